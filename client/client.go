@@ -80,6 +80,7 @@ package client // import "go.opentelemetry.io/collector/client"
 import (
 	"context"
 	"net"
+	"sort"
 	"strings"
 )
 
@@ -165,3 +166,16 @@ func (m Metadata) Get(key string) []string {
 
 	return ret
 }
+
+// Keys returns the keys of the metadata, lowercased and sorted.
+func (m Metadata) Keys() []string {
+	if len(m.data) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m.data))
+	for k := range m.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -97,8 +97,10 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...teleme
 		metric.WithUnit("By"),
 	)
 	errs = errors.Join(errs, err)
-	_, err = builder.meter.RegisterCallback(builder.observeProcessRuntimeTotalAllocBytes, builder.ProcessRuntimeTotalAllocBytes)
-	errs = errors.Join(errs, err)
+	if builder.observeProcessRuntimeTotalAllocBytes != nil {
+		_, err = builder.meter.RegisterCallback(builder.observeProcessRuntimeTotalAllocBytes, builder.ProcessRuntimeTotalAllocBytes)
+		errs = errors.Join(errs, err)
+	}
 	builder.RequestDuration, err = builder.meter.Float64Histogram(
 		"otelcol_request_duration",
 		metric.WithDescription("Duration of request"),
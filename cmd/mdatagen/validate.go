@@ -95,6 +95,7 @@ func (s *Status) validateStability() error {
 			if c != "metrics" &&
 				c != "traces" &&
 				c != "logs" &&
+				c != "profiles" &&
 				c != "traces_to_traces" &&
 				c != "traces_to_metrics" &&
 				c != "traces_to_logs" &&
@@ -10,12 +10,17 @@ import (
 	"path/filepath"
 	"strings"
 
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
 	"go.opentelemetry.io/collector/confmap"
 )
 
 const schemeName = "file"
 
-type provider struct{}
+type provider struct {
+	logger *zap.Logger
+}
 
 // NewFactory returns a factory for a confmap.Provider that reads the configuration from a file.
 //
@@ -32,26 +37,40 @@ type provider struct{}
 // `file:/path/to/file` - absolute path (unix, windows)
 // `file:c:/path/to/file` - absolute path including drive-letter (windows)
 // `file:c:\path\to\file` - absolute path including drive-letter (windows)
+//
+// When the "confmap.fileProviderWatch" feature gate is enabled, the Provider watches the file, and notifies the
+// changes of its content, so that the configuration is reloaded. The file system events are used, falling back to
+// polling the file when they are not available. The file being replaced, by an editor or by swapping a symlink as
+// Kubernetes does for the mounted ConfigMaps, is detected.
 func NewFactory() confmap.ProviderFactory {
 	return confmap.NewProviderFactory(newProvider)
 }
 
-func newProvider(confmap.ProviderSettings) confmap.Provider {
-	return &provider{}
+func newProvider(set confmap.ProviderSettings) confmap.Provider {
+	return &provider{logger: set.Logger}
 }
 
-func (fmp *provider) Retrieve(_ context.Context, uri string, _ confmap.WatcherFunc) (*confmap.Retrieved, error) {
+func (fmp *provider) Retrieve(ctx context.Context, uri string, watcher confmap.WatcherFunc) (*confmap.Retrieved, error) {
 	if !strings.HasPrefix(uri, schemeName+":") {
 		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, schemeName)
 	}
 
 	// Clean the path before using it.
-	content, err := os.ReadFile(filepath.Clean(uri[len(schemeName)+1:]))
+	path := filepath.Clean(uri[len(schemeName)+1:])
+	content, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read the file %v: %w", uri, err)
 	}
 
-	return confmap.NewRetrievedFromYAML(content)
+	if watcher == nil || !watchFeatureGate.IsEnabled() {
+		return confmap.NewRetrievedFromYAML(content)
+	}
+	fw := newFileWatcher(path, content, watcher, fmp.logger)
+	ret, err := confmap.NewRetrievedFromYAML(content, confmap.WithRetrievedClose(fw.close))
+	if err != nil {
+		return nil, multierr.Append(err, fw.close(ctx))
+	}
+	return ret, nil
 }
 
 func (*provider) Scheme() string {
@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileprovider // import "go.opentelemetry.io/collector/confmap/provider/fileprovider"
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/featuregate"
+)
+
+var watchFeatureGate = featuregate.GlobalRegistry().MustRegister("confmap.fileProviderWatch",
+	featuregate.StageAlpha,
+	featuregate.WithRegisterFromVersion("v0.107.0"),
+	featuregate.WithRegisterDescription("When enabled, the file provider watches the configuration files, and notifies the collector to reload its configuration when they change."))
+
+var (
+	// debounceDelay is the delay without file system events after which the file is checked for changes,
+	// so that the intermediate states of the editors' rename-and-write sequences are not reported.
+	debounceDelay = 500 * time.Millisecond
+	// pollInterval is the interval at which the file is checked for changes, when it cannot be watched.
+	pollInterval = 5 * time.Second
+)
+
+// fileWatcher watches a file, and calls its confmap.WatcherFunc once its content changes.
+type fileWatcher struct {
+	path     string
+	content  []byte
+	onChange confmap.WatcherFunc
+	logger   *zap.Logger
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newFileWatcher starts watching the file at the path, whose retrieved content is given. The file system events
+// are used to detect the changes, falling back to polling the file if the events are not available.
+func newFileWatcher(path string, content []byte, onChange confmap.WatcherFunc, logger *zap.Logger) *fileWatcher {
+	fw := &fileWatcher{
+		path:     path,
+		content:  content,
+		onChange: onChange,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = fw.addDirs(watcher); err != nil {
+			_ = watcher.Close()
+		}
+	}
+
+	fw.wg.Add(1)
+	if err != nil {
+		logger.Warn("Failed to watch the configuration file, polling it instead", zap.String("path", path), zap.Error(err))
+		go fw.poll()
+	} else {
+		go fw.watch(watcher)
+	}
+	return fw
+}
+
+// addDirs watches the directory of the file, and the directory of its target if it is a symlink. The directories
+// are watched rather than the file, so that the file being replaced by a rename or a symlink swap, as Kubernetes
+// does for the mounted ConfigMaps, is detected.
+func (fw *fileWatcher) addDirs(watcher *fsnotify.Watcher) error {
+	if err := watcher.Add(filepath.Dir(fw.path)); err != nil {
+		return err
+	}
+	target, err := filepath.EvalSymlinks(fw.path)
+	if err != nil || filepath.Dir(target) == filepath.Dir(fw.path) {
+		// The file may be missing while being replaced, its target is watched on the next events.
+		return nil
+	}
+	return watcher.Add(filepath.Dir(target))
+}
+
+// isRelevant reports whether the event may change the content of the file: the file itself changed, a symlink it
+// may resolve through was swapped, or the directory of its target changed.
+func (fw *fileWatcher) isRelevant(event fsnotify.Event) bool {
+	name := filepath.Clean(event.Name)
+	if name == fw.path {
+		return true
+	}
+	if info, err := os.Lstat(name); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return true
+	}
+	target, err := filepath.EvalSymlinks(fw.path)
+	return err == nil && filepath.Dir(name) == filepath.Dir(target)
+}
+
+func (fw *fileWatcher) watch(watcher *fsnotify.Watcher) {
+	defer fw.wg.Done()
+	defer watcher.Close()
+
+	// The file is checked once no event happened for the debounce delay.
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-fw.done:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if fw.isRelevant(event) {
+				debounce = time.After(debounceDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			fw.logger.Warn("Error watching the configuration file", zap.String("path", fw.path), zap.Error(err))
+		case <-debounce:
+			debounce = nil
+			if fw.changed() {
+				fw.onChange(&confmap.ChangeEvent{})
+				return
+			}
+			// The target of the file may have been swapped.
+			if err := fw.addDirs(watcher); err != nil {
+				fw.logger.Warn("Failed to watch the configuration file", zap.String("path", fw.path), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (fw *fileWatcher) poll() {
+	defer fw.wg.Done()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-fw.done:
+			return
+		case <-ticker.C:
+			if fw.changed() {
+				fw.onChange(&confmap.ChangeEvent{})
+				return
+			}
+		}
+	}
+}
+
+// changed reports whether the content of the file changed since it was retrieved. A missing file is reported as
+// unchanged, as it may be in the middle of being replaced.
+func (fw *fileWatcher) changed() bool {
+	content, err := os.ReadFile(fw.path)
+	return err == nil && !bytes.Equal(content, fw.content)
+}
+
+// close stops watching the file.
+func (fw *fileWatcher) close(context.Context) error {
+	close(fw.done)
+	fw.wg.Wait()
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileprovider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/featuregate"
+)
+
+func enableWatch(t *testing.T) {
+	require.NoError(t, featuregate.GlobalRegistry().Set(watchFeatureGate.ID(), true))
+	prevDebounceDelay := debounceDelay
+	debounceDelay = 50 * time.Millisecond
+	t.Cleanup(func() {
+		require.NoError(t, featuregate.GlobalRegistry().Set(watchFeatureGate.ID(), false))
+		debounceDelay = prevDebounceDelay
+	})
+}
+
+// retrieveWatched retrieves the file, and returns the channel of the change events of its watcher.
+func retrieveWatched(t *testing.T, path string) (*confmap.Retrieved, <-chan *confmap.ChangeEvent) {
+	events := make(chan *confmap.ChangeEvent, 10)
+	fp := createProvider()
+	ret, err := fp.Retrieve(context.Background(), fileSchemePrefix+path, func(event *confmap.ChangeEvent) {
+		events <- event
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, ret.Close(context.Background()))
+		assert.NoError(t, fp.Shutdown(context.Background()))
+	})
+	return ret, events
+}
+
+func assertChangeEvent(t *testing.T, events <-chan *confmap.ChangeEvent) {
+	select {
+	case event := <-events:
+		assert.NoError(t, event.Error)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "no change event")
+	}
+	// The changes are reported once.
+	assertNoChangeEvent(t, events)
+}
+
+func assertNoChangeEvent(t *testing.T, events <-chan *confmap.ChangeEvent) {
+	select {
+	case <-events:
+		assert.Fail(t, "unexpected change event")
+	case <-time.After(4 * debounceDelay):
+	}
+}
+
+func writeConfig(t *testing.T, path string, endpoint string) {
+	require.NoError(t, os.WriteFile(path, []byte("exporters:\n  otlp:\n    endpoint: "+endpoint+"\n"), 0600))
+}
+
+func TestWatchDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "localhost:4317")
+	_, events := retrieveWatched(t, path)
+
+	writeConfig(t, path, "localhost:4318")
+	assertNoChangeEvent(t, events)
+}
+
+func TestWatchWrite(t *testing.T) {
+	enableWatch(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "localhost:4317")
+	_, events := retrieveWatched(t, path)
+
+	// Writing the same content is not a change.
+	writeConfig(t, path, "localhost:4317")
+	assertNoChangeEvent(t, events)
+
+	writeConfig(t, path, "localhost:4318")
+	assertChangeEvent(t, events)
+}
+
+func TestWatchRename(t *testing.T) {
+	enableWatch(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeConfig(t, path, "localhost:4317")
+	_, events := retrieveWatched(t, path)
+
+	// Editors write to a temporary file, and rename it to the file, which is missing in between.
+	require.NoError(t, os.Rename(path, filepath.Join(dir, "config.yaml~")))
+	tmpPath := filepath.Join(dir, ".config.yaml.swp")
+	writeConfig(t, tmpPath, "localhost:4318")
+	require.NoError(t, os.Rename(tmpPath, path))
+	assertChangeEvent(t, events)
+}
+
+func TestWatchSymlinkSwap(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on windows")
+	}
+	enableWatch(t)
+
+	// Kubernetes mounts the ConfigMaps as symlinks to a "..data" symlink to the directory of the current version,
+	// and swaps the "..data" symlink to update them.
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..v1"), 0700))
+	writeConfig(t, filepath.Join(dir, "..v1", "config.yaml"), "localhost:4317")
+	require.NoError(t, os.Symlink("..v1", filepath.Join(dir, "..data")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "config.yaml"), filepath.Join(dir, "config.yaml")))
+	_, events := retrieveWatched(t, filepath.Join(dir, "config.yaml"))
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..v2"), 0700))
+	writeConfig(t, filepath.Join(dir, "..v2", "config.yaml"), "localhost:4318")
+	require.NoError(t, os.Symlink("..v2", filepath.Join(dir, "..data_tmp")))
+	require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "..v1")))
+	assertChangeEvent(t, events)
+}
+
+func TestPoll(t *testing.T) {
+	prevPollInterval := pollInterval
+	pollInterval = 50 * time.Millisecond
+	t.Cleanup(func() { pollInterval = prevPollInterval })
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "localhost:4317")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	events := make(chan *confmap.ChangeEvent, 10)
+	fw := &fileWatcher{
+		path:     path,
+		content:  content,
+		onChange: func(event *confmap.ChangeEvent) { events <- event },
+		logger:   zap.NewNop(),
+		done:     make(chan struct{}),
+	}
+	fw.wg.Add(1)
+	go fw.poll()
+	t.Cleanup(func() { assert.NoError(t, fw.close(context.Background())) })
+
+	// The file missing while being replaced is not a change.
+	require.NoError(t, os.Remove(path))
+	assertNoChangeEvent(t, events)
+
+	writeConfig(t, path, "localhost:4318")
+	assertChangeEvent(t, events)
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -27,6 +28,8 @@ type Resolver struct {
 
 	closers []CloseFunc
 	watcher chan error
+	// watcherMu serializes the watchers sending to the watcher channel, so that a pending event can be replaced.
+	watcherMu sync.Mutex
 }
 
 // ResolverSettings are the settings to configure the behavior of the Resolver.
@@ -236,19 +239,41 @@ func (mr *Resolver) Watch() <-chan error {
 //
 // Should never be called concurrently with itself or Get.
 func (mr *Resolver) Shutdown(ctx context.Context) error {
-	close(mr.watcher)
-
+	// Close the retrieved configurations first, so that their watchers are stopped when the Watch channel is closed.
 	var errs error
 	errs = multierr.Append(errs, mr.closeIfNeeded(ctx))
 	for _, p := range mr.providers {
 		errs = multierr.Append(errs, p.Shutdown(ctx))
 	}
 
+	mr.watcherMu.Lock()
+	close(mr.watcher)
+	mr.watcherMu.Unlock()
 	return errs
 }
 
 func (mr *Resolver) onChange(event *ChangeEvent) {
-	mr.watcher <- event.Error
+	mr.watcherMu.Lock()
+	defer mr.watcherMu.Unlock()
+
+	// A pending event already triggers a new resolution, so the watchers never block on the following ones.
+	select {
+	case mr.watcher <- event.Error:
+		return
+	default:
+	}
+	if event.Error == nil {
+		return
+	}
+	// The error must not be lost behind a pending change, so the pending event is upgraded to the error.
+	err := event.Error
+	select {
+	case pending := <-mr.watcher:
+		err = multierr.Append(pending, err)
+	default:
+	}
+	// The channel is empty, and only sent to while holding the lock.
+	mr.watcher <- err
 }
 
 func (mr *Resolver) closeIfNeeded(ctx context.Context) error {
@@ -398,6 +398,40 @@ func TestResolverShutdownClosesWatch(t *testing.T) {
 	watcherWG.Wait()
 }
 
+func TestResolverWatchErrorAfterPendingChange(t *testing.T) {
+	var watcher WatcherFunc
+	provider := newFakeProvider("file", func(_ context.Context, uri string, w WatcherFunc) (*Retrieved, error) {
+		watcher = w
+		return NewRetrieved(newConfFromFile(t, uri[5:]))
+	})
+	resolver, err := NewResolver(ResolverSettings{
+		URIs:              []string{filepath.Join("testdata", "config.yaml")},
+		ProviderFactories: []ProviderFactory{provider},
+	})
+	require.NoError(t, err)
+	_, err = resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, watcher)
+
+	errA := errors.New("first watch error")
+	errB := errors.New("second watch error")
+	watcher(&ChangeEvent{})
+	watcher(&ChangeEvent{Error: errA})
+	watcher(&ChangeEvent{})
+	watcher(&ChangeEvent{Error: errB})
+
+	// The pending change is upgraded to the errors, which are not dropped.
+	errW := <-resolver.Watch()
+	require.ErrorIs(t, errW, errA)
+	require.ErrorIs(t, errW, errB)
+	select {
+	case errW = <-resolver.Watch():
+		t.Fatalf("unexpected pending watch event: %v", errW)
+	default:
+	}
+	require.NoError(t, resolver.Shutdown(context.Background()))
+}
+
 func TestProvidesDefaultLogger(t *testing.T) {
 	factory, provider := newObservableFileProvider(t)
 	_, err := NewResolver(ResolverSettings{
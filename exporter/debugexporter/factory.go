@@ -16,6 +16,7 @@ import (
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/debugexporter/internal/metadata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/exporterprofiles"
 	"go.opentelemetry.io/collector/exporter/internal/otlptext"
 )
 
@@ -35,6 +36,7 @@ func NewFactory() exporter.Factory {
 		exporter.WithTraces(createTracesExporter, metadata.TracesStability),
 		exporter.WithMetrics(createMetricsExporter, metadata.MetricsStability),
 		exporter.WithLogs(createLogsExporter, metadata.LogsStability),
+		exporterprofiles.WithProfiles(createProfilesExporter, metadata.ProfilesStability),
 	)
 }
 
@@ -83,6 +85,18 @@ func createLogsExporter(ctx context.Context, set exporter.Settings, config compo
 	)
 }
 
+func createProfilesExporter(ctx context.Context, set exporter.Settings, config component.Config) (exporterprofiles.Profiles, error) {
+	cfg := config.(*Config)
+	exporterLogger := createLogger(cfg, set.TelemetrySettings.Logger)
+	debugExporter := newDebugExporter(exporterLogger, cfg.Verbosity)
+	return exporterhelper.NewProfilesExporter(ctx, set, config,
+		debugExporter.pushProfiles,
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
+		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
+		exporterhelper.WithShutdown(otlptext.LoggerSync(exporterLogger)),
+	)
+}
+
 func createLogger(cfg *Config, logger *zap.Logger) *zap.Logger {
 	var exporterLogger *zap.Logger
 	if cfg.UseInternalLogger {
@@ -46,3 +46,12 @@ func TestCreateLogsExporter(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, te)
 }
+
+func TestCreateProfilesExporter(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	te, err := factory.CreateProfilesExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, te)
+}
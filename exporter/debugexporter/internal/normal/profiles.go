@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package normal // import "go.opentelemetry.io/collector/exporter/debugexporter/internal/normal"
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pprofile"
+)
+
+type normalProfilesMarshaler struct{}
+
+// Ensure normalProfilesMarshaler implements interface pprofile.Marshaler
+var _ pprofile.Marshaler = normalProfilesMarshaler{}
+
+// NewNormalProfilesMarshaler returns a pprofile.Marshaler for normal verbosity. It writes one line of text per sample,
+// with its stack in the folded format, the root function first, followed by its values.
+func NewNormalProfilesMarshaler() pprofile.Marshaler {
+	return normalProfilesMarshaler{}
+}
+
+func (normalProfilesMarshaler) MarshalProfiles(pd pprofile.Profiles) ([]byte, error) {
+	var buffer bytes.Buffer
+	for i := 0; i < pd.ResourceProfiles().Len(); i++ {
+		resourceProfiles := pd.ResourceProfiles().At(i)
+		for j := 0; j < resourceProfiles.ScopeProfiles().Len(); j++ {
+			scopeProfiles := resourceProfiles.ScopeProfiles().At(j)
+			for k := 0; k < scopeProfiles.Profiles().Len(); k++ {
+				profile := scopeProfiles.Profiles().At(k).Profile()
+				for l := 0; l < profile.Sample().Len(); l++ {
+					sample := profile.Sample().At(l)
+
+					if stack := foldedStack(profile, sample); stack != "" {
+						buffer.WriteString(stack)
+						buffer.WriteString(" ")
+					}
+					buffer.WriteString(strings.Join(sampleValues(profile, sample), " "))
+
+					buffer.WriteString("\n")
+				}
+			}
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+// foldedStack returns the function names of the stack of the sample separated by semicolons, the root first.
+// The locations without function are written as their address.
+func foldedStack(profile pprofile.Profile, sample pprofile.Sample) string {
+	var locationIndices []uint64
+	if sample.LocationIndex().Len() > 0 {
+		locationIndices = sample.LocationIndex().AsRaw()
+	} else {
+		for i := sample.LocationsStartIndex(); i < sample.LocationsStartIndex()+sample.LocationsLength() && i < uint64(profile.LocationIndices().Len()); i++ {
+			locationIndices = append(locationIndices, uint64(profile.LocationIndices().At(int(i))))
+		}
+	}
+
+	var frames []string
+	// The locations and their lines are ordered from the leaf to the root.
+	for i := len(locationIndices) - 1; i >= 0; i-- {
+		if locationIndices[i] >= uint64(profile.Location().Len()) {
+			frames = append(frames, "?")
+			continue
+		}
+		location := profile.Location().At(int(locationIndices[i]))
+		if location.Line().Len() == 0 {
+			frames = append(frames, fmt.Sprintf("0x%x", location.Address()))
+			continue
+		}
+		for j := location.Line().Len() - 1; j >= 0; j-- {
+			functionIndex := location.Line().At(j).FunctionIndex()
+			if functionIndex >= uint64(profile.Function().Len()) {
+				frames = append(frames, "?")
+				continue
+			}
+			frames = append(frames, stringAt(profile, profile.Function().At(int(functionIndex)).Name()))
+		}
+	}
+	return strings.Join(frames, ";")
+}
+
+// sampleValues returns a slice of strings in the form "type=value", or only the value if it has no sample type.
+func sampleValues(profile pprofile.Profile, sample pprofile.Sample) []string {
+	values := make([]string, sample.Value().Len())
+	for i := 0; i < sample.Value().Len(); i++ {
+		value := strconv.FormatInt(sample.Value().At(i), 10)
+		if i < profile.SampleType().Len() {
+			if sampleType := stringAt(profile, profile.SampleType().At(i).Type()); sampleType != "" {
+				value = sampleType + "=" + value
+			}
+		}
+		values[i] = value
+	}
+	return values
+}
+
+// stringAt returns the string at the index of the string table of the profile, or an empty string if out of range.
+func stringAt(profile pprofile.Profile, idx int64) string {
+	if idx < 0 || idx >= int64(profile.StringTable().Len()) {
+		return ""
+	}
+	return profile.StringTable().At(int(idx))
+}
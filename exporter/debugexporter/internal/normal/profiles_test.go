@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package normal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/pdata/pprofile"
+)
+
+func TestMarshalProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    pprofile.Profiles
+		expected string
+	}{
+		{
+			name:     "empty profiles",
+			input:    pprofile.NewProfiles(),
+			expected: "",
+		},
+		{
+			name: "one sample without stack",
+			input: func() pprofile.Profiles {
+				profiles := pprofile.NewProfiles()
+				profile := profiles.ResourceProfiles().AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty().Profile()
+				profile.Sample().AppendEmpty().Value().Append(4)
+				return profiles
+			}(),
+			expected: `4
+`,
+		},
+		{
+			name: "two samples with stacks",
+			input: func() pprofile.Profiles {
+				profiles := pprofile.NewProfiles()
+				profile := profiles.ResourceProfiles().AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty().Profile()
+				profile.StringTable().FromRaw([]string{"", "samples", "count", "cpu", "nanoseconds", "main", "handle"})
+				sampleType := profile.SampleType().AppendEmpty()
+				sampleType.SetType(1)
+				sampleType.SetUnit(2)
+				sampleType = profile.SampleType().AppendEmpty()
+				sampleType.SetType(3)
+				sampleType.SetUnit(4)
+				profile.Function().AppendEmpty().SetName(5)
+				profile.Function().AppendEmpty().SetName(6)
+				location := profile.Location().AppendEmpty()
+				location.Line().AppendEmpty().SetFunctionIndex(1)
+				location.Line().AppendEmpty().SetFunctionIndex(0)
+				profile.Location().AppendEmpty().SetAddress(0x2000)
+				profile.LocationIndices().FromRaw([]int64{1, 0})
+
+				sample := profile.Sample().AppendEmpty()
+				sample.Value().FromRaw([]int64{1, 10000000})
+				sample.SetLocationsLength(2)
+				sample = profile.Sample().AppendEmpty()
+				sample.Value().FromRaw([]int64{3, 30000000})
+				sample.LocationIndex().FromRaw([]uint64{0})
+				return profiles
+			}(),
+			expected: `main;handle;0x2000 samples=1 cpu=10000000
+main;handle samples=3 cpu=30000000
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewNormalProfilesMarshaler().MarshalProfiles(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(output))
+		})
+	}
+}
@@ -5,11 +5,13 @@ package exporterbatcher // import "go.opentelemetry.io/collector/exporter/export
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
-// Config defines a configuration for batching requests based on a timeout and a minimum number of items.
-// MaxSizeItems defines batch splitting functionality if it's more than zero.
+// Config defines a configuration for batching requests based on a timeout and a minimum number of items or bytes.
+// MaxSizeItems and MaxSizeBytes define batch splitting functionality if any of them is more than zero.
 // Experimental: This API is at the early stage of development and may change without backward compatibility
 // until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
 type Config struct {
@@ -21,6 +23,18 @@ type Config struct {
 
 	MinSizeConfig `mapstructure:",squash"`
 	MaxSizeConfig `mapstructure:",squash"`
+
+	// MetadataKeys is a list of client.Metadata keys used to form distinct batches. If empty, all the requests are
+	// merged into a single batch. Otherwise, one batch is formed per distinct combination of values of the listed
+	// metadata keys, so the requests with different values, e.g. of different tenants, are never merged together.
+	// Empty value and unset metadata are treated as distinct cases.
+	// Entries are case-insensitive. Duplicated entries trigger a validation error.
+	MetadataKeys []string `mapstructure:"metadata_keys"`
+
+	// MetadataCardinalityLimit is the maximum number of distinct combinations of values of the MetadataKeys batched
+	// at the same time. The requests of additional combinations are rejected with a permanent error.
+	// Setting this value to zero disables the limit.
+	MetadataCardinalityLimit uint32 `mapstructure:"metadata_cardinality_limit"`
 }
 
 // MinSizeConfig defines the configuration for the minimum number of items in a batch.
@@ -31,6 +45,11 @@ type MinSizeConfig struct {
 	// sent regardless of the timeout. There is no guarantee that the batch size always greater than this value.
 	// This option requires the Request to implement RequestItemsCounter interface. Otherwise, it will be ignored.
 	MinSizeItems int `mapstructure:"min_size_items"`
+
+	// MinSizeBytes is the size in bytes, once proto-marshaled for OTLP, at which the batch should be sent regardless
+	// of the timeout. Setting this value to zero disables it.
+	// This option requires the Request to implement the `BytesSize() int` method. Otherwise, it will be ignored.
+	MinSizeBytes int `mapstructure:"min_size_bytes"`
 }
 
 // MaxSizeConfig defines the configuration for the maximum number of items in a batch.
@@ -41,6 +60,12 @@ type MaxSizeConfig struct {
 	// If the batch size exceeds this value, it will be broken up into smaller batches if possible.
 	// Setting this value to zero disables the maximum size limit.
 	MaxSizeItems int `mapstructure:"max_size_items"`
+
+	// MaxSizeBytes is the maximum size in bytes of the batch, once proto-marshaled for OTLP.
+	// If the batch size exceeds this value, it will be broken up into smaller batches if possible. An item bigger than
+	// this value on its own is sent alone in a batch.
+	// Setting this value to zero disables the maximum size limit.
+	MaxSizeBytes int `mapstructure:"max_size_bytes"`
 }
 
 func (c Config) Validate() error {
@@ -53,9 +78,26 @@ func (c Config) Validate() error {
 	if c.MaxSizeItems != 0 && c.MaxSizeItems < c.MinSizeItems {
 		return errors.New("max_size_items must be greater than or equal to min_size_items")
 	}
+	if c.MinSizeBytes < 0 {
+		return errors.New("min_size_bytes must be greater than or equal to zero")
+	}
+	if c.MaxSizeBytes < 0 {
+		return errors.New("max_size_bytes must be greater than or equal to zero")
+	}
+	if c.MaxSizeBytes != 0 && c.MaxSizeBytes < c.MinSizeBytes {
+		return errors.New("max_size_bytes must be greater than or equal to min_size_bytes")
+	}
 	if c.FlushTimeout <= 0 {
 		return errors.New("timeout must be greater than zero")
 	}
+	uniq := map[string]bool{}
+	for _, k := range c.MetadataKeys {
+		l := strings.ToLower(k)
+		if uniq[l] {
+			return fmt.Errorf("duplicate entry in metadata_keys: %q (case-insensitive)", l)
+		}
+		uniq[l] = true
+	}
 	return nil
 }
 
@@ -66,5 +108,6 @@ func NewDefaultConfig() Config {
 		MinSizeConfig: MinSizeConfig{
 			MinSizeItems: 8192,
 		},
+		MetadataCardinalityLimit: 1000,
 	}
 }
@@ -27,4 +27,21 @@ func TestConfig_Validate(t *testing.T) {
 	cfg.MaxSizeItems = 20000
 	cfg.MinSizeItems = 20001
 	assert.EqualError(t, cfg.Validate(), "max_size_items must be greater than or equal to min_size_items")
+
+	cfg = NewDefaultConfig()
+	cfg.MinSizeBytes = -1
+	assert.EqualError(t, cfg.Validate(), "min_size_bytes must be greater than or equal to zero")
+
+	cfg = NewDefaultConfig()
+	cfg.MaxSizeBytes = -1
+	assert.EqualError(t, cfg.Validate(), "max_size_bytes must be greater than or equal to zero")
+
+	cfg = NewDefaultConfig()
+	cfg.MinSizeBytes = 4 << 20
+	cfg.MaxSizeBytes = 1 << 20
+	assert.EqualError(t, cfg.Validate(), "max_size_bytes must be greater than or equal to min_size_bytes")
+
+	cfg = NewDefaultConfig()
+	cfg.MetadataKeys = []string{"X-Tenant", "x-tenant"}
+	assert.EqualError(t, cfg.Validate(), `duplicate entry in metadata_keys: "x-tenant" (case-insensitive)`)
 }
@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"math"
+	"math/bits"
+
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+)
+
+// batchCapacity is the room left in a batch built by the OTLP merge-split functions, in number of items and in bytes
+// once proto-marshaled. Every element of the batch is a length-delimited field of its parent message, so it takes
+// the room of its tag and length on top of its own size.
+type batchCapacity struct {
+	items int
+	// bytes is only tracked if limitBytes is true, so the sizes are not computed if the batch is not limited in bytes.
+	bytes      int
+	limitBytes bool
+}
+
+func newBatchCapacity(cfg exporterbatcher.MaxSizeConfig) batchCapacity {
+	c := batchCapacity{items: cfg.MaxSizeItems, bytes: cfg.MaxSizeBytes, limitBytes: cfg.MaxSizeBytes > 0}
+	if c.items <= 0 {
+		c.items = math.MaxInt
+	}
+	return c
+}
+
+// addAll takes the room of a whole request if it fits in the batch. The fields of the request are appended
+// to the batch as they are, so its size is taken as is.
+func (c *batchCapacity) addAll(items int, size func() int) bool {
+	if items > c.items {
+		return false
+	}
+	if c.limitBytes {
+		s := size()
+		if s > c.bytes {
+			return false
+		}
+		c.bytes -= s
+	}
+	c.items -= items
+	return true
+}
+
+// add takes the room of an element of the given number of items and size if it fits in the batch.
+func (c *batchCapacity) add(items int, size func() int) bool {
+	if items > c.items {
+		return false
+	}
+	if c.limitBytes {
+		s := protoFieldSize(size())
+		if s > c.bytes {
+			return false
+		}
+		c.bytes -= s
+	}
+	c.items -= items
+	return true
+}
+
+// nested returns the room left for the elements of a message added to the batch, once its tag, its length and its
+// other fields, of the given header size, are accounted for. The length is accounted for with its maximum size given
+// the room left, so the message fits in the batch whatever its final size.
+func (c *batchCapacity) nested(headerSize func() int) batchCapacity {
+	n := *c
+	if c.limitBytes {
+		n.bytes = c.bytes - 1 - sovSize(c.bytes) - headerSize()
+	}
+	return n
+}
+
+// takeNested takes the room of a message filled with the elements added to the nested capacity.
+func (c *batchCapacity) takeNested(n batchCapacity, size func() int) {
+	if c.limitBytes {
+		c.bytes -= protoFieldSize(size())
+	}
+	c.items = n.items
+}
+
+// protoFieldSize returns the size of a length-delimited field holding a message of the given size. The fields
+// holding the elements of the OTLP messages have numbers lower than 16, so their tag takes a single byte.
+func protoFieldSize(size int) int {
+	return 1 + sovSize(size) + size
+}
+
+// sovSize returns the size of the varint encoding of x.
+func sovSize(x int) int {
+	return (bits.Len64(uint64(x)|1) + 6) / 7
+}
@@ -5,20 +5,34 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
 )
 
+// bytesSizer gives the size in bytes of the requests implementing the `BytesSize() int` method, zero otherwise.
+var bytesSizer = &queue.BytesSizer[Request]{}
+
+// errTooManyBatches is returned when the MetadataCardinalityLimit has been reached.
+var errTooManyBatches = consumererror.NewPermanent(errors.New("too many batch metadata-value combinations"))
+
 // batchSender is a component that places requests into batches before passing them to the downstream senders.
-// Batches are sent out with any of the following conditions:
-// - batch size reaches cfg.MinSizeItems
+// If metadata keys are configured, the requests are placed into a separate batch per distinct combination
+// of the metadata values. Batches are sent out with any of the following conditions:
+// - batch size reaches cfg.MinSizeItems or cfg.MinSizeBytes
 // - cfg.FlushTimeout is elapsed since the timestamp when the previous batch was sent out.
 // - concurrencyLimit is reached.
 type batchSender struct {
@@ -26,16 +40,20 @@ type batchSender struct {
 	cfg            exporterbatcher.Config
 	mergeFunc      exporterbatcher.BatchMergeFunc[Request]
 	mergeSplitFunc exporterbatcher.BatchMergeSplitFunc[Request]
+	// metadataKeys is the lower-cased and sorted list of cfg.MetadataKeys.
+	metadataKeys []string
 
 	// concurrencyLimit is the maximum number of goroutines that can be blocked by the batcher.
 	// If this number is reached and all the goroutines are busy, the batch will be sent right away.
 	// Populated from the number of queue consumers if queue is enabled.
 	concurrencyLimit int64
-	activeRequests   atomic.Int64
+	// limiter, if set, gives the concurrency limit instead of concurrencyLimit. It's populated if the queue
+	// adjusts the number of requests exported concurrently.
+	limiter        *queue.AdaptiveLimiter
+	activeRequests atomic.Int64
 
-	mu          sync.Mutex
-	activeBatch *batch
-	lastFlushed time.Time
+	mu     sync.Mutex
+	shards map[attribute.Distinct]*batchShard
 
 	logger *zap.Logger
 
@@ -47,9 +65,16 @@ type batchSender struct {
 // newBatchSender returns a new batch consumer component.
 func newBatchSender(cfg exporterbatcher.Config, set exporter.Settings,
 	mf exporterbatcher.BatchMergeFunc[Request], msf exporterbatcher.BatchMergeSplitFunc[Request]) *batchSender {
+	// use lower-case, to be consistent with http/2 headers.
+	mks := make([]string, len(cfg.MetadataKeys))
+	for i, k := range cfg.MetadataKeys {
+		mks[i] = strings.ToLower(k)
+	}
+	sort.Strings(mks)
 	bs := &batchSender{
-		activeBatch:        newEmptyBatch(),
 		cfg:                cfg,
+		metadataKeys:       mks,
+		shards:             map[attribute.Distinct]*batchShard{},
 		logger:             set.Logger,
 		mergeFunc:          mf,
 		mergeSplitFunc:     msf,
@@ -71,8 +96,10 @@ func (bs *batchSender) Start(_ context.Context, _ component.Host) error {
 				// This loop will handle that case.
 				for bs.activeRequests.Load() > 0 {
 					bs.mu.Lock()
-					if bs.activeBatch.request != nil {
-						bs.exportActiveBatch()
+					for _, shard := range bs.shards {
+						if shard.activeBatch.request != nil {
+							bs.exportActiveBatch(shard)
+						}
 					}
 					bs.mu.Unlock()
 				}
@@ -84,12 +111,16 @@ func (bs *batchSender) Start(_ context.Context, _ component.Host) error {
 			case <-timer.C:
 				bs.mu.Lock()
 				nextFlush := bs.cfg.FlushTimeout
-				if bs.activeBatch.request != nil {
-					sinceLastFlush := time.Since(bs.lastFlushed)
-					if sinceLastFlush >= bs.cfg.FlushTimeout {
-						bs.exportActiveBatch()
-					} else {
-						nextFlush = bs.cfg.FlushTimeout - sinceLastFlush
+				for key, shard := range bs.shards {
+					sinceLastFlush := time.Since(shard.lastFlushed)
+					switch {
+					case sinceLastFlush < bs.cfg.FlushTimeout:
+						nextFlush = min(nextFlush, bs.cfg.FlushTimeout-sinceLastFlush)
+					case shard.activeBatch.request != nil:
+						bs.exportActiveBatch(shard)
+					default:
+						// Forget the idle shards, so they don't count towards the cardinality limit.
+						delete(bs.shards, key)
 					}
 				}
 				bs.mu.Unlock()
@@ -110,6 +141,9 @@ type batch struct {
 	// requestsBlocked is the number of requests blocked in this batch
 	// that can be immediately released from activeRequests when batch sending completes.
 	requestsBlocked int64
+	// bytes is the running size in bytes of the requests merged into this batch.
+	// It's only tracked if cfg.MinSizeBytes is set.
+	bytes int64
 }
 
 func newEmptyBatch() *batch {
@@ -119,24 +153,74 @@ func newEmptyBatch() *batch {
 	}
 }
 
-// exportActiveBatch exports the active batch asynchronously and replaces it with a new one.
+// batchShard holds the active batch of the requests sharing the same values of the configured metadata keys.
+type batchShard struct {
+	activeBatch *batch
+	lastFlushed time.Time
+}
+
+// getShard returns the shard of the requests sharing the metadata values of the given context,
+// creating it unless the cardinality limit is reached. Caller must hold the lock.
+func (bs *batchSender) getShard(ctx context.Context) (*batchShard, error) {
+	var key attribute.Distinct
+	if len(bs.metadataKeys) > 0 {
+		info := client.FromContext(ctx)
+		attrs := make([]attribute.KeyValue, 0, len(bs.metadataKeys))
+		for _, k := range bs.metadataKeys {
+			vs := info.Metadata.Get(k)
+			if len(vs) == 1 {
+				attrs = append(attrs, attribute.String(k, vs[0]))
+			} else {
+				attrs = append(attrs, attribute.StringSlice(k, vs))
+			}
+		}
+		aset := attribute.NewSet(attrs...)
+		key = aset.Equivalent()
+	}
+
+	shard, ok := bs.shards[key]
+	if !ok {
+		if bs.cfg.MetadataCardinalityLimit != 0 && len(bs.shards) >= int(bs.cfg.MetadataCardinalityLimit) {
+			return nil, errTooManyBatches
+		}
+		shard = &batchShard{activeBatch: newEmptyBatch(), lastFlushed: time.Now()}
+		bs.shards[key] = shard
+	}
+	return shard, nil
+}
+
+// exportActiveBatch exports the active batch of the shard asynchronously and replaces it with a new one.
 // Caller must hold the lock.
-func (bs *batchSender) exportActiveBatch() {
+func (bs *batchSender) exportActiveBatch(shard *batchShard) {
 	go func(b *batch) {
 		b.err = bs.nextSender.send(b.ctx, b.request)
 		close(b.done)
 		bs.activeRequests.Add(-b.requestsBlocked)
-	}(bs.activeBatch)
-	bs.lastFlushed = time.Now()
-	bs.activeBatch = newEmptyBatch()
+	}(shard.activeBatch)
+	shard.lastFlushed = time.Now()
+	shard.activeBatch = newEmptyBatch()
 }
 
-// isActiveBatchReady returns true if the active batch is ready to be exported.
+// isActiveBatchReady returns true if the active batch of the shard is ready to be exported.
 // The batch is ready if it has reached the minimum size or the concurrency limit is reached.
 // Caller must hold the lock.
-func (bs *batchSender) isActiveBatchReady() bool {
-	return bs.activeBatch.request.ItemsCount() >= bs.cfg.MinSizeItems ||
-		(bs.concurrencyLimit > 0 && bs.activeRequests.Load() >= bs.concurrencyLimit)
+func (bs *batchSender) isActiveBatchReady(shard *batchShard) bool {
+	concurrencyLimit := bs.concurrencyLimit
+	if bs.limiter != nil {
+		concurrencyLimit = int64(bs.limiter.Limit())
+	}
+	return shard.activeBatch.request.ItemsCount() >= bs.cfg.MinSizeItems ||
+		(bs.cfg.MinSizeBytes > 0 && shard.activeBatch.bytes >= int64(bs.cfg.MinSizeBytes)) ||
+		(concurrencyLimit > 0 && bs.activeRequests.Load() >= concurrencyLimit)
+}
+
+// sizeofBytes returns the size in bytes of the request if it's needed to check cfg.MinSizeBytes, zero otherwise.
+// It must be called before the request is merged, as merging may move its data into the batch.
+func (bs *batchSender) sizeofBytes(req Request) int64 {
+	if bs.cfg.MinSizeBytes <= 0 {
+		return 0
+	}
+	return bytesSizer.Sizeof(req)
 }
 
 func (bs *batchSender) send(ctx context.Context, req Request) error {
@@ -145,7 +229,7 @@ func (bs *batchSender) send(ctx context.Context, req Request) error {
 		return bs.nextSender.send(ctx, req)
 	}
 
-	if bs.cfg.MaxSizeItems > 0 {
+	if bs.cfg.MaxSizeItems > 0 || bs.cfg.MaxSizeBytes > 0 {
 		return bs.sendMergeSplitBatch(ctx, req)
 	}
 	return bs.sendMergeBatch(ctx, req)
@@ -155,7 +239,14 @@ func (bs *batchSender) send(ctx context.Context, req Request) error {
 func (bs *batchSender) sendMergeSplitBatch(ctx context.Context, req Request) error {
 	bs.mu.Lock()
 
-	reqs, err := bs.mergeSplitFunc(ctx, bs.cfg.MaxSizeConfig, bs.activeBatch.request, req)
+	shard, err := bs.getShard(ctx)
+	if err != nil {
+		bs.mu.Unlock()
+		return err
+	}
+
+	reqBytes := bs.sizeofBytes(req)
+	reqs, err := bs.mergeSplitFunc(ctx, bs.cfg.MaxSizeConfig, shard.activeBatch.request, req)
 	if err != nil || len(reqs) == 0 {
 		bs.mu.Unlock()
 		return err
@@ -163,16 +254,17 @@ func (bs *batchSender) sendMergeSplitBatch(ctx context.Context, req Request) err
 
 	bs.activeRequests.Add(1)
 	if len(reqs) == 1 {
-		bs.activeBatch.requestsBlocked++
+		shard.activeBatch.requestsBlocked++
 	} else {
 		// if there was a split, we want to make sure that bs.activeRequests is released once all of the parts are sent instead of using batch.requestsBlocked
 		defer bs.activeRequests.Add(-1)
 	}
-	if len(reqs) == 1 || bs.activeBatch.request != nil {
-		bs.updateActiveBatch(ctx, reqs[0])
-		batch := bs.activeBatch
-		if bs.isActiveBatchReady() || len(reqs) > 1 {
-			bs.exportActiveBatch()
+	if len(reqs) == 1 || shard.activeBatch.request != nil {
+		// If the request was split, the first part fills the batch, which is exported regardless of its size.
+		shard.updateActiveBatch(ctx, reqs[0], reqBytes)
+		batch := shard.activeBatch
+		if bs.isActiveBatchReady(shard) || len(reqs) > 1 {
+			bs.exportActiveBatch(shard)
 		}
 		bs.mu.Unlock()
 		<-batch.done
@@ -198,9 +290,15 @@ func (bs *batchSender) sendMergeSplitBatch(ctx context.Context, req Request) err
 func (bs *batchSender) sendMergeBatch(ctx context.Context, req Request) error {
 	bs.mu.Lock()
 
-	if bs.activeBatch.request != nil {
-		var err error
-		req, err = bs.mergeFunc(ctx, bs.activeBatch.request, req)
+	shard, err := bs.getShard(ctx)
+	if err != nil {
+		bs.mu.Unlock()
+		return err
+	}
+
+	reqBytes := bs.sizeofBytes(req)
+	if shard.activeBatch.request != nil {
+		req, err = bs.mergeFunc(ctx, shard.activeBatch.request, req)
 		if err != nil {
 			bs.mu.Unlock()
 			return err
@@ -208,26 +306,29 @@ func (bs *batchSender) sendMergeBatch(ctx context.Context, req Request) error {
 	}
 
 	bs.activeRequests.Add(1)
-	bs.updateActiveBatch(ctx, req)
-	batch := bs.activeBatch
+	shard.updateActiveBatch(ctx, req, reqBytes)
+	batch := shard.activeBatch
 	batch.requestsBlocked++
-	if bs.isActiveBatchReady() {
-		bs.exportActiveBatch()
+	if bs.isActiveBatchReady(shard) {
+		bs.exportActiveBatch(shard)
 	}
 	bs.mu.Unlock()
 	<-batch.done
 	return batch.err
 }
 
-// updateActiveBatch update the active batch to the new merged request and context.
+// updateActiveBatch update the active batch of the shard to the new merged request and context,
+// adding the size in bytes of the request merged into it to the running size of the batch.
 // The context is only set once and is not updated after the first call.
 // Merging the context would be complex and require an additional goroutine to handle the context cancellation.
 // We take the approach of using the context from the first request since it's likely to have the shortest timeout.
-func (bs *batchSender) updateActiveBatch(ctx context.Context, req Request) {
-	if bs.activeBatch.request == nil {
-		bs.activeBatch.ctx = ctx
+// All the requests of the shard have the same values of the metadata keys, so the context carries them.
+func (shard *batchShard) updateActiveBatch(ctx context.Context, req Request, reqBytes int64) {
+	if shard.activeBatch.request == nil {
+		shard.activeBatch.ctx = ctx
 	}
-	bs.activeBatch.request = req
+	shard.activeBatch.request = req
+	shard.activeBatch.bytes += reqBytes
 }
 
 func (bs *batchSender) Shutdown(context.Context) error {
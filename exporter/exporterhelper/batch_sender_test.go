@@ -6,17 +6,24 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 import (
 	"context"
 	"errors"
+	"math"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
 	"go.opentelemetry.io/collector/exporter/exporterqueue"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/testdata"
 )
 
 func TestBatchSender_Merge(t *testing.T) {
@@ -703,6 +710,174 @@ func TestBatchSenderTimerFlush(t *testing.T) {
 	require.NoError(t, be.Shutdown(context.Background()))
 }
 
+func TestBatchSender_MetadataKeys(t *testing.T) {
+	bCfg := exporterbatcher.NewDefaultConfig()
+	bCfg.MinSizeItems = 10
+	bCfg.FlushTimeout = time.Hour
+	bCfg.MetadataKeys = []string{"X-Tenant"}
+	bCfg.MetadataCardinalityLimit = 2
+	be, err := newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender,
+		WithBatcher(bCfg, WithRequestBatchFuncs(fakeBatchMergeFunc, fakeBatchMergeSplitFunc)))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	tenantCtx := func(tenant string) context.Context {
+		return client.NewContext(context.Background(), client.Info{
+			Metadata: client.NewMetadata(map[string][]string{"x-tenant": {tenant}}),
+		})
+	}
+	sink := newFakeRequestSink()
+	var wg sync.WaitGroup
+	send := func(tenant string, items int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, be.send(tenantCtx(tenant), &fakeRequest{items: items, sink: sink}))
+		}()
+	}
+
+	// The requests of different tenants are not merged together, so none of the batches reaches the minimum size.
+	send("acme", 6)
+	send("globex", 6)
+	assert.Eventually(t, func() bool { return be.batchSender.(*batchSender).activeRequests.Load() == 2 },
+		time.Second, time.Millisecond)
+	assert.Equal(t, uint64(0), sink.requestsCount.Load())
+
+	// A third tenant is rejected as the cardinality limit is reached.
+	err = be.send(tenantCtx("initech"), &fakeRequest{items: 10, sink: sink})
+	require.ErrorIs(t, err, errTooManyBatches)
+	assert.True(t, consumererror.IsPermanent(err))
+
+	send("acme", 4)
+	assert.Eventually(t, func() bool {
+		return sink.requestsCount.Load() == 1 && sink.itemsCount.Load() == 10
+	}, time.Second, time.Millisecond)
+
+	send("globex", 4)
+	wg.Wait()
+	assert.Equal(t, uint64(2), sink.requestsCount.Load())
+	assert.Equal(t, uint64(20), sink.itemsCount.Load())
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestBatchSender_IdleShardsRemoved(t *testing.T) {
+	bCfg := exporterbatcher.NewDefaultConfig()
+	bCfg.FlushTimeout = 10 * time.Millisecond
+	bCfg.MetadataKeys = []string{"X-Tenant"}
+	bCfg.MetadataCardinalityLimit = 1
+	be, err := newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender,
+		WithBatcher(bCfg, WithRequestBatchFuncs(fakeBatchMergeFunc, fakeBatchMergeSplitFunc)))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, be.Shutdown(context.Background())) })
+
+	sink := newFakeRequestSink()
+	for _, tenant := range []string{"acme", "globex"} {
+		ctx := client.NewContext(context.Background(), client.Info{
+			Metadata: client.NewMetadata(map[string][]string{"x-tenant": {tenant}}),
+		})
+		// The batch of the previous tenant is flushed by the timeout, then forgotten once idle.
+		assert.EventuallyWithT(t, func(c *assert.CollectT) {
+			assert.NoError(c, be.send(ctx, &fakeRequest{items: 1, sink: sink}))
+		}, time.Second, 5*time.Millisecond)
+	}
+	assert.Equal(t, uint64(2), sink.requestsCount.Load())
+}
+
+func TestBatchSender_SizeBytes(t *testing.T) {
+	td := testdata.GenerateTraces(4)
+	size := tracesMarshaler.TracesSize(td)
+	bCfg := exporterbatcher.NewDefaultConfig()
+	bCfg.FlushTimeout = time.Hour
+	bCfg.MinSizeBytes = 2 * size
+	bCfg.MaxSizeBytes = 2 * size
+	sink := new(consumertest.TracesSink)
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+		sink.ConsumeTraces, WithBatcher(bCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The first request is kept in the batch, the second one makes it reach the minimum size.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(4)))
+	}()
+	assert.Never(t, func() bool { return sink.SpanCount() > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(4)))
+	wg.Wait()
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, 8, sink.SpanCount())
+
+	// A request bigger than the maximum size is split.
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(10)))
+	require.Len(t, sink.AllTraces(), 3)
+	for _, td := range sink.AllTraces() {
+		assert.LessOrEqual(t, tracesMarshaler.TracesSize(td), 2*size)
+	}
+	assert.Equal(t, 18, sink.SpanCount())
+	require.NoError(t, te.Shutdown(context.Background()))
+}
+
+// sizedFakeRequest is a fakeRequest reporting its size in bytes and counting the bytes measured.
+type sizedFakeRequest struct {
+	*fakeRequest
+	bytes    int
+	measured *atomic.Int64
+}
+
+func (r *sizedFakeRequest) BytesSize() int {
+	r.measured.Add(int64(r.bytes))
+	return r.bytes
+}
+
+func TestBatchSender_SizeBytesRunningTotal(t *testing.T) {
+	measured := &atomic.Int64{}
+	mergeFunc := func(ctx context.Context, r1 Request, r2 Request) (Request, error) {
+		if r1 == nil {
+			return r2, nil
+		}
+		sr1, sr2 := r1.(*sizedFakeRequest), r2.(*sizedFakeRequest)
+		merged, err := fakeBatchMergeFunc(ctx, sr1.fakeRequest, sr2.fakeRequest)
+		if err != nil {
+			return nil, err
+		}
+		return &sizedFakeRequest{fakeRequest: merged.(*fakeRequest), bytes: sr1.bytes + sr2.bytes, measured: measured}, nil
+	}
+	bCfg := exporterbatcher.NewDefaultConfig()
+	bCfg.FlushTimeout = time.Hour
+	bCfg.MinSizeItems = math.MaxInt
+	bCfg.MinSizeBytes = 100
+	be, err := newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender,
+		WithBatcher(bCfg, WithRequestBatchFuncs(mergeFunc, fakeBatchMergeSplitFunc)))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	sink := newFakeRequestSink()
+	var wg sync.WaitGroup
+	for i := 0; i < 9; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, be.send(context.Background(), &sizedFakeRequest{
+				fakeRequest: &fakeRequest{items: 1, sink: sink}, bytes: 10, measured: measured}))
+		}()
+	}
+	assert.Eventually(t, func() bool { return measured.Load() == 90 }, time.Second, 5*time.Millisecond)
+	assert.Zero(t, sink.requestsCount.Load())
+
+	// The tenth request makes the batch reach the minimum size. Each request was measured only once,
+	// the accumulated batch wasn't measured again on every send.
+	require.NoError(t, be.send(context.Background(), &sizedFakeRequest{
+		fakeRequest: &fakeRequest{items: 1, sink: sink}, bytes: 10, measured: measured}))
+	wg.Wait()
+	assert.Equal(t, uint64(1), sink.requestsCount.Load())
+	assert.Equal(t, uint64(10), sink.itemsCount.Load())
+	assert.Equal(t, int64(100), measured.Load())
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
 func queueBatchExporter(t *testing.T, batchOption Option) *baseExporter {
 	be, err := newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender, batchOption,
 		WithRequestQueue(exporterqueue.NewDefaultConfig(), exporterqueue.NewMemoryQueueFactory[Request]()))
@@ -0,0 +1,353 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
+	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+)
+
+var errCircuitBreakerOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerSettings defines configuration for stopping the exports for a while when the destination keeps
+// failing. The circuit opens when either of the configured thresholds is reached, stays open for CoolDown,
+// then half-opens to let probe requests through, and closes again once HalfOpenProbes of them succeed.
+type CircuitBreakerSettings struct {
+	// Enabled indicates whether to stop the exports when the destination keeps failing.
+	Enabled bool `mapstructure:"enabled"`
+	// ConsecutiveFailures is the number of consecutive failed attempts that opens the circuit. Zero disables it.
+	ConsecutiveFailures int `mapstructure:"consecutive_failures"`
+	// FailureRatio is the ratio, between 0 and 1, of failed attempts within the Window that opens the circuit.
+	// Zero disables it.
+	FailureRatio float64 `mapstructure:"failure_ratio"`
+	// Window is the duration over which the FailureRatio is computed.
+	Window time.Duration `mapstructure:"window"`
+	// MinRequests is the minimum number of attempts within the Window for the FailureRatio to be considered.
+	MinRequests int `mapstructure:"min_requests"`
+	// CoolDown is the duration the circuit stays open before letting probe requests through.
+	CoolDown time.Duration `mapstructure:"cool_down"`
+	// HalfOpenProbes is the number of successful probe requests needed to close the circuit.
+	HalfOpenProbes int `mapstructure:"half_open_probes"`
+}
+
+// NewDefaultCircuitBreakerSettings returns the default settings for CircuitBreakerSettings.
+func NewDefaultCircuitBreakerSettings() CircuitBreakerSettings {
+	return CircuitBreakerSettings{
+		Enabled:             false,
+		ConsecutiveFailures: 5,
+		Window:              time.Minute,
+		MinRequests:         10,
+		CoolDown:            30 * time.Second,
+		HalfOpenProbes:      1,
+	}
+}
+
+// Validate checks if the CircuitBreakerSettings configuration is valid
+func (cbCfg *CircuitBreakerSettings) Validate() error {
+	if !cbCfg.Enabled {
+		return nil
+	}
+	if cbCfg.ConsecutiveFailures < 0 {
+		return errors.New("circuit breaker consecutive_failures must not be negative")
+	}
+	if cbCfg.FailureRatio < 0 || cbCfg.FailureRatio > 1 {
+		return errors.New("circuit breaker failure_ratio must be between 0 and 1")
+	}
+	if cbCfg.ConsecutiveFailures == 0 && cbCfg.FailureRatio == 0 {
+		return errors.New("circuit breaker requires consecutive_failures or failure_ratio to be set")
+	}
+	if cbCfg.FailureRatio > 0 && cbCfg.Window <= 0 {
+		return errors.New("circuit breaker window must be positive")
+	}
+	if cbCfg.CoolDown <= 0 {
+		return errors.New("circuit breaker cool_down must be positive")
+	}
+	if cbCfg.HalfOpenProbes <= 0 {
+		return errors.New("circuit breaker half_open_probes must be positive")
+	}
+	return nil
+}
+
+// circuitState is the state of the circuit breaker, as reported by the exporter_circuit_breaker_state metric.
+type circuitState int64
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitHalfOpen:
+		return "half-open"
+	case circuitOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreakerSender is a requestSender that stops sending the requests for a while when the destination keeps
+// failing. It's placed before the retrySender, and the requests wait for the circuit to close before being retried,
+// so the requests don't burn their retries while the circuit is open.
+//
+// The attempts are observed by its circuitAttemptSender placed after the retrySender, which stops the retries of
+// the requests once the circuit opens. These requests wait for the circuit to close again, and are sent with a new
+// retry budget.
+type circuitBreakerSender struct {
+	baseRequestSender
+	attempts *circuitAttemptSender
+
+	cfg            CircuitBreakerSettings
+	traceAttribute attribute.KeyValue
+	logger         *zap.Logger
+	reportStatus   func(*component.StatusEvent)
+	obsrep         *obsReport
+	stopCh         chan struct{}
+
+	// mu guards everything declared below.
+	mu    sync.Mutex
+	state circuitState
+	// changed is closed and replaced every time a waiting request may be let through.
+	changed  chan struct{}
+	openedAt time.Time
+
+	consecutiveFailures int
+	windowStart         time.Time
+	windowRequests      int
+	windowFailures      int
+
+	probesInFlight int
+	probeSuccesses int
+}
+
+func newCircuitBreakerSender(cfg CircuitBreakerSettings, set exporter.Settings, obsrep *obsReport) *circuitBreakerSender {
+	cb := &circuitBreakerSender{
+		cfg:            cfg,
+		traceAttribute: attribute.String(obsmetrics.ExporterKey, set.ID.String()),
+		logger:         set.Logger,
+		reportStatus:   set.ReportStatus,
+		obsrep:         obsrep,
+		stopCh:         make(chan struct{}),
+		changed:        make(chan struct{}),
+	}
+	cb.attempts = &circuitAttemptSender{cb: cb}
+	return cb
+}
+
+func (cb *circuitBreakerSender) Start(context.Context, component.Host) error {
+	cb.mu.Lock()
+	cb.windowStart = time.Now()
+	cb.mu.Unlock()
+	return cb.obsrep.telemetryBuilder.InitExporterCircuitBreakerState(func() int64 {
+		return int64(cb.currentState())
+	}, metric.WithAttributeSet(attribute.NewSet(cb.traceAttribute,
+		attribute.String(obsmetrics.DataTypeKey, cb.obsrep.dataType.String()))))
+}
+
+// currentState returns the current state of the circuit.
+func (cb *circuitBreakerSender) currentState() circuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// Shutdown lets the requests waiting for the circuit to close fail with a shutdown error,
+// so the persistent queue can keep them to be sent after restart.
+func (cb *circuitBreakerSender) Shutdown(context.Context) error {
+	close(cb.stopCh)
+	return nil
+}
+
+func (cb *circuitBreakerSender) send(ctx context.Context, req Request) error {
+	for {
+		probe, err := cb.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		sendCtx := ctx
+		if probe {
+			sendCtx = context.WithValue(ctx, circuitProbeKey{}, true)
+		}
+		err = cb.nextSender.send(sendCtx, req)
+		if probe {
+			cb.releaseProbe()
+		}
+		if !errors.Is(err, errCircuitBreakerOpen) {
+			return err
+		}
+		// The circuit opened while the request was retried, it waits for the circuit to close again.
+	}
+}
+
+// circuitProbeKey is the context key marking the probe requests of the half-open circuit.
+type circuitProbeKey struct{}
+
+// circuitAttemptSender is a requestSender that records the result of every attempt in the circuit breaker. The
+// attempts of the requests which are not the probe of the circuit fail with a permanent error once the circuit is not
+// closed anymore, so the retries stop and the requests wait for the circuit in the circuitBreakerSender.
+type circuitAttemptSender struct {
+	baseRequestSender
+	cb *circuitBreakerSender
+}
+
+func (as *circuitAttemptSender) send(ctx context.Context, req Request) error {
+	probe, _ := ctx.Value(circuitProbeKey{}).(bool)
+	if !as.cb.allowAttempt(probe) {
+		return consumererror.NewPermanent(errCircuitBreakerOpen)
+	}
+	err := as.nextSender.send(ctx, req)
+	as.cb.record(probe, classifyExportErr(err))
+	return err
+}
+
+// allowAttempt reports whether an attempt can be made: the circuit is closed, or the attempt is made by the probe of
+// the half-open circuit.
+func (cb *circuitBreakerSender) allowAttempt(probe bool) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state == circuitClosed || (probe && cb.state == circuitHalfOpen)
+}
+
+// releaseProbe marks the probe request as done, and lets the next probe through.
+func (cb *circuitBreakerSender) releaseProbe() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	// The probes in flight are reset when the state changes.
+	if cb.probesInFlight > 0 {
+		cb.probesInFlight--
+	}
+	cb.notify()
+}
+
+// acquire blocks until the request can be sent. It returns true if the request is a probe of the half-open circuit.
+func (cb *circuitBreakerSender) acquire(ctx context.Context) (bool, error) {
+	for {
+		cb.mu.Lock()
+		var wait <-chan time.Time
+		switch cb.state {
+		case circuitClosed:
+			cb.mu.Unlock()
+			return false, nil
+		case circuitOpen:
+			remaining := cb.cfg.CoolDown - time.Since(cb.openedAt)
+			if remaining <= 0 {
+				cb.setState(circuitHalfOpen)
+				cb.mu.Unlock()
+				continue
+			}
+			wait = time.After(remaining)
+		case circuitHalfOpen:
+			// Probe one request at a time, so the destination isn't flooded with the queued requests.
+			if cb.probesInFlight == 0 {
+				cb.probesInFlight++
+				cb.mu.Unlock()
+				return true, nil
+			}
+		}
+		changed := cb.changed
+		cb.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return false, fmt.Errorf("request is cancelled or timed out while %w: %w", errCircuitBreakerOpen, ctx.Err())
+		case <-cb.stopCh:
+			return false, experr.NewShutdownErr(errCircuitBreakerOpen)
+		case <-changed:
+		case <-wait:
+		}
+	}
+}
+
+// record updates the state of the circuit with the result of an attempt.
+func (cb *circuitBreakerSender) record(probe bool, res queue.ExportResult) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if probe {
+		if cb.state != circuitHalfOpen {
+			return
+		}
+		switch res {
+		case queue.ExportOverloaded:
+			cb.setState(circuitOpen)
+		case queue.ExportSucceeded:
+			cb.probeSuccesses++
+			if cb.probeSuccesses >= cb.cfg.HalfOpenProbes {
+				cb.setState(circuitClosed)
+			}
+		}
+		return
+	}
+
+	// The results of the requests sent before the circuit was opened don't matter anymore.
+	if cb.state != circuitClosed || res == queue.ExportIgnored {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(cb.windowStart) >= cb.cfg.Window {
+		cb.windowStart, cb.windowRequests, cb.windowFailures = now, 0, 0
+	}
+	cb.windowRequests++
+	if res == queue.ExportSucceeded {
+		cb.consecutiveFailures = 0
+		return
+	}
+	cb.consecutiveFailures++
+	cb.windowFailures++
+
+	if (cb.cfg.ConsecutiveFailures > 0 && cb.consecutiveFailures >= cb.cfg.ConsecutiveFailures) ||
+		(cb.cfg.FailureRatio > 0 && cb.windowRequests >= cb.cfg.MinRequests &&
+			float64(cb.windowFailures) >= cb.cfg.FailureRatio*float64(cb.windowRequests)) {
+		cb.setState(circuitOpen)
+	}
+}
+
+// setState transitions the circuit to the given state and reports the change. Caller must hold the lock.
+func (cb *circuitBreakerSender) setState(state circuitState) {
+	cb.state = state
+	cb.consecutiveFailures, cb.windowRequests, cb.windowFailures = 0, 0, 0
+	cb.windowStart = time.Now()
+	cb.probesInFlight, cb.probeSuccesses = 0, 0
+
+	switch state {
+	case circuitOpen:
+		cb.openedAt = time.Now()
+		cb.logger.Warn("Destination keeps failing, stopping the exports.",
+			zap.String("circuit", state.String()), zap.Duration("cool_down", cb.cfg.CoolDown))
+		if cb.reportStatus != nil {
+			cb.reportStatus(component.NewRecoverableErrorEvent(errCircuitBreakerOpen))
+		}
+	case circuitHalfOpen:
+		cb.logger.Info("Probing the destination.", zap.String("circuit", state.String()))
+	case circuitClosed:
+		cb.logger.Info("Destination recovered, resuming the exports.", zap.String("circuit", state.String()))
+		if cb.reportStatus != nil {
+			cb.reportStatus(component.NewStatusEvent(component.StatusOK))
+		}
+	}
+	cb.notify()
+}
+
+// notify wakes up the requests waiting for the circuit. Caller must hold the lock.
+func (cb *circuitBreakerSender) notify() {
+	close(cb.changed)
+	cb.changed = make(chan struct{})
+}
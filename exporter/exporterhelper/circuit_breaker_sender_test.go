@@ -0,0 +1,301 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+)
+
+func TestCircuitBreakerSettings_Validate(t *testing.T) {
+	cbCfg := NewDefaultCircuitBreakerSettings()
+	assert.NoError(t, cbCfg.Validate())
+
+	cbCfg.Enabled = true
+	assert.NoError(t, cbCfg.Validate())
+
+	cbCfg.ConsecutiveFailures = -1
+	assert.EqualError(t, cbCfg.Validate(), "circuit breaker consecutive_failures must not be negative")
+
+	cbCfg.ConsecutiveFailures = 0
+	assert.EqualError(t, cbCfg.Validate(), "circuit breaker requires consecutive_failures or failure_ratio to be set")
+
+	cbCfg.FailureRatio = 1.5
+	assert.EqualError(t, cbCfg.Validate(), "circuit breaker failure_ratio must be between 0 and 1")
+
+	cbCfg.FailureRatio = 0.5
+	cbCfg.Window = 0
+	assert.EqualError(t, cbCfg.Validate(), "circuit breaker window must be positive")
+
+	cbCfg = NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	cbCfg.CoolDown = 0
+	assert.EqualError(t, cbCfg.Validate(), "circuit breaker cool_down must be positive")
+
+	cbCfg = NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	cbCfg.HalfOpenProbes = 0
+	assert.EqualError(t, cbCfg.Validate(), "circuit breaker half_open_probes must be positive")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	cbCfg.Enabled = false
+	assert.NoError(t, cbCfg.Validate())
+}
+
+// switchableSender counts the requests it receives and fails them with the currently set error.
+type switchableSender struct {
+	baseRequestSender
+	mu       sync.Mutex
+	err      error
+	requests atomic.Int64
+}
+
+func (s *switchableSender) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *switchableSender) send(context.Context, Request) error {
+	s.requests.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// statusRecorder records the statuses reported by a component.
+type statusRecorder struct {
+	mu       sync.Mutex
+	statuses []component.Status
+}
+
+func (r *statusRecorder) reportStatus(ev *component.StatusEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statuses = append(r.statuses, ev.Status())
+}
+
+func (r *statusRecorder) get() []component.Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]component.Status(nil), r.statuses...)
+}
+
+func newTestCircuitBreakerSender(t *testing.T, cfg CircuitBreakerSettings) (*circuitBreakerSender, *switchableSender, *statusRecorder) {
+	set := defaultSettings
+	recorder := &statusRecorder{}
+	set.ReportStatus = recorder.reportStatus
+	obsrep, err := newObsReport(obsReportSettings{exporterID: defaultID, exporterCreateSettings: set, dataType: defaultDataType})
+	require.NoError(t, err)
+	cb := newCircuitBreakerSender(cfg, set, obsrep)
+	next := &switchableSender{}
+	cb.setNextSender(cb.attempts)
+	cb.attempts.setNextSender(next)
+	require.NoError(t, cb.Start(context.Background(), componenttest.NewNopHost()))
+	return cb, next, recorder
+}
+
+func TestCircuitBreaker_ConsecutiveFailures(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerSettings()
+	cfg.Enabled = true
+	cfg.ConsecutiveFailures = 3
+	cfg.CoolDown = 50 * time.Millisecond
+	cb, next, recorder := newTestCircuitBreakerSender(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, cb.Shutdown(context.Background())) })
+
+	// A success resets the count of consecutive failures.
+	next.setErr(errors.New("unavailable"))
+	for i := 0; i < 2; i++ {
+		require.Error(t, cb.send(context.Background(), newMockRequest(1, nil)))
+	}
+	next.setErr(nil)
+	require.NoError(t, cb.send(context.Background(), newMockRequest(1, nil)))
+
+	// Permanent errors don't count.
+	next.setErr(consumererror.NewPermanent(errors.New("bad data")))
+	for i := 0; i < 5; i++ {
+		require.Error(t, cb.send(context.Background(), newMockRequest(1, nil)))
+	}
+	assert.Equal(t, circuitClosed, cb.state)
+
+	next.setErr(errors.New("unavailable"))
+	for i := 0; i < 3; i++ {
+		require.Error(t, cb.send(context.Background(), newMockRequest(1, nil)))
+	}
+	assert.Equal(t, circuitOpen, cb.state)
+	assert.Equal(t, []component.Status{component.StatusRecoverableError}, recorder.get())
+
+	// While the circuit is open, the requests wait instead of being sent.
+	sentBefore := next.requests.Load()
+	next.setErr(nil)
+	start := time.Now()
+	require.NoError(t, cb.send(context.Background(), newMockRequest(1, nil)))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.Equal(t, sentBefore+1, next.requests.Load())
+
+	// The successful probe closes the circuit.
+	assert.Equal(t, circuitClosed, cb.state)
+	assert.Equal(t, []component.Status{component.StatusRecoverableError, component.StatusOK}, recorder.get())
+}
+
+func TestCircuitBreaker_FailureRatio(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerSettings()
+	cfg.Enabled = true
+	cfg.ConsecutiveFailures = 0
+	cfg.FailureRatio = 0.5
+	cfg.MinRequests = 4
+	cb, next, _ := newTestCircuitBreakerSender(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, cb.Shutdown(context.Background())) })
+
+	unavailable := errors.New("unavailable")
+	for _, err := range []error{unavailable, nil, unavailable, nil} {
+		next.setErr(err)
+		assert.Equal(t, err, cb.send(context.Background(), newMockRequest(1, nil)))
+	}
+	// Half of the requests failed, but the circuit doesn't open until enough requests are made.
+	assert.Equal(t, circuitClosed, cb.state)
+
+	next.setErr(unavailable)
+	require.Error(t, cb.send(context.Background(), newMockRequest(1, nil)))
+	assert.Equal(t, circuitOpen, cb.state)
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerSettings()
+	cfg.Enabled = true
+	cfg.ConsecutiveFailures = 1
+	cfg.CoolDown = 20 * time.Millisecond
+	cfg.HalfOpenProbes = 2
+	cb, next, _ := newTestCircuitBreakerSender(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, cb.Shutdown(context.Background())) })
+
+	next.setErr(errors.New("unavailable"))
+	require.Error(t, cb.send(context.Background(), newMockRequest(1, nil)))
+	assert.Equal(t, circuitOpen, cb.state)
+
+	// The probe fails, the circuit opens again.
+	require.Error(t, cb.send(context.Background(), newMockRequest(1, nil)))
+	assert.Equal(t, circuitOpen, cb.state)
+
+	// Two successful probes are needed to close the circuit.
+	next.setErr(nil)
+	require.NoError(t, cb.send(context.Background(), newMockRequest(1, nil)))
+	assert.Equal(t, circuitHalfOpen, cb.state)
+	require.NoError(t, cb.send(context.Background(), newMockRequest(1, nil)))
+	assert.Equal(t, circuitClosed, cb.state)
+}
+
+func TestCircuitBreaker_WaitInterrupted(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerSettings()
+	cfg.Enabled = true
+	cfg.ConsecutiveFailures = 1
+	cfg.CoolDown = time.Hour
+	cb, next, _ := newTestCircuitBreakerSender(t, cfg)
+
+	next.setErr(errors.New("unavailable"))
+	require.Error(t, cb.send(context.Background(), newMockRequest(1, nil)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := cb.send(ctx, newMockRequest(1, nil))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, errCircuitBreakerOpen)
+
+	// The shutdown releases the waiting requests with a shutdown error, so the persistent queue keeps them.
+	errCh := make(chan error)
+	go func() {
+		errCh <- cb.send(context.Background(), newMockRequest(1, nil))
+	}()
+	require.NoError(t, cb.Shutdown(context.Background()))
+	assert.True(t, experr.IsShutdownErr(<-errCh))
+	assert.Equal(t, int64(1), next.requests.Load())
+}
+
+func TestCircuitBreaker_RetriesNotBurnt(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerSettings()
+	cfg.Enabled = true
+	cfg.ConsecutiveFailures = 1
+	cfg.CoolDown = 100 * time.Millisecond
+	cb, next, _ := newTestCircuitBreakerSender(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, cb.Shutdown(context.Background())) })
+
+	// The retry budget is shorter than the outage, but the request doesn't burn it while the circuit is open.
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxInterval = time.Millisecond
+	rCfg.RandomizationFactor = 0
+	rCfg.MaxElapsedTime = 150 * time.Millisecond
+	rs := newRetrySender(rCfg, defaultSettings)
+	cb.setNextSender(rs)
+	rs.setNextSender(cb.attempts)
+	t.Cleanup(func() { assert.NoError(t, rs.Shutdown(context.Background())) })
+
+	next.setErr(errors.New("unavailable"))
+	recovered := time.AfterFunc(250*time.Millisecond, func() { next.setErr(nil) })
+	t.Cleanup(func() { recovered.Stop() })
+
+	require.NoError(t, cb.send(context.Background(), newMockRequest(1, nil)))
+	assert.Equal(t, circuitClosed, cb.currentState())
+	// Only the first attempt and the probes reached the destination.
+	assert.LessOrEqual(t, next.requests.Load(), int64(5))
+}
+
+func TestCircuitBreaker_RequestsStayQueued(t *testing.T) {
+	tt, err := componenttest.SetupTelemetry(defaultID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	cbCfg := NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	cbCfg.ConsecutiveFailures = 1
+	cbCfg.CoolDown = time.Hour
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	set := exporter.Settings{ID: defaultID, TelemetrySettings: tt.TelemetrySettings(), BuildInfo: component.NewDefaultBuildInfo()}
+	be, err := newBaseExporter(set, defaultDataType, newNoopObsrepSender,
+		withMarshaler(mockRequestMarshaler), withUnmarshaler(mockRequestUnmarshaler(&mockRequest{})),
+		WithRetry(rCfg), WithQueue(qCfg), WithCircuitBreaker(cbCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	attrs := attribute.String(obsmetrics.DataTypeKey, defaultDataType.String())
+	require.NoError(t, tt.CheckExporterMetricGauge("otelcol_exporter_circuit_breaker_state", int64(circuitClosed), attrs))
+
+	mockR := newMockRequest(1, errors.New("unavailable"))
+	require.NoError(t, be.send(context.Background(), mockR))
+	cb := be.circuitBreakerSender.(*circuitBreakerSender)
+	assert.Eventually(t, func() bool {
+		cb.mu.Lock()
+		defer cb.mu.Unlock()
+		return cb.state == circuitOpen
+	}, time.Second, time.Millisecond)
+	require.NoError(t, tt.CheckExporterMetricGauge("otelcol_exporter_circuit_breaker_state", int64(circuitOpen), attrs))
+
+	// The following requests stay in the queue while the circuit is open, and the retries are not burnt.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, be.send(context.Background(), newMockRequest(1, nil)))
+	}
+	assert.Equal(t, 3, be.queueSender.(*queueSender).queue.Size())
+	assert.Equal(t, int64(1), mockR.requestCount.Load())
+
+	assert.NoError(t, be.Shutdown(context.Background()))
+}
@@ -6,6 +6,7 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -86,6 +87,44 @@ func WithRetry(config configretry.BackOffConfig) Option {
 	}
 }
 
+// WithRateLimit overrides the default RateLimitSettings for an exporter.
+// The default RateLimitSettings is to not limit the rate.
+func WithRateLimit(config RateLimitSettings) Option {
+	return func(o *baseExporter) error {
+		if !config.Enabled {
+			return nil
+		}
+		o.rateLimitSender = newRateLimitSender(config)
+		return nil
+	}
+}
+
+// WithCircuitBreaker overrides the default CircuitBreakerSettings for an exporter.
+// The default CircuitBreakerSettings is to disable the circuit breaker.
+func WithCircuitBreaker(config CircuitBreakerSettings) Option {
+	return func(o *baseExporter) error {
+		if !config.Enabled {
+			return nil
+		}
+		cb := newCircuitBreakerSender(config, o.set, o.obsrep)
+		o.circuitBreakerSender = cb
+		o.circuitAttemptSender = cb.attempts
+		return nil
+	}
+}
+
+// WithHedging overrides the default HedgingSettings for an exporter.
+// The default HedgingSettings is to disable the hedging.
+func WithHedging(config HedgingSettings) Option {
+	return func(o *baseExporter) error {
+		if !config.Enabled {
+			return nil
+		}
+		o.hedgingSender = newHedgingSender(config, o.set, o.obsrep)
+		return nil
+	}
+}
+
 // WithQueue overrides the default QueueSettings for an exporter.
 // The default QueueSettings is to disable queueing.
 // This option cannot be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
@@ -98,19 +137,29 @@ func WithQueue(config QueueSettings) Option {
 			o.exportFailureMessage += " Try enabling sending_queue to survive temporary failures."
 			return nil
 		}
-		qf := exporterqueue.NewPersistentQueueFactory[Request](config.StorageID, exporterqueue.PersistentQueueSettings[Request]{
+		pqSet, err := exporterqueue.NewEncryptedPersistentQueueSettings(config.Encryption, exporterqueue.PersistentQueueSettings[Request]{
 			Marshaler:   o.marshaler,
 			Unmarshaler: o.unmarshaler,
 		})
+		if err != nil {
+			return err
+		}
+		qf := exporterqueue.NewSpillOverQueueFactory[Request](config.StorageID, config.SpillOver, pqSet)
+		qCfg := exporterqueue.Config{
+			Enabled:              config.Enabled,
+			NumConsumers:         config.NumConsumers,
+			QueueSize:            config.QueueSize,
+			QueueSizeBytes:       config.QueueSizeBytes,
+			AdaptiveConcurrency:  config.AdaptiveConcurrency,
+			MetadataKeys:         config.MetadataKeys,
+			Compression:          config.Compression,
+			ShutdownDrainTimeout: config.ShutdownDrainTimeout,
+		}
 		q := qf(context.Background(), exporterqueue.Settings{
 			DataType:         o.signal,
 			ExporterSettings: o.set,
-		}, exporterqueue.Config{
-			Enabled:      config.Enabled,
-			NumConsumers: config.NumConsumers,
-			QueueSize:    config.QueueSize,
-		})
-		o.queueSender = newQueueSender(q, o.set, config.NumConsumers, o.exportFailureMessage, o.obsrep)
+		}, qCfg)
+		o.queueSender = newQueueSender(q, o.set, qCfg, o.exportFailureMessage, o.obsrep)
 		return nil
 	}
 }
@@ -132,7 +181,34 @@ func WithRequestQueue(cfg exporterqueue.Config, queueFactory exporterqueue.Facto
 			DataType:         o.signal,
 			ExporterSettings: o.set,
 		}
-		o.queueSender = newQueueSender(queueFactory(context.Background(), set, cfg), o.set, cfg.NumConsumers, o.exportFailureMessage, o.obsrep)
+		o.queueSender = newQueueSender(queueFactory(context.Background(), set, cfg), o.set, cfg, o.exportFailureMessage, o.obsrep)
+		return nil
+	}
+}
+
+// WithDeadLetter sets the destination of the data dropped by the exporter, either because of a permanent error or
+// because all the retries have been exhausted. The default DeadLetterSettings is to drop the data.
+// This option cannot be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
+func WithDeadLetter(config DeadLetterSettings) Option {
+	return func(o *baseExporter) error {
+		if o.marshaler == nil {
+			return fmt.Errorf("WithDeadLetter option is not available for the new request exporters")
+		}
+		switch {
+		case config.Exporter != nil:
+			o.deadLetterSender = newDeadLetterSender(&exporterDeadLetterSink{
+				id:      *config.Exporter,
+				ownerID: o.set.ID,
+				signal:  o.signal,
+			}, o.set)
+		case config.StorageID != nil:
+			o.deadLetterSender = newDeadLetterSender(&storageDeadLetterSink{
+				storageID: *config.StorageID,
+				ownerID:   o.set.ID,
+				signal:    o.signal,
+				marshaler: o.marshaler,
+			}, o.set)
+		}
 		return nil
 	}
 }
@@ -240,13 +316,22 @@ type baseExporter struct {
 	// Chain of senders that the exporter helper applies before passing the data to the actual exporter.
 	// The data is handled by each sender in the respective order starting from the queueSender.
 	// Most of the senders are optional, and initialized with a no-op path-through sender.
-	batchSender   requestSender
-	queueSender   requestSender
-	obsrepSender  requestSender
-	retrySender   requestSender
-	timeoutSender *timeoutSender // timeoutSender is always initialized.
+	batchSender          requestSender
+	queueSender          requestSender
+	obsrepSender         requestSender
+	deadLetterSender     requestSender
+	rateLimitSender      requestSender
+	retrySender          requestSender
+	attemptSender        requestSender
+	circuitBreakerSender requestSender
+	circuitAttemptSender requestSender
+	hedgingSender        requestSender
+	timeoutSender        *timeoutSender // timeoutSender is always initialized.
 
 	consumerOptions []consumer.Option
+
+	// stopped is set once the exporter starts shutting down.
+	stopped atomic.Bool
 }
 
 func newBaseExporter(set exporter.Settings, signal component.DataType, osf obsrepSenderFactory, options ...Option) (*baseExporter, error) {
@@ -258,11 +343,17 @@ func newBaseExporter(set exporter.Settings, signal component.DataType, osf obsre
 	be := &baseExporter{
 		signal: signal,
 
-		batchSender:   &baseRequestSender{},
-		queueSender:   &baseRequestSender{},
-		obsrepSender:  osf(obsReport),
-		retrySender:   &baseRequestSender{},
-		timeoutSender: &timeoutSender{cfg: NewDefaultTimeoutSettings()},
+		batchSender:          &baseRequestSender{},
+		queueSender:          &baseRequestSender{},
+		obsrepSender:         osf(obsReport),
+		deadLetterSender:     &baseRequestSender{},
+		rateLimitSender:      &baseRequestSender{},
+		retrySender:          &baseRequestSender{},
+		attemptSender:        &baseRequestSender{},
+		circuitBreakerSender: &baseRequestSender{},
+		circuitAttemptSender: &baseRequestSender{},
+		hedgingSender:        &baseRequestSender{},
+		timeoutSender:        &timeoutSender{cfg: NewDefaultTimeoutSettings()},
 
 		set:    set,
 		obsrep: obsReport,
@@ -275,20 +366,36 @@ func newBaseExporter(set exporter.Settings, signal component.DataType, osf obsre
 		return nil, err
 	}
 
+	if qs, ok := be.queueSender.(*queueSender); ok && qs.limiter != nil {
+		be.attemptSender = &attemptLimiterSender{limiter: qs.limiter}
+	}
 	be.connectSenders()
 
 	if bs, ok := be.batchSender.(*batchSender); ok {
 		// If queue sender is enabled assign to the batch sender the same number of workers.
 		if qs, ok := be.queueSender.(*queueSender); ok {
 			bs.concurrencyLimit = int64(qs.numConsumers)
+			bs.limiter = qs.limiter
 		}
 		// Batcher sender mutates the data.
 		be.consumerOptions = append(be.consumerOptions, consumer.WithCapabilities(consumer.Capabilities{MutatesData: true}))
 	}
 
+	if ds, ok := be.deadLetterSender.(*deadLetterSender); ok {
+		// The requests failing because of the shutdown are not lost if the queue keeps them to be sent after restart.
+		if qs, ok := be.queueSender.(*queueSender); ok {
+			ds.keepOnShutdown = qs.persistent
+		}
+	}
+
 	return be, nil
 }
 
+// isStopped reports whether the exporter is shut down, or shutting down.
+func (be *baseExporter) isStopped() bool {
+	return be.stopped.Load()
+}
+
 // send sends the request using the first sender in the chain.
 func (be *baseExporter) send(ctx context.Context, req Request) error {
 	err := be.queueSender.send(ctx, req)
@@ -303,8 +410,14 @@ func (be *baseExporter) send(ctx context.Context, req Request) error {
 func (be *baseExporter) connectSenders() {
 	be.queueSender.setNextSender(be.batchSender)
 	be.batchSender.setNextSender(be.obsrepSender)
-	be.obsrepSender.setNextSender(be.retrySender)
-	be.retrySender.setNextSender(be.timeoutSender)
+	be.obsrepSender.setNextSender(be.deadLetterSender)
+	be.deadLetterSender.setNextSender(be.circuitBreakerSender)
+	be.circuitBreakerSender.setNextSender(be.rateLimitSender)
+	be.rateLimitSender.setNextSender(be.retrySender)
+	be.retrySender.setNextSender(be.attemptSender)
+	be.attemptSender.setNextSender(be.circuitAttemptSender)
+	be.circuitAttemptSender.setNextSender(be.hedgingSender)
+	be.hedgingSender.setNextSender(be.timeoutSender)
 }
 
 func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
@@ -313,6 +426,16 @@ func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
 		return err
 	}
 
+	// If no error then start the deadLetterSender, so it's ready before any data is sent.
+	if err := be.deadLetterSender.Start(ctx, host); err != nil {
+		return err
+	}
+
+	// If no error then start the circuitBreakerSender.
+	if err := be.circuitBreakerSender.Start(ctx, host); err != nil {
+		return err
+	}
+
 	// If no error then start the batchSender.
 	if err := be.batchSender.Start(ctx, host); err != nil {
 		return err
@@ -322,14 +445,74 @@ func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
 	return be.queueSender.Start(ctx, host)
 }
 
+var _ exporterqueue.Inspector = (*baseExporter)(nil)
+
+// QueueStatus implements exporterqueue.Inspector.
+func (be *baseExporter) QueueStatus() (exporterqueue.Status, bool) {
+	qs, ok := be.queueSender.(*queueSender)
+	if !ok {
+		return exporterqueue.Status{}, false
+	}
+	st := qs.status()
+	if rs, ok := be.retrySender.(*retrySender); ok {
+		st.RetryingRequests = rs.retrying.Load()
+	}
+	if cb, ok := be.circuitBreakerSender.(*circuitBreakerSender); ok {
+		st.CircuitBreakerState = cb.currentState().String()
+	}
+	return st, true
+}
+
+// PauseQueue implements exporterqueue.Inspector.
+func (be *baseExporter) PauseQueue() error {
+	qs, ok := be.queueSender.(*queueSender)
+	if !ok {
+		return errQueueNotEnabled
+	}
+	qs.consumers.Pause()
+	be.set.Logger.Warn("Sending queue paused.")
+	return nil
+}
+
+// ResumeQueue implements exporterqueue.Inspector.
+func (be *baseExporter) ResumeQueue() error {
+	qs, ok := be.queueSender.(*queueSender)
+	if !ok {
+		return errQueueNotEnabled
+	}
+	qs.consumers.Resume()
+	be.set.Logger.Info("Sending queue resumed.")
+	return nil
+}
+
+// PurgeQueue implements exporterqueue.Inspector.
+func (be *baseExporter) PurgeQueue(ctx context.Context) (int, error) {
+	qs, ok := be.queueSender.(*queueSender)
+	if !ok {
+		return 0, errQueueNotEnabled
+	}
+	return qs.purge(ctx)
+}
+
 func (be *baseExporter) Shutdown(ctx context.Context) error {
+	be.stopped.Store(true)
+	// Drain the queue first, if configured, while the requests can still be retried.
+	if qs, ok := be.queueSender.(*queueSender); ok {
+		qs.drain(ctx)
+	}
 	return multierr.Combine(
 		// First shutdown the retry sender, so the queue sender can flush the queue without retries.
 		be.retrySender.Shutdown(ctx),
+		// Then shutdown the circuit breaker sender, so the requests waiting for the circuit to close are released.
+		be.circuitBreakerSender.Shutdown(ctx),
+		// Then shutdown the rate limit sender, so the requests waiting for the rate limit are released.
+		be.rateLimitSender.Shutdown(ctx),
 		// Then shutdown the batch sender
 		be.batchSender.Shutdown(ctx),
 		// Then shutdown the queue sender.
 		be.queueSender.Shutdown(ctx),
+		// Then shutdown the dead letter sender, once the data drained from the queue is handled.
+		be.deadLetterSender.Shutdown(ctx),
 		// Last shutdown the wrapped exporter itself.
 		be.ShutdownFunc.Shutdown(ctx))
 }
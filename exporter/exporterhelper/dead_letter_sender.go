@@ -0,0 +1,331 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentprofiles"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+const (
+	// DeadLetterReasonKey is the client.Metadata key set to the error which caused the data to be dropped,
+	// when the data is sent to a dead letter exporter.
+	DeadLetterReasonKey = "otel-dead-letter-reason"
+	// DeadLetterAttemptsKey is the client.Metadata key set to the number of attempts made to export the data,
+	// when the data is sent to a dead letter exporter.
+	DeadLetterAttemptsKey = "otel-dead-letter-attempts"
+
+	// deadLetterWriteIndexKey is the storage key of the index at which the next dead letter record is written.
+	deadLetterWriteIndexKey = "wi"
+)
+
+// DeadLetterSettings defines configuration for the destination of the data dropped by an exporter, either because
+// of a permanent error or because all the retries have been exhausted. At most one destination can be set.
+type DeadLetterSettings struct {
+	// Exporter if not empty, sends the dropped data to the given exporter, along with the reason of the failure and
+	// the number of attempts made, available in the client.Metadata under the DeadLetterReasonKey and
+	// DeadLetterAttemptsKey keys. The exporter must be part of a pipeline of the same signal.
+	Exporter *component.ID `mapstructure:"exporter"`
+	// StorageID if not empty, writes the dropped data to the given storage extension as DeadLetterRecord
+	// JSON documents, so it can be replayed later.
+	StorageID *component.ID `mapstructure:"storage"`
+}
+
+// NewDefaultDeadLetterSettings returns the default settings for DeadLetterSettings.
+func NewDefaultDeadLetterSettings() DeadLetterSettings {
+	return DeadLetterSettings{}
+}
+
+// Validate checks if the DeadLetterSettings configuration is valid
+func (dlCfg *DeadLetterSettings) Validate() error {
+	if dlCfg.Exporter != nil && dlCfg.StorageID != nil {
+		return errors.New("dead letter exporter and storage cannot be set at the same time")
+	}
+	return nil
+}
+
+// DeadLetterRecord is the document written to the dead letter storage for every dropped request.
+// The records are stored under the keys "0", "1", ... up to the index stored under the "wi" key, excluded, encoded
+// as a little-endian uint64.
+type DeadLetterRecord struct {
+	// Signal is the type of the dropped data: traces, metrics or logs.
+	Signal string `json:"signal"`
+	// Reason is the error which caused the data to be dropped.
+	Reason string `json:"reason"`
+	// Attempts is the number of attempts made to export the data.
+	Attempts int64 `json:"attempts"`
+	// Timestamp is the time at which the data was dropped.
+	Timestamp time.Time `json:"timestamp"`
+	// Payload is the OTLP protobuf encoding of the dropped data.
+	Payload []byte `json:"payload"`
+}
+
+// deadLetterSink is a destination of the dropped requests.
+type deadLetterSink interface {
+	component.Component
+	write(ctx context.Context, req Request, reason error, attempts int64) error
+}
+
+// deadLetterSender is a requestSender that hands the requests the exporter gives up on to a deadLetterSink.
+// The error is still returned, so the failure is reported as before.
+type deadLetterSender struct {
+	baseRequestSender
+	sink   deadLetterSink
+	logger *zap.Logger
+	// keepOnShutdown indicates whether the requests failing because of the shutdown are kept by the queue to be
+	// retried after restart, in which case they must not be dead-lettered.
+	keepOnShutdown bool
+}
+
+func newDeadLetterSender(sink deadLetterSink, set exporter.Settings) *deadLetterSender {
+	return &deadLetterSender{
+		sink:   sink,
+		logger: set.Logger,
+	}
+}
+
+func (ds *deadLetterSender) Start(ctx context.Context, host component.Host) error {
+	return ds.sink.Start(ctx, host)
+}
+
+func (ds *deadLetterSender) Shutdown(ctx context.Context) error {
+	return ds.sink.Shutdown(ctx)
+}
+
+func (ds *deadLetterSender) send(ctx context.Context, req Request) error {
+	err := ds.nextSender.send(ctx, req)
+	if err == nil || (ds.keepOnShutdown && experr.IsShutdownErr(err)) {
+		return err
+	}
+
+	// Only the failed part of the request is dead-lettered if some items were accepted.
+	failedReq := extractPartialRequest(req, err)
+	// The request context may be cancelled or timed out, which is likely the reason of the failure.
+	dlCtx := context.WithoutCancel(ctx)
+	if dlErr := ds.sink.write(dlCtx, failedReq, err, attemptsFromErr(err)); dlErr != nil {
+		ds.logger.Error("Failed to write dropped data to the dead letter destination",
+			zap.Error(dlErr), zap.Int("dropped_items", failedReq.ItemsCount()))
+	} else {
+		ds.logger.Warn("Exporting failed. Data written to the dead letter destination.",
+			zap.Error(err), zap.Int("dead_lettered_items", failedReq.ItemsCount()))
+	}
+	return err
+}
+
+// getExporters is the interface the host must implement to give access to the dead letter exporter.
+type getExporters interface {
+	GetExporters() map[component.DataType]map[component.ID]component.Component
+}
+
+// stoppedExporter is implemented by the exporters of this package, to know whether they are shut down.
+type stoppedExporter interface {
+	isStopped() bool
+}
+
+// exporterDeadLetterSink sends the dropped requests to another exporter.
+//
+// The exporters are shut down in no particular order, and the pipelines reload may replace the dead letter exporter,
+// so the exporter is looked up from the host on every write, and the writes fail once it is shut down. The requests
+// dropped after the dead letter exporter is shut down, e.g. while the queue is drained on shutdown, are lost.
+type exporterDeadLetterSink struct {
+	component.ShutdownFunc
+	id      component.ID
+	ownerID component.ID
+	signal  component.DataType
+
+	host getExporters
+}
+
+func (s *exporterDeadLetterSink) Start(_ context.Context, host component.Host) error {
+	if s.id == s.ownerID {
+		return fmt.Errorf("exporter %q cannot be its own dead letter exporter", s.id)
+	}
+	h, ok := host.(getExporters)
+	if !ok {
+		return errors.New("the host doesn't give access to the exporters, required by the dead letter exporter")
+	}
+	exp, found := h.GetExporters()[s.signal][s.id]
+	if !found {
+		return fmt.Errorf("dead letter exporter %q is not part of any %s pipeline", s.id, s.signal)
+	}
+	if !supportsSignal(exp, s.signal) {
+		return fmt.Errorf("dead letter exporter %q doesn't support %s", s.id, s.signal)
+	}
+	s.host = h
+	return nil
+}
+
+// supportsSignal reports whether the exporter consumes the given signal.
+func supportsSignal(exp component.Component, signal component.DataType) bool {
+	var ok bool
+	switch signal {
+	case component.DataTypeTraces:
+		_, ok = exp.(consumer.Traces)
+	case component.DataTypeMetrics:
+		_, ok = exp.(consumer.Metrics)
+	case component.DataTypeLogs:
+		_, ok = exp.(consumer.Logs)
+	case componentprofiles.DataTypeProfiles:
+		_, ok = exp.(consumerprofiles.Profiles)
+	}
+	return ok
+}
+
+// exporter returns the dead letter exporter currently running.
+func (s *exporterDeadLetterSink) exporter() (component.Component, error) {
+	if s.host == nil {
+		return nil, errors.New("dead letter exporter is not started")
+	}
+	exp, found := s.host.GetExporters()[s.signal][s.id]
+	if !found {
+		return nil, fmt.Errorf("dead letter exporter %q is not part of any %s pipeline", s.id, s.signal)
+	}
+	if se, ok := exp.(stoppedExporter); ok && se.isStopped() {
+		return nil, fmt.Errorf("dead letter exporter %q is shut down", s.id)
+	}
+	return exp, nil
+}
+
+func (s *exporterDeadLetterSink) write(ctx context.Context, req Request, reason error, attempts int64) error {
+	exp, err := s.exporter()
+	if err != nil {
+		return err
+	}
+
+	info := client.FromContext(ctx)
+	md := map[string][]string{
+		DeadLetterReasonKey:   {reason.Error()},
+		DeadLetterAttemptsKey: {strconv.FormatInt(attempts, 10)},
+	}
+	for _, k := range info.Metadata.Keys() {
+		if _, ok := md[k]; !ok {
+			md[k] = info.Metadata.Get(k)
+		}
+	}
+	info.Metadata = client.NewMetadata(md)
+	ctx = client.NewContext(ctx, info)
+
+	switch r := req.(type) {
+	case *tracesRequest:
+		if c, ok := exp.(consumer.Traces); ok {
+			return c.ConsumeTraces(ctx, r.td)
+		}
+	case *metricsRequest:
+		if c, ok := exp.(consumer.Metrics); ok {
+			return c.ConsumeMetrics(ctx, r.md)
+		}
+	case *logsRequest:
+		if c, ok := exp.(consumer.Logs); ok {
+			return c.ConsumeLogs(ctx, r.ld)
+		}
+	case *profilesRequest:
+		if c, ok := exp.(consumerprofiles.Profiles); ok {
+			return c.ConsumeProfiles(ctx, r.pd)
+		}
+	default:
+		return fmt.Errorf("unsupported request type %T", req)
+	}
+	return fmt.Errorf("dead letter exporter %q doesn't support %s", s.id, s.signal)
+}
+
+// storageDeadLetterSink writes the dropped requests to a storage extension.
+type storageDeadLetterSink struct {
+	storageID component.ID
+	ownerID   component.ID
+	signal    component.DataType
+	marshaler exporterqueue.Marshaler[Request]
+
+	// mu guards everything declared below.
+	mu         sync.Mutex
+	client     storage.Client
+	writeIndex uint64
+}
+
+func (s *storageDeadLetterSink) Start(ctx context.Context, host component.Host) error {
+	ext, found := host.GetExtensions()[s.storageID]
+	if !found {
+		return fmt.Errorf("dead letter storage %q not found", s.storageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return fmt.Errorf("dead letter storage %q is not a storage extension", s.storageID)
+	}
+	cl, err := storageExt.GetClient(ctx, component.KindExporter, s.ownerID, "dead_letter_"+s.signal.String())
+	if err != nil {
+		return err
+	}
+
+	buf, err := cl.Get(ctx, deadLetterWriteIndexKey)
+	if err != nil {
+		return errors.Join(err, cl.Close(ctx))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = cl
+	if len(buf) >= 8 {
+		s.writeIndex = binary.LittleEndian.Uint64(buf)
+	}
+	return nil
+}
+
+func (s *storageDeadLetterSink) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Close(ctx)
+	s.client = nil
+	return err
+}
+
+func (s *storageDeadLetterSink) write(ctx context.Context, req Request, reason error, attempts int64) error {
+	payload, err := s.marshaler(req)
+	if err != nil {
+		return err
+	}
+	record, err := json.Marshal(DeadLetterRecord{
+		Signal:    s.signal.String(),
+		Reason:    reason.Error(),
+		Attempts:  attempts,
+		Timestamp: time.Now(),
+		Payload:   payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == nil {
+		return errors.New("dead letter storage is not available")
+	}
+	// Add the record and update the write index in the same transaction.
+	err = s.client.Batch(ctx,
+		storage.SetOperation(strconv.FormatUint(s.writeIndex, 10), record),
+		storage.SetOperation(deadLetterWriteIndexKey, binary.LittleEndian.AppendUint64(nil, s.writeIndex+1)))
+	if err != nil {
+		return err
+	}
+	s.writeIndex++
+	return nil
+}
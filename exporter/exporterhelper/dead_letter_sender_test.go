@@ -0,0 +1,350 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/testdata"
+)
+
+var deadLetterID = component.MustNewIDWithName("dead_letter", "sink")
+
+type exportersHost struct {
+	component.Host
+	exporters map[component.DataType]map[component.ID]component.Component
+}
+
+func (h *exportersHost) GetExporters() map[component.DataType]map[component.ID]component.Component {
+	return h.exporters
+}
+
+// metadataTracesSink is a consumer.Traces recording the client.Metadata of every call.
+type metadataTracesSink struct {
+	component.StartFunc
+	component.ShutdownFunc
+	consumertest.TracesSink
+	metadata []client.Metadata
+}
+
+func (s *metadataTracesSink) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	s.metadata = append(s.metadata, client.FromContext(ctx).Metadata)
+	return s.TracesSink.ConsumeTraces(ctx, td)
+}
+
+func TestDeadLetterSettings_Validate(t *testing.T) {
+	dlCfg := NewDefaultDeadLetterSettings()
+	assert.NoError(t, dlCfg.Validate())
+
+	dlCfg.Exporter = &deadLetterID
+	assert.NoError(t, dlCfg.Validate())
+
+	storageID := component.MustNewID("file_storage")
+	dlCfg.StorageID = &storageID
+	assert.EqualError(t, dlCfg.Validate(), "dead letter exporter and storage cannot be set at the same time")
+}
+
+func TestDeadLetter_ExporterPermanentError(t *testing.T) {
+	sink := &metadataTracesSink{}
+	host := &exportersHost{Host: componenttest.NewNopHost(), exporters: map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeTraces: {deadLetterID: sink},
+	}}
+	permanentErr := consumererror.NewPermanent(errors.New("bad data"))
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(permanentErr), WithRetry(configretry.NewDefaultBackOffConfig()),
+		WithDeadLetter(DeadLetterSettings{Exporter: &deadLetterID}))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), host))
+
+	ctx := client.NewContext(context.Background(), client.Info{Metadata: client.NewMetadata(map[string][]string{"tenant": {"acme"}})})
+	td := testdata.GenerateTraces(2)
+	require.ErrorIs(t, te.ConsumeTraces(ctx, td), permanentErr)
+	require.NoError(t, te.Shutdown(context.Background()))
+
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, td, sink.AllTraces()[0])
+	require.Len(t, sink.metadata, 1)
+	assert.Equal(t, []string{"not retryable error: Permanent error: bad data"}, sink.metadata[0].Get(DeadLetterReasonKey))
+	assert.Equal(t, []string{"1"}, sink.metadata[0].Get(DeadLetterAttemptsKey))
+	// The original metadata is preserved.
+	assert.Equal(t, []string{"acme"}, sink.metadata[0].Get("tenant"))
+}
+
+func TestDeadLetter_ExporterRetriesExhausted(t *testing.T) {
+	sink := &metadataTracesSink{}
+	host := &exportersHost{Host: componenttest.NewNopHost(), exporters: map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeTraces: {deadLetterID: sink},
+	}}
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxInterval = time.Millisecond
+	rCfg.RandomizationFactor = 0
+	rCfg.MaxElapsedTime = 50 * time.Millisecond
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(errors.New("transient error")), WithRetry(rCfg),
+		WithDeadLetter(DeadLetterSettings{Exporter: &deadLetterID}))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), host))
+
+	require.Error(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	require.NoError(t, te.Shutdown(context.Background()))
+
+	require.Len(t, sink.metadata, 1)
+	assert.Equal(t, []string{"no more retries left: transient error"}, sink.metadata[0].Get(DeadLetterReasonKey))
+	assert.NotEqual(t, []string{"1"}, sink.metadata[0].Get(DeadLetterAttemptsKey))
+}
+
+func TestDeadLetter_ExporterPartialError(t *testing.T) {
+	sink := &metadataTracesSink{}
+	host := &exportersHost{Host: componenttest.NewNopHost(), exporters: map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeTraces: {deadLetterID: sink},
+	}}
+	failed := testdata.GenerateTraces(1)
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(consumererror.NewPermanent(consumererror.NewTraces(errors.New("partial"), failed))),
+		WithDeadLetter(DeadLetterSettings{Exporter: &deadLetterID}))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), host))
+	require.Error(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(3)))
+	require.NoError(t, te.Shutdown(context.Background()))
+
+	// Only the failed part of the data is dead-lettered.
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, failed, sink.AllTraces()[0])
+}
+
+func TestDeadLetter_ExporterReplacedOrStopped(t *testing.T) {
+	sink := &metadataTracesSink{}
+	host := &exportersHost{Host: componenttest.NewNopHost(), exporters: map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeTraces: {deadLetterID: sink},
+	}}
+	permanentErr := consumererror.NewPermanent(errors.New("bad data"))
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(permanentErr), WithDeadLetter(DeadLetterSettings{Exporter: &deadLetterID}))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), host))
+	t.Cleanup(func() {
+		assert.NoError(t, te.Shutdown(context.Background()))
+	})
+
+	// The dead letter exporter is replaced, e.g. by a reload of the pipelines.
+	dlSink := new(consumertest.TracesSink)
+	dlExp, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+		dlSink.ConsumeTraces)
+	require.NoError(t, err)
+	require.NoError(t, dlExp.Start(context.Background(), host))
+	host.exporters[component.DataTypeTraces][deadLetterID] = dlExp
+
+	require.ErrorIs(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)), permanentErr)
+	assert.Empty(t, sink.AllTraces())
+	assert.Len(t, dlSink.AllTraces(), 1)
+
+	// Once the dead letter exporter is shut down, the data is not sent to it anymore.
+	require.NoError(t, dlExp.Shutdown(context.Background()))
+	require.ErrorIs(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)), permanentErr)
+	assert.Len(t, dlSink.AllTraces(), 1)
+}
+
+func TestDeadLetter_ExporterOtherSignals(t *testing.T) {
+	metricsSink := new(consumertest.MetricsSink)
+	logsSink := new(consumertest.LogsSink)
+	host := &exportersHost{Host: componenttest.NewNopHost(), exporters: map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeMetrics: {deadLetterID: &componentMetricsSink{MetricsSink: metricsSink}},
+		component.DataTypeLogs:    {deadLetterID: &componentLogsSink{LogsSink: logsSink}},
+	}}
+	exportErr := consumererror.NewPermanent(errors.New("bad data"))
+
+	me, err := NewMetricsExporter(context.Background(), exportertest.NewNopSettings(), &fakeMetricsExporterConfig,
+		newPushMetricsData(exportErr), WithDeadLetter(DeadLetterSettings{Exporter: &deadLetterID}))
+	require.NoError(t, err)
+	require.NoError(t, me.Start(context.Background(), host))
+	require.Error(t, me.ConsumeMetrics(context.Background(), testdata.GenerateMetrics(2)))
+	require.NoError(t, me.Shutdown(context.Background()))
+	assert.Equal(t, 4, metricsSink.DataPointCount())
+
+	le, err := NewLogsExporter(context.Background(), exportertest.NewNopSettings(), &fakeLogsExporterConfig,
+		newPushLogsData(exportErr), WithDeadLetter(DeadLetterSettings{Exporter: &deadLetterID}))
+	require.NoError(t, err)
+	require.NoError(t, le.Start(context.Background(), host))
+	require.Error(t, le.ConsumeLogs(context.Background(), testdata.GenerateLogs(2)))
+	require.NoError(t, le.Shutdown(context.Background()))
+	assert.Equal(t, 2, logsSink.LogRecordCount())
+}
+
+func TestDeadLetter_ExporterStartErrors(t *testing.T) {
+	tracesSink := new(consumertest.TracesSink)
+	tests := []struct {
+		name    string
+		id      component.ID
+		host    component.Host
+		wantErr string
+	}{
+		{
+			name:    "no_exporters_access",
+			id:      deadLetterID,
+			host:    &mockHost{},
+			wantErr: "the host doesn't give access to the exporters, required by the dead letter exporter",
+		},
+		{
+			name:    "not_found",
+			id:      deadLetterID,
+			host:    &exportersHost{Host: componenttest.NewNopHost()},
+			wantErr: `dead letter exporter "dead_letter/sink" is not part of any traces pipeline`,
+		},
+		{
+			name: "unsupported_signal",
+			id:   deadLetterID,
+			host: &exportersHost{Host: componenttest.NewNopHost(), exporters: map[component.DataType]map[component.ID]component.Component{
+				component.DataTypeTraces: {deadLetterID: &fakeDeadLetterSink{}},
+			}},
+			wantErr: `dead letter exporter "dead_letter/sink" doesn't support traces`,
+		},
+		{
+			name: "self",
+			id:   defaultID,
+			host: &exportersHost{Host: componenttest.NewNopHost(), exporters: map[component.DataType]map[component.ID]component.Component{
+				component.DataTypeTraces: {defaultID: &componentTracesSink{TracesSink: tracesSink}},
+			}},
+			wantErr: `exporter "test" cannot be its own dead letter exporter`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			te, err := NewTracesExporter(context.Background(), defaultSettings, &fakeTracesExporterConfig,
+				newTraceDataPusher(nil), WithDeadLetter(DeadLetterSettings{Exporter: &tt.id}))
+			require.NoError(t, err)
+			assert.EqualError(t, te.Start(context.Background(), tt.host), tt.wantErr)
+		})
+	}
+}
+
+func TestDeadLetter_Storage(t *testing.T) {
+	storageID := component.MustNewIDWithName("file_storage", "dead_letter")
+	ext := queue.NewMockStorageExtension(nil)
+	host := &mockHost{ext: map[component.ID]component.Component{storageID: ext}}
+	newExporter := func() component.Component {
+		te, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+			newTraceDataPusher(consumererror.NewPermanent(errors.New("bad data"))),
+			WithDeadLetter(DeadLetterSettings{StorageID: &storageID}))
+		require.NoError(t, err)
+		require.NoError(t, te.Start(context.Background(), host))
+		require.Error(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+		require.Error(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+		return te
+	}
+
+	// The write index is restored after restart, the records are not overwritten.
+	require.NoError(t, newExporter().Shutdown(context.Background()))
+	require.NoError(t, newExporter().Shutdown(context.Background()))
+
+	cl, err := ext.(storage.Extension).GetClient(context.Background(), component.KindExporter, exportertest.NewNopSettings().ID, "dead_letter_traces")
+	require.NoError(t, err)
+	wi, err := cl.Get(context.Background(), deadLetterWriteIndexKey)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), binary.LittleEndian.Uint64(wi))
+
+	for i, key := range []string{"0", "1", "2", "3"} {
+		buf, err := cl.Get(context.Background(), key)
+		require.NoError(t, err)
+		var record DeadLetterRecord
+		require.NoError(t, json.Unmarshal(buf, &record))
+		assert.Equal(t, "traces", record.Signal)
+		assert.Equal(t, "Permanent error: bad data", record.Reason)
+		assert.Equal(t, int64(1), record.Attempts)
+		assert.False(t, record.Timestamp.IsZero())
+		td, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(record.Payload)
+		require.NoError(t, err)
+		assert.Equal(t, testdata.GenerateTraces(i%2+1), td)
+	}
+}
+
+func TestDeadLetter_StorageNotFound(t *testing.T) {
+	storageID := component.MustNewIDWithName("file_storage", "dead_letter")
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(nil), WithDeadLetter(DeadLetterSettings{StorageID: &storageID}))
+	require.NoError(t, err)
+	assert.EqualError(t, te.Start(context.Background(), componenttest.NewNopHost()), `dead letter storage "file_storage/dead_letter" not found`)
+}
+
+func TestDeadLetter_ShutdownError(t *testing.T) {
+	for _, keepOnShutdown := range []bool{false, true} {
+		sink := &fakeDeadLetterSink{}
+		ds := newDeadLetterSender(sink, exportertest.NewNopSettings())
+		ds.keepOnShutdown = keepOnShutdown
+		ds.setNextSender(&errorSender{err: experr.NewShutdownErr(errors.New("stopped"))})
+		require.Error(t, ds.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), nil)))
+		// The requests kept by the persistent queue on shutdown must not be dead-lettered.
+		assert.Equal(t, !keepOnShutdown, sink.written == 1)
+	}
+}
+
+func TestDeadLetter_NotAvailableForRequestExporters(t *testing.T) {
+	_, err := NewTracesRequestExporter(context.Background(), exportertest.NewNopSettings(),
+		(&fakeRequestConverter{}).requestFromTracesFunc, WithDeadLetter(DeadLetterSettings{Exporter: &deadLetterID}))
+	assert.EqualError(t, err, "WithDeadLetter option is not available for the new request exporters")
+}
+
+type fakeDeadLetterSink struct {
+	component.StartFunc
+	component.ShutdownFunc
+	written int
+}
+
+func (s *fakeDeadLetterSink) write(context.Context, Request, error, int64) error {
+	s.written++
+	return nil
+}
+
+type errorSender struct {
+	baseRequestSender
+	err error
+}
+
+func (s *errorSender) send(context.Context, Request) error {
+	return s.err
+}
+
+type componentTracesSink struct {
+	component.StartFunc
+	component.ShutdownFunc
+	*consumertest.TracesSink
+}
+
+type componentMetricsSink struct {
+	component.StartFunc
+	component.ShutdownFunc
+	*consumertest.MetricsSink
+}
+
+type componentLogsSink struct {
+	component.StartFunc
+	component.ShutdownFunc
+	*consumertest.LogsSink
+}
+
+var (
+	_ consumer.Traces  = (*componentTracesSink)(nil)
+	_ consumer.Metrics = (*componentMetricsSink)(nil)
+	_ consumer.Logs    = (*componentLogsSink)(nil)
+)
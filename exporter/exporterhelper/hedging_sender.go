@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+)
+
+// HedgingSettings defines configuration for sending a duplicate of the requests that take too long to complete,
+// so the latency is not dominated by a few slow destinations. The first attempt to succeed wins and the other
+// ones are cancelled. The exporters using it must not mutate the data, since the attempts share the request.
+type HedgingSettings struct {
+	// Enabled indicates whether to send a duplicate of the slow requests.
+	Enabled bool `mapstructure:"enabled"`
+	// Delay is how long to wait for an attempt to complete before sending the next duplicate.
+	Delay time.Duration `mapstructure:"delay"`
+	// MaxHedges is the maximum number of duplicates sent for a request, in addition to the original attempt.
+	MaxHedges int `mapstructure:"max_hedges"`
+}
+
+// NewDefaultHedgingSettings returns the default settings for HedgingSettings.
+func NewDefaultHedgingSettings() HedgingSettings {
+	return HedgingSettings{
+		Enabled:   false,
+		Delay:     time.Second,
+		MaxHedges: 1,
+	}
+}
+
+// Validate checks if the HedgingSettings configuration is valid
+func (hCfg *HedgingSettings) Validate() error {
+	if !hCfg.Enabled {
+		return nil
+	}
+	if hCfg.Delay <= 0 {
+		return errors.New("hedging delay must be positive")
+	}
+	if hCfg.MaxHedges <= 0 {
+		return errors.New("hedging max_hedges must be positive")
+	}
+	return nil
+}
+
+type hedgeAttemptKey struct{}
+
+// HedgeAttempt returns the attempt the context of an export was created for by the hedging, 0 being the original
+// attempt and n the n-th duplicate. The exporters can use it to send the duplicates to an alternate destination.
+func HedgeAttempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(hedgeAttemptKey{}).(int)
+	return attempt
+}
+
+// hedgingSender is a requestSender that sends a duplicate of the request every time the previous attempts didn't
+// complete within the configured delay, up to the configured number of duplicates. It's placed before the
+// timeoutSender, so every attempt gets its own timeout.
+type hedgingSender struct {
+	baseRequestSender
+	cfg    HedgingSettings
+	obsrep *obsReport
+	attrs  metric.MeasurementOption
+}
+
+func newHedgingSender(cfg HedgingSettings, set exporter.Settings, obsrep *obsReport) *hedgingSender {
+	return &hedgingSender{
+		cfg:    cfg,
+		obsrep: obsrep,
+		attrs: metric.WithAttributeSet(attribute.NewSet(attribute.String(obsmetrics.ExporterKey, set.ID.String()),
+			attribute.String(obsmetrics.DataTypeKey, obsrep.dataType.String()))),
+	}
+}
+
+func (hs *hedgingSender) send(ctx context.Context, req Request) error {
+	attemptsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan error, hs.cfg.MaxHedges+1)
+	attempt := func(n int) {
+		go func() {
+			results <- hs.nextSender.send(context.WithValue(attemptsCtx, hedgeAttemptKey{}, n), req)
+		}()
+	}
+	attempt(0)
+	sent, inFlight := 1, 1
+
+	timer := time.NewTimer(hs.cfg.Delay)
+	defer timer.Stop()
+	var err error
+	for inFlight > 0 {
+		select {
+		case <-timer.C:
+			hs.obsrep.telemetryBuilder.ExporterHedgedRequests.Add(ctx, 1, hs.attrs)
+			attempt(sent)
+			sent++
+			inFlight++
+			if sent <= hs.cfg.MaxHedges {
+				timer.Reset(hs.cfg.Delay)
+			}
+		case err = <-results:
+			inFlight--
+			// The data rejected by the destination would be rejected by the other attempts too.
+			if err == nil || consumererror.IsPermanent(err) {
+				cancel()
+				// Wait for the cancelled attempts, so they don't outlive the request.
+				for ; inFlight > 0; inFlight-- {
+					<-results
+				}
+				return err
+			}
+		}
+	}
+	// All the attempts failed, the last error is returned to be retried.
+	return err
+}
@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+)
+
+func TestHedgingSettings_Validate(t *testing.T) {
+	hCfg := NewDefaultHedgingSettings()
+	assert.NoError(t, hCfg.Validate())
+
+	hCfg.Enabled = true
+	assert.NoError(t, hCfg.Validate())
+
+	hCfg.Delay = 0
+	assert.EqualError(t, hCfg.Validate(), "hedging delay must be positive")
+
+	hCfg = NewDefaultHedgingSettings()
+	hCfg.Enabled = true
+	hCfg.MaxHedges = 0
+	assert.EqualError(t, hCfg.Validate(), "hedging max_hedges must be positive")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	hCfg.Enabled = false
+	assert.NoError(t, hCfg.Validate())
+}
+
+// attemptsSender handles every attempt with the function set for it, and records the attempts it received.
+type attemptsSender struct {
+	baseRequestSender
+	handlers []func(ctx context.Context) error
+
+	mu        sync.Mutex
+	attempts  []int
+	cancelled []int
+}
+
+func (s *attemptsSender) send(ctx context.Context, _ Request) error {
+	attempt := HedgeAttempt(ctx)
+	s.mu.Lock()
+	s.attempts = append(s.attempts, attempt)
+	s.mu.Unlock()
+	err := s.handlers[attempt](ctx)
+	if errors.Is(err, context.Canceled) {
+		s.mu.Lock()
+		s.cancelled = append(s.cancelled, attempt)
+		s.mu.Unlock()
+	}
+	return err
+}
+
+func (s *attemptsSender) get() ([]int, []int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.attempts...), append([]int(nil), s.cancelled...)
+}
+
+func respondAfter(d time.Duration, err error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+			return err
+		}
+	}
+}
+
+func newTestHedgingSender(t *testing.T, cfg HedgingSettings, handlers ...func(context.Context) error) (*hedgingSender, *attemptsSender, componentTestTelemetry) {
+	tt := setupTestTelemetry()
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+	set := tt.NewSettings()
+	set.ID = defaultID
+	obsrep, err := newObsReport(obsReportSettings{exporterID: defaultID, exporterCreateSettings: set, dataType: defaultDataType})
+	require.NoError(t, err)
+	hs := newHedgingSender(cfg, set, obsrep)
+	next := &attemptsSender{handlers: handlers}
+	hs.setNextSender(next)
+	return hs, next, tt
+}
+
+func hedgedRequestsMetric(hedges int64) metricdata.Metrics {
+	return metricdata.Metrics{
+		Name:        "otelcol_exporter_hedged_requests",
+		Description: "Number of duplicate requests sent because the previous attempts did not complete within the hedging delay",
+		Unit:        "{requests}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{
+					Attributes: attribute.NewSet(
+						attribute.String(obsmetrics.ExporterKey, defaultID.String()),
+						attribute.String(obsmetrics.DataTypeKey, defaultDataType.String())),
+					Value: hedges,
+				},
+			},
+		},
+	}
+}
+
+func TestHedgingSender_NoHedgeWhenFast(t *testing.T) {
+	hs, next, tt := newTestHedgingSender(t, HedgingSettings{Enabled: true, Delay: time.Hour, MaxHedges: 1},
+		respondAfter(0, nil))
+
+	require.NoError(t, hs.send(context.Background(), newMockRequest(1, nil)))
+	attempts, _ := next.get()
+	assert.Equal(t, []int{0}, attempts)
+
+	var md metricdata.ResourceMetrics
+	require.NoError(t, tt.reader.Collect(context.Background(), &md))
+	assert.Equal(t, metricdata.Metrics{}, tt.getMetric("otelcol_exporter_hedged_requests", md))
+}
+
+func TestHedgingSender_FirstSuccessWins(t *testing.T) {
+	hs, next, tt := newTestHedgingSender(t, HedgingSettings{Enabled: true, Delay: 10 * time.Millisecond, MaxHedges: 2},
+		respondAfter(time.Hour, nil),
+		respondAfter(0, nil),
+		respondAfter(time.Hour, nil))
+
+	require.NoError(t, hs.send(context.Background(), newMockRequest(1, nil)))
+	// The original attempt is cancelled once the first duplicate succeeds, before the second duplicate is sent.
+	attempts, cancelled := next.get()
+	assert.Equal(t, []int{0, 1}, attempts)
+	assert.Equal(t, []int{0}, cancelled)
+
+	var md metricdata.ResourceMetrics
+	require.NoError(t, tt.reader.Collect(context.Background(), &md))
+	metricdatatest.AssertEqual(t, hedgedRequestsMetric(1), tt.getMetric("otelcol_exporter_hedged_requests", md),
+		metricdatatest.IgnoreTimestamp())
+}
+
+func TestHedgingSender_MaxHedges(t *testing.T) {
+	hs, next, tt := newTestHedgingSender(t, HedgingSettings{Enabled: true, Delay: 10 * time.Millisecond, MaxHedges: 2},
+		respondAfter(200*time.Millisecond, errors.New("unavailable")),
+		respondAfter(200*time.Millisecond, errors.New("unavailable")),
+		respondAfter(200*time.Millisecond, errors.New("still unavailable")))
+
+	// All the attempts fail, the last error is returned.
+	assert.EqualError(t, hs.send(context.Background(), newMockRequest(1, nil)), "still unavailable")
+	attempts, cancelled := next.get()
+	assert.Equal(t, []int{0, 1, 2}, attempts)
+	assert.Empty(t, cancelled)
+
+	var md metricdata.ResourceMetrics
+	require.NoError(t, tt.reader.Collect(context.Background(), &md))
+	metricdatatest.AssertEqual(t, hedgedRequestsMetric(2), tt.getMetric("otelcol_exporter_hedged_requests", md),
+		metricdatatest.IgnoreTimestamp())
+}
+
+func TestHedgingSender_PermanentError(t *testing.T) {
+	hs, next, _ := newTestHedgingSender(t, HedgingSettings{Enabled: true, Delay: 10 * time.Millisecond, MaxHedges: 1},
+		respondAfter(time.Hour, nil),
+		respondAfter(0, consumererror.NewPermanent(errors.New("bad data"))))
+
+	err := hs.send(context.Background(), newMockRequest(1, nil))
+	assert.True(t, consumererror.IsPermanent(err))
+	_, cancelled := next.get()
+	assert.Equal(t, []int{0}, cancelled)
+}
+
+func TestHedgingSender_FastFailureNotHedged(t *testing.T) {
+	hs, next, _ := newTestHedgingSender(t, HedgingSettings{Enabled: true, Delay: time.Hour, MaxHedges: 1},
+		respondAfter(0, errors.New("unavailable")))
+
+	assert.EqualError(t, hs.send(context.Background(), newMockRequest(1, nil)), "unavailable")
+	attempts, _ := next.get()
+	assert.Equal(t, []int{0}, attempts)
+}
+
+func TestHedgeAttempt(t *testing.T) {
+	assert.Equal(t, 0, HedgeAttempt(context.Background()))
+}
@@ -26,11 +26,16 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 // as defined in metadata and user config.
 type TelemetryBuilder struct {
 	meter                             metric.Meter
+	ExporterCircuitBreakerState       metric.Int64ObservableGauge
 	ExporterEnqueueFailedLogRecords   metric.Int64Counter
 	ExporterEnqueueFailedMetricPoints metric.Int64Counter
 	ExporterEnqueueFailedSpans        metric.Int64Counter
+	ExporterHedgedRequests            metric.Int64Counter
 	ExporterQueueCapacity             metric.Int64ObservableGauge
+	ExporterQueueCapacityBytes        metric.Int64ObservableGauge
+	ExporterQueueConcurrencyLimit     metric.Int64ObservableGauge
 	ExporterQueueSize                 metric.Int64ObservableGauge
+	ExporterQueueSizeBytes            metric.Int64ObservableGauge
 	ExporterSendFailedLogRecords      metric.Int64Counter
 	ExporterSendFailedMetricPoints    metric.Int64Counter
 	ExporterSendFailedSpans           metric.Int64Counter
@@ -50,12 +55,30 @@ func WithLevel(lvl configtelemetry.Level) telemetryBuilderOption {
 	}
 }
 
+// InitExporterCircuitBreakerState configures the ExporterCircuitBreakerState metric.
+func (builder *TelemetryBuilder) InitExporterCircuitBreakerState(cb func() int64, opts ...metric.ObserveOption) error {
+	var err error
+	builder.ExporterCircuitBreakerState, err = builder.meter.Int64ObservableGauge(
+		"otelcol_exporter_circuit_breaker_state",
+		metric.WithDescription("Current state of the circuit breaker, reported when it is enabled (0 closed, 1 half-open, 2 open)"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = builder.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(builder.ExporterCircuitBreakerState, cb(), opts...)
+		return nil
+	}, builder.ExporterCircuitBreakerState)
+	return err
+}
+
 // InitExporterQueueCapacity configures the ExporterQueueCapacity metric.
 func (builder *TelemetryBuilder) InitExporterQueueCapacity(cb func() int64, opts ...metric.ObserveOption) error {
 	var err error
 	builder.ExporterQueueCapacity, err = builder.meter.Int64ObservableGauge(
 		"otelcol_exporter_queue_capacity",
-		metric.WithDescription("Fixed capacity of the retry queue (in batches)"),
+		metric.WithDescription("Fixed capacity of the retry queue (in batches), reported when the queue is sized in batches"),
 		metric.WithUnit("{batches}"),
 	)
 	if err != nil {
@@ -68,6 +91,42 @@ func (builder *TelemetryBuilder) InitExporterQueueCapacity(cb func() int64, opts
 	return err
 }
 
+// InitExporterQueueCapacityBytes configures the ExporterQueueCapacityBytes metric.
+func (builder *TelemetryBuilder) InitExporterQueueCapacityBytes(cb func() int64, opts ...metric.ObserveOption) error {
+	var err error
+	builder.ExporterQueueCapacityBytes, err = builder.meter.Int64ObservableGauge(
+		"otelcol_exporter_queue_capacity_bytes",
+		metric.WithDescription("Fixed capacity of the retry queue (in bytes), reported when the queue is sized in bytes"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = builder.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(builder.ExporterQueueCapacityBytes, cb(), opts...)
+		return nil
+	}, builder.ExporterQueueCapacityBytes)
+	return err
+}
+
+// InitExporterQueueConcurrencyLimit configures the ExporterQueueConcurrencyLimit metric.
+func (builder *TelemetryBuilder) InitExporterQueueConcurrencyLimit(cb func() int64, opts ...metric.ObserveOption) error {
+	var err error
+	builder.ExporterQueueConcurrencyLimit, err = builder.meter.Int64ObservableGauge(
+		"otelcol_exporter_queue_concurrency_limit",
+		metric.WithDescription("Current limit of batches exported concurrently from the retry queue, reported when adaptive concurrency is enabled"),
+		metric.WithUnit("{batches}"),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = builder.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(builder.ExporterQueueConcurrencyLimit, cb(), opts...)
+		return nil
+	}, builder.ExporterQueueConcurrencyLimit)
+	return err
+}
+
 // InitExporterQueueSize configures the ExporterQueueSize metric.
 func (builder *TelemetryBuilder) InitExporterQueueSize(cb func() int64, opts ...metric.ObserveOption) error {
 	var err error
@@ -86,6 +145,24 @@ func (builder *TelemetryBuilder) InitExporterQueueSize(cb func() int64, opts ...
 	return err
 }
 
+// InitExporterQueueSizeBytes configures the ExporterQueueSizeBytes metric.
+func (builder *TelemetryBuilder) InitExporterQueueSizeBytes(cb func() int64, opts ...metric.ObserveOption) error {
+	var err error
+	builder.ExporterQueueSizeBytes, err = builder.meter.Int64ObservableGauge(
+		"otelcol_exporter_queue_size_bytes",
+		metric.WithDescription("Current size of the retry queue (in bytes), reported when the queue is sized in bytes"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = builder.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(builder.ExporterQueueSizeBytes, cb(), opts...)
+		return nil
+	}, builder.ExporterQueueSizeBytes)
+	return err
+}
+
 // NewTelemetryBuilder provides a struct with methods to update all internal telemetry
 // for a component
 func NewTelemetryBuilder(settings component.TelemetrySettings, options ...telemetryBuilderOption) (*TelemetryBuilder, error) {
@@ -117,6 +194,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...teleme
 		metric.WithUnit("{spans}"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ExporterHedgedRequests, err = builder.meter.Int64Counter(
+		"otelcol_exporter_hedged_requests",
+		metric.WithDescription("Number of duplicate requests sent because the previous attempts did not complete within the hedging delay"),
+		metric.WithUnit("{requests}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ExporterSendFailedLogRecords, err = builder.meter.Int64Counter(
 		"otelcol_exporter_send_failed_log_records",
 		metric.WithDescription("Number of log records in failed attempts to send to destination."),
@@ -63,6 +63,11 @@ func (req *logsRequest) ItemsCount() int {
 	return req.ld.LogRecordCount()
 }
 
+// BytesSize returns the size of the request once proto-marshaled, used to size the queue in bytes.
+func (req *logsRequest) BytesSize() int {
+	return logsMarshaler.LogsSize(req.ld)
+}
+
 type logsExporter struct {
 	*baseExporter
 	consumer.Logs
@@ -25,9 +25,9 @@ func mergeLogs(_ context.Context, r1 Request, r2 Request) (Request, error) {
 // mergeSplitLogs splits and/or merges the logs into multiple requests based on the MaxSizeConfig.
 func mergeSplitLogs(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Request, r2 Request) ([]Request, error) {
 	var (
-		res          []Request
-		destReq      *logsRequest
-		capacityLeft = cfg.MaxSizeItems
+		res      []Request
+		destReq  *logsRequest
+		capacity = newBatchCapacity(cfg)
 	)
 	for _, req := range []Request{r1, r2} {
 		if req == nil {
@@ -37,33 +37,35 @@ func mergeSplitLogs(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Req
 		if !ok {
 			return nil, errors.New("invalid input type")
 		}
-		if srcReq.ld.LogRecordCount() <= capacityLeft {
+		if capacity.addAll(srcReq.ld.LogRecordCount(), func() int { return logsMarshaler.LogsSize(srcReq.ld) }) {
 			if destReq == nil {
 				destReq = srcReq
 			} else {
 				srcReq.ld.ResourceLogs().MoveAndAppendTo(destReq.ld.ResourceLogs())
 			}
-			capacityLeft -= destReq.ld.LogRecordCount()
 			continue
 		}
 
-		for {
-			extractedLogs := extractLogs(srcReq.ld, capacityLeft)
+		for srcReq.ld.LogRecordCount() > 0 {
+			extractedLogs := extractLogs(srcReq.ld, &capacity)
 			if extractedLogs.LogRecordCount() == 0 {
-				break
+				if destReq == nil {
+					// The next log record doesn't fit even in an empty batch, it's sent alone.
+					single := batchCapacity{items: 1}
+					res = append(res, &logsRequest{ld: extractLogs(srcReq.ld, &single), pusher: srcReq.pusher})
+					continue
+				}
+				// Create new batch once capacity is reached.
+				res = append(res, destReq)
+				destReq = nil
+				capacity = newBatchCapacity(cfg)
+				continue
 			}
-			capacityLeft -= extractedLogs.LogRecordCount()
 			if destReq == nil {
 				destReq = &logsRequest{ld: extractedLogs, pusher: srcReq.pusher}
 			} else {
 				extractedLogs.ResourceLogs().MoveAndAppendTo(destReq.ld.ResourceLogs())
 			}
-			// Create new batch once capacity is reached.
-			if capacityLeft == 0 {
-				res = append(res, destReq)
-				destReq = nil
-				capacityLeft = cfg.MaxSizeItems
-			}
 		}
 	}
 
@@ -73,57 +75,70 @@ func mergeSplitLogs(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Req
 	return res, nil
 }
 
-// extractLogs extracts logs from the input logs and returns a new logs with the specified number of log records.
-func extractLogs(srcLogs plog.Logs, count int) plog.Logs {
+// extractLogs extracts logs from the input logs and returns a new logs with the log records fitting in the capacity,
+// and takes their room from it.
+func extractLogs(srcLogs plog.Logs, capacity *batchCapacity) plog.Logs {
 	destLogs := plog.NewLogs()
+	full := false
 	srcLogs.ResourceLogs().RemoveIf(func(srcRL plog.ResourceLogs) bool {
-		if count == 0 {
+		if full {
 			return false
 		}
-		needToExtract := resourceLogsCount(srcRL) > count
-		if needToExtract {
-			srcRL = extractResourceLogs(srcRL, count)
+		if capacity.add(resourceLogsCount(srcRL), func() int { return logsMarshaler.ResourceLogsSize(srcRL) }) {
+			srcRL.MoveTo(destLogs.ResourceLogs().AppendEmpty())
+			return true
+		}
+		full = true
+		if destRL := extractResourceLogs(srcRL, capacity); resourceLogsCount(destRL) > 0 {
+			destRL.MoveTo(destLogs.ResourceLogs().AppendEmpty())
 		}
-		count -= resourceLogsCount(srcRL)
-		srcRL.MoveTo(destLogs.ResourceLogs().AppendEmpty())
-		return !needToExtract
+		return false
 	})
 	return destLogs
 }
 
-// extractResourceLogs extracts resource logs and returns a new resource logs with the specified number of log records.
-func extractResourceLogs(srcRL plog.ResourceLogs, count int) plog.ResourceLogs {
+// extractResourceLogs extracts resource logs and returns a new resource logs with the log records fitting
+// in the capacity.
+func extractResourceLogs(srcRL plog.ResourceLogs, capacity *batchCapacity) plog.ResourceLogs {
 	destRL := plog.NewResourceLogs()
 	destRL.SetSchemaUrl(srcRL.SchemaUrl())
 	srcRL.Resource().CopyTo(destRL.Resource())
+	nested := capacity.nested(func() int { return logsMarshaler.ResourceLogsSize(destRL) })
+	full := false
 	srcRL.ScopeLogs().RemoveIf(func(srcSL plog.ScopeLogs) bool {
-		if count == 0 {
+		if full {
 			return false
 		}
-		needToExtract := srcSL.LogRecords().Len() > count
-		if needToExtract {
-			srcSL = extractScopeLogs(srcSL, count)
+		if nested.add(srcSL.LogRecords().Len(), func() int { return logsMarshaler.ScopeLogsSize(srcSL) }) {
+			srcSL.MoveTo(destRL.ScopeLogs().AppendEmpty())
+			return true
+		}
+		full = true
+		if destSL := extractScopeLogs(srcSL, &nested); destSL.LogRecords().Len() > 0 {
+			destSL.MoveTo(destRL.ScopeLogs().AppendEmpty())
 		}
-		count -= srcSL.LogRecords().Len()
-		srcSL.MoveTo(destRL.ScopeLogs().AppendEmpty())
-		return !needToExtract
+		return false
 	})
+	capacity.takeNested(nested, func() int { return logsMarshaler.ResourceLogsSize(destRL) })
 	return destRL
 }
 
-// extractScopeLogs extracts scope logs and returns a new scope logs with the specified number of log records.
-func extractScopeLogs(srcSL plog.ScopeLogs, count int) plog.ScopeLogs {
+// extractScopeLogs extracts scope logs and returns a new scope logs with the log records fitting in the capacity.
+func extractScopeLogs(srcSL plog.ScopeLogs, capacity *batchCapacity) plog.ScopeLogs {
 	destSL := plog.NewScopeLogs()
 	destSL.SetSchemaUrl(srcSL.SchemaUrl())
 	srcSL.Scope().CopyTo(destSL.Scope())
+	nested := capacity.nested(func() int { return logsMarshaler.ScopeLogsSize(destSL) })
+	full := false
 	srcSL.LogRecords().RemoveIf(func(srcLR plog.LogRecord) bool {
-		if count == 0 {
+		if full || !nested.add(1, func() int { return logsMarshaler.LogRecordSize(srcLR) }) {
+			full = true
 			return false
 		}
 		srcLR.MoveTo(destSL.LogRecords().AppendEmpty())
-		count--
 		return true
 	})
+	capacity.takeNested(nested, func() int { return logsMarshaler.ScopeLogsSize(destSL) })
 	return destSL
 }
 
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -142,6 +143,47 @@ func TestMergeSplitLogs(t *testing.T) {
 	}
 }
 
+func TestMergeSplitLogsMaxSizeBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  exporterbatcher.MaxSizeConfig
+	}{
+		{
+			name: "bytes_only",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 1000},
+		},
+		{
+			name: "bytes_and_items",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 3000, MaxSizeItems: 5},
+		},
+		{
+			name: "log_record_bigger_than_limit",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r1 := &logsRequest{ld: testdata.GenerateLogs(3)}
+			r2 := &logsRequest{ld: testdata.GenerateLogs(50)}
+			total := r1.ld.LogRecordCount() + r2.ld.LogRecordCount()
+			res, err := mergeSplitLogs(context.Background(), tt.cfg, r1, r2)
+			require.NoError(t, err)
+			count := 0
+			for _, r := range res {
+				ld := r.(*logsRequest).ld
+				count += ld.LogRecordCount()
+				if ld.LogRecordCount() > 1 {
+					assert.LessOrEqual(t, logsMarshaler.LogsSize(ld), tt.cfg.MaxSizeBytes)
+				}
+				if tt.cfg.MaxSizeItems > 0 {
+					assert.LessOrEqual(t, ld.LogRecordCount(), tt.cfg.MaxSizeItems)
+				}
+			}
+			assert.Equal(t, total, count)
+		})
+	}
+}
+
 func TestMergeSplitLogsInvalidInput(t *testing.T) {
 	r1 := &tracesRequest{td: testdata.GenerateTraces(2)}
 	r2 := &logsRequest{ld: testdata.GenerateLogs(3)}
@@ -152,7 +194,7 @@ func TestMergeSplitLogsInvalidInput(t *testing.T) {
 func TestExtractLogs(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		ld := testdata.GenerateLogs(10)
-		extractedLogs := extractLogs(ld, i)
+		extractedLogs := extractLogs(ld, &batchCapacity{items: i})
 		assert.Equal(t, i, extractedLogs.LogRecordCount())
 		assert.Equal(t, 10-i, ld.LogRecordCount())
 	}
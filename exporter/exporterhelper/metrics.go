@@ -63,6 +63,11 @@ func (req *metricsRequest) ItemsCount() int {
 	return req.md.DataPointCount()
 }
 
+// BytesSize returns the size of the request once proto-marshaled, used to size the queue in bytes.
+func (req *metricsRequest) BytesSize() int {
+	return metricsMarshaler.MetricsSize(req.md)
+}
+
 type metricsExporter struct {
 	*baseExporter
 	consumer.Metrics
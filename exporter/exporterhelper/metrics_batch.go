@@ -25,9 +25,9 @@ func mergeMetrics(_ context.Context, r1 Request, r2 Request) (Request, error) {
 // mergeSplitMetrics splits and/or merges the metrics into multiple requests based on the MaxSizeConfig.
 func mergeSplitMetrics(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Request, r2 Request) ([]Request, error) {
 	var (
-		res          []Request
-		destReq      *metricsRequest
-		capacityLeft = cfg.MaxSizeItems
+		res      []Request
+		destReq  *metricsRequest
+		capacity = newBatchCapacity(cfg)
 	)
 	for _, req := range []Request{r1, r2} {
 		if req == nil {
@@ -37,33 +37,35 @@ func mergeSplitMetrics(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1
 		if !ok {
 			return nil, errors.New("invalid input type")
 		}
-		if srcReq.md.DataPointCount() <= capacityLeft {
+		if capacity.addAll(srcReq.md.DataPointCount(), func() int { return metricsMarshaler.MetricsSize(srcReq.md) }) {
 			if destReq == nil {
 				destReq = srcReq
 			} else {
 				srcReq.md.ResourceMetrics().MoveAndAppendTo(destReq.md.ResourceMetrics())
 			}
-			capacityLeft -= destReq.md.DataPointCount()
 			continue
 		}
 
-		for {
-			extractedMetrics := extractMetrics(srcReq.md, capacityLeft)
+		for srcReq.md.DataPointCount() > 0 {
+			extractedMetrics := extractMetrics(srcReq.md, &capacity)
 			if extractedMetrics.DataPointCount() == 0 {
-				break
+				if destReq == nil {
+					// The next data point doesn't fit even in an empty batch, it's sent alone.
+					single := batchCapacity{items: 1}
+					res = append(res, &metricsRequest{md: extractMetrics(srcReq.md, &single), pusher: srcReq.pusher})
+					continue
+				}
+				// Create new batch once capacity is reached.
+				res = append(res, destReq)
+				destReq = nil
+				capacity = newBatchCapacity(cfg)
+				continue
 			}
-			capacityLeft -= extractedMetrics.DataPointCount()
 			if destReq == nil {
 				destReq = &metricsRequest{md: extractedMetrics, pusher: srcReq.pusher}
 			} else {
 				extractedMetrics.ResourceMetrics().MoveAndAppendTo(destReq.md.ResourceMetrics())
 			}
-			// Create new batch once capacity is reached.
-			if capacityLeft == 0 {
-				res = append(res, destReq)
-				destReq = nil
-				capacityLeft = cfg.MaxSizeItems
-			}
 		}
 	}
 
@@ -74,133 +76,177 @@ func mergeSplitMetrics(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1
 	return res, nil
 }
 
-// extractMetrics extracts metrics from srcMetrics until count of data points is reached.
-func extractMetrics(srcMetrics pmetric.Metrics, count int) pmetric.Metrics {
+// extractMetrics extracts metrics from srcMetrics with the data points fitting in the capacity,
+// and takes their room from it.
+func extractMetrics(srcMetrics pmetric.Metrics, capacity *batchCapacity) pmetric.Metrics {
 	destMetrics := pmetric.NewMetrics()
+	full := false
 	srcMetrics.ResourceMetrics().RemoveIf(func(srcRM pmetric.ResourceMetrics) bool {
-		if count == 0 {
+		if full {
 			return false
 		}
-		needToExtract := resourceDataPointsCount(srcRM) > count
-		if needToExtract {
-			srcRM = extractResourceMetrics(srcRM, count)
+		if capacity.add(resourceDataPointsCount(srcRM), func() int { return metricsMarshaler.ResourceMetricsSize(srcRM) }) {
+			srcRM.MoveTo(destMetrics.ResourceMetrics().AppendEmpty())
+			return true
+		}
+		full = true
+		if destRM := extractResourceMetrics(srcRM, capacity); resourceDataPointsCount(destRM) > 0 {
+			destRM.MoveTo(destMetrics.ResourceMetrics().AppendEmpty())
 		}
-		count -= resourceDataPointsCount(srcRM)
-		srcRM.MoveTo(destMetrics.ResourceMetrics().AppendEmpty())
-		return !needToExtract
+		return false
 	})
 	return destMetrics
 }
 
-// extractResourceMetrics extracts resource metrics and returns a new resource metrics with the specified number of data points.
-func extractResourceMetrics(srcRM pmetric.ResourceMetrics, count int) pmetric.ResourceMetrics {
+// extractResourceMetrics extracts resource metrics and returns a new resource metrics with the data points fitting
+// in the capacity.
+func extractResourceMetrics(srcRM pmetric.ResourceMetrics, capacity *batchCapacity) pmetric.ResourceMetrics {
 	destRM := pmetric.NewResourceMetrics()
 	destRM.SetSchemaUrl(srcRM.SchemaUrl())
 	srcRM.Resource().CopyTo(destRM.Resource())
+	nested := capacity.nested(func() int { return metricsMarshaler.ResourceMetricsSize(destRM) })
+	full := false
 	srcRM.ScopeMetrics().RemoveIf(func(srcSM pmetric.ScopeMetrics) bool {
-		if count == 0 {
+		if full {
 			return false
 		}
-		needToExtract := scopeDataPointsCount(srcSM) > count
-		if needToExtract {
-			srcSM = extractScopeMetrics(srcSM, count)
+		if nested.add(scopeDataPointsCount(srcSM), func() int { return metricsMarshaler.ScopeMetricsSize(srcSM) }) {
+			srcSM.MoveTo(destRM.ScopeMetrics().AppendEmpty())
+			return true
+		}
+		full = true
+		if destSM := extractScopeMetrics(srcSM, &nested); scopeDataPointsCount(destSM) > 0 {
+			destSM.MoveTo(destRM.ScopeMetrics().AppendEmpty())
 		}
-		count -= scopeDataPointsCount(srcSM)
-		srcSM.MoveTo(destRM.ScopeMetrics().AppendEmpty())
-		return !needToExtract
+		return false
 	})
+	capacity.takeNested(nested, func() int { return metricsMarshaler.ResourceMetricsSize(destRM) })
 	return destRM
 }
 
-// extractScopeMetrics extracts scope metrics and returns a new scope metrics with the specified number of data points.
-func extractScopeMetrics(srcSM pmetric.ScopeMetrics, count int) pmetric.ScopeMetrics {
+// extractScopeMetrics extracts scope metrics and returns a new scope metrics with the data points fitting
+// in the capacity.
+func extractScopeMetrics(srcSM pmetric.ScopeMetrics, capacity *batchCapacity) pmetric.ScopeMetrics {
 	destSM := pmetric.NewScopeMetrics()
 	destSM.SetSchemaUrl(srcSM.SchemaUrl())
 	srcSM.Scope().CopyTo(destSM.Scope())
+	nested := capacity.nested(func() int { return metricsMarshaler.ScopeMetricsSize(destSM) })
+	full := false
 	srcSM.Metrics().RemoveIf(func(srcMetric pmetric.Metric) bool {
-		if count == 0 {
+		if full {
 			return false
 		}
-		needToExtract := metricDataPointCount(srcMetric) > count
-		if needToExtract {
-			srcMetric = extractMetricDataPoints(srcMetric, count)
+		if nested.add(metricDataPointCount(srcMetric), func() int { return metricsMarshaler.MetricSize(srcMetric) }) {
+			srcMetric.MoveTo(destSM.Metrics().AppendEmpty())
+			return true
 		}
-		count -= metricDataPointCount(srcMetric)
-		srcMetric.MoveTo(destSM.Metrics().AppendEmpty())
-		return !needToExtract
+		full = true
+		if destMetric := extractMetricDataPoints(srcMetric, &nested); metricDataPointCount(destMetric) > 0 {
+			destMetric.MoveTo(destSM.Metrics().AppendEmpty())
+		}
+		return false
 	})
+	capacity.takeNested(nested, func() int { return metricsMarshaler.ScopeMetricsSize(destSM) })
 	return destSM
 }
 
-func extractMetricDataPoints(srcMetric pmetric.Metric, count int) pmetric.Metric {
+// extractMetricDataPoints extracts data points and returns a new metric with the data points fitting in the capacity.
+func extractMetricDataPoints(srcMetric pmetric.Metric, capacity *batchCapacity) pmetric.Metric {
 	destMetric := pmetric.NewMetric()
+	destMetric.SetName(srcMetric.Name())
+	destMetric.SetDescription(srcMetric.Description())
+	destMetric.SetUnit(srcMetric.Unit())
+	srcMetric.Metadata().CopyTo(destMetric.Metadata())
+	headerSize := 0
+	if capacity.limitBytes {
+		headerSize = metricsMarshaler.MetricSize(destMetric)
+	}
+
 	switch srcMetric.Type() {
 	case pmetric.MetricTypeGauge:
-		extractGaugeDataPoints(srcMetric.Gauge(), count, destMetric.SetEmptyGauge())
+		destMetric.SetEmptyGauge()
 	case pmetric.MetricTypeSum:
-		extractSumDataPoints(srcMetric.Sum(), count, destMetric.SetEmptySum())
+		destMetric.SetEmptySum().SetAggregationTemporality(srcMetric.Sum().AggregationTemporality())
+		destMetric.Sum().SetIsMonotonic(srcMetric.Sum().IsMonotonic())
 	case pmetric.MetricTypeHistogram:
-		extractHistogramDataPoints(srcMetric.Histogram(), count, destMetric.SetEmptyHistogram())
+		destMetric.SetEmptyHistogram().SetAggregationTemporality(srcMetric.Histogram().AggregationTemporality())
 	case pmetric.MetricTypeExponentialHistogram:
-		extractExponentialHistogramDataPoints(srcMetric.ExponentialHistogram(), count,
-			destMetric.SetEmptyExponentialHistogram())
+		destMetric.SetEmptyExponentialHistogram().SetAggregationTemporality(
+			srcMetric.ExponentialHistogram().AggregationTemporality())
 	case pmetric.MetricTypeSummary:
-		extractSummaryDataPoints(srcMetric.Summary(), count, destMetric.SetEmptySummary())
+		destMetric.SetEmptySummary()
 	}
-	return destMetric
-}
 
-func extractGaugeDataPoints(srcGauge pmetric.Gauge, count int, destGauge pmetric.Gauge) {
-	srcGauge.DataPoints().RemoveIf(func(srcDP pmetric.NumberDataPoint) bool {
-		if count == 0 {
-			return false
-		}
-		srcDP.MoveTo(destGauge.DataPoints().AppendEmpty())
-		count--
-		return true
-	})
+	// The data points are nested in the data of the metric, itself nested in the metric. The fields of the data,
+	// if any, take a few bytes, so its length is encoded on a single byte.
+	nested := capacity.nested(func() int { return headerSize })
+	nested = nested.nested(func() int { return metricsMarshaler.MetricSize(destMetric) - headerSize - 2 })
+
+	switch srcMetric.Type() {
+	case pmetric.MetricTypeGauge:
+		extractNumberDataPoints(srcMetric.Gauge().DataPoints(), destMetric.Gauge().DataPoints(), &nested)
+	case pmetric.MetricTypeSum:
+		extractNumberDataPoints(srcMetric.Sum().DataPoints(), destMetric.Sum().DataPoints(), &nested)
+	case pmetric.MetricTypeHistogram:
+		extractHistogramDataPoints(srcMetric.Histogram().DataPoints(), destMetric.Histogram().DataPoints(), &nested)
+	case pmetric.MetricTypeExponentialHistogram:
+		extractExponentialHistogramDataPoints(srcMetric.ExponentialHistogram().DataPoints(),
+			destMetric.ExponentialHistogram().DataPoints(), &nested)
+	case pmetric.MetricTypeSummary:
+		extractSummaryDataPoints(srcMetric.Summary().DataPoints(), destMetric.Summary().DataPoints(), &nested)
+	}
+	capacity.takeNested(nested, func() int { return metricsMarshaler.MetricSize(destMetric) })
+	return destMetric
 }
 
-func extractSumDataPoints(srcSum pmetric.Sum, count int, destSum pmetric.Sum) {
-	srcSum.DataPoints().RemoveIf(func(srcDP pmetric.NumberDataPoint) bool {
-		if count == 0 {
+func extractNumberDataPoints(srcDPs pmetric.NumberDataPointSlice, destDPs pmetric.NumberDataPointSlice,
+	capacity *batchCapacity) {
+	full := false
+	srcDPs.RemoveIf(func(srcDP pmetric.NumberDataPoint) bool {
+		if full || !capacity.add(1, func() int { return metricsMarshaler.NumberDataPointSize(srcDP) }) {
+			full = true
 			return false
 		}
-		srcDP.MoveTo(destSum.DataPoints().AppendEmpty())
-		count--
+		srcDP.MoveTo(destDPs.AppendEmpty())
 		return true
 	})
 }
 
-func extractHistogramDataPoints(srcHistogram pmetric.Histogram, count int, destHistogram pmetric.Histogram) {
-	srcHistogram.DataPoints().RemoveIf(func(srcDP pmetric.HistogramDataPoint) bool {
-		if count == 0 {
+func extractHistogramDataPoints(srcDPs pmetric.HistogramDataPointSlice, destDPs pmetric.HistogramDataPointSlice,
+	capacity *batchCapacity) {
+	full := false
+	srcDPs.RemoveIf(func(srcDP pmetric.HistogramDataPoint) bool {
+		if full || !capacity.add(1, func() int { return metricsMarshaler.HistogramDataPointSize(srcDP) }) {
+			full = true
 			return false
 		}
-		srcDP.MoveTo(destHistogram.DataPoints().AppendEmpty())
-		count--
+		srcDP.MoveTo(destDPs.AppendEmpty())
 		return true
 	})
 }
 
-func extractExponentialHistogramDataPoints(srcExponentialHistogram pmetric.ExponentialHistogram, count int, destExponentialHistogram pmetric.ExponentialHistogram) {
-	srcExponentialHistogram.DataPoints().RemoveIf(func(srcDP pmetric.ExponentialHistogramDataPoint) bool {
-		if count == 0 {
+func extractExponentialHistogramDataPoints(srcDPs pmetric.ExponentialHistogramDataPointSlice,
+	destDPs pmetric.ExponentialHistogramDataPointSlice, capacity *batchCapacity) {
+	full := false
+	srcDPs.RemoveIf(func(srcDP pmetric.ExponentialHistogramDataPoint) bool {
+		if full || !capacity.add(1, func() int { return metricsMarshaler.ExponentialHistogramDataPointSize(srcDP) }) {
+			full = true
 			return false
 		}
-		srcDP.MoveTo(destExponentialHistogram.DataPoints().AppendEmpty())
-		count--
+		srcDP.MoveTo(destDPs.AppendEmpty())
 		return true
 	})
 }
 
-func extractSummaryDataPoints(srcSummary pmetric.Summary, count int, destSummary pmetric.Summary) {
-	srcSummary.DataPoints().RemoveIf(func(srcDP pmetric.SummaryDataPoint) bool {
-		if count == 0 {
+func extractSummaryDataPoints(srcDPs pmetric.SummaryDataPointSlice, destDPs pmetric.SummaryDataPointSlice,
+	capacity *batchCapacity) {
+	full := false
+	srcDPs.RemoveIf(func(srcDP pmetric.SummaryDataPoint) bool {
+		if full || !capacity.add(1, func() int { return metricsMarshaler.SummaryDataPointSize(srcDP) }) {
+			full = true
 			return false
 		}
-		srcDP.MoveTo(destSummary.DataPoints().AppendEmpty())
-		count--
+		srcDP.MoveTo(destDPs.AppendEmpty())
 		return true
 	})
 }
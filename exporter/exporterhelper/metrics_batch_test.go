@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -142,6 +143,47 @@ func TestMergeSplitMetrics(t *testing.T) {
 	}
 }
 
+func TestMergeSplitMetricsMaxSizeBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  exporterbatcher.MaxSizeConfig
+	}{
+		{
+			name: "bytes_only",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 1000},
+		},
+		{
+			name: "bytes_and_items",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 3000, MaxSizeItems: 5},
+		},
+		{
+			name: "data_point_bigger_than_limit",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r1 := &metricsRequest{md: testdata.GenerateMetrics(3)}
+			r2 := &metricsRequest{md: testdata.GenerateMetrics(50)}
+			total := r1.md.DataPointCount() + r2.md.DataPointCount()
+			res, err := mergeSplitMetrics(context.Background(), tt.cfg, r1, r2)
+			require.NoError(t, err)
+			count := 0
+			for _, r := range res {
+				md := r.(*metricsRequest).md
+				count += md.DataPointCount()
+				if md.DataPointCount() > 1 {
+					assert.LessOrEqual(t, metricsMarshaler.MetricsSize(md), tt.cfg.MaxSizeBytes)
+				}
+				if tt.cfg.MaxSizeItems > 0 {
+					assert.LessOrEqual(t, md.DataPointCount(), tt.cfg.MaxSizeItems)
+				}
+			}
+			assert.Equal(t, total, count)
+		})
+	}
+}
+
 func TestMergeSplitMetricsInvalidInput(t *testing.T) {
 	r1 := &tracesRequest{td: testdata.GenerateTraces(2)}
 	r2 := &metricsRequest{md: testdata.GenerateMetrics(3)}
@@ -152,15 +194,31 @@ func TestMergeSplitMetricsInvalidInput(t *testing.T) {
 func TestExtractMetrics(t *testing.T) {
 	for i := 0; i < 20; i++ {
 		md := testdata.GenerateMetrics(10)
-		extractedMetrics := extractMetrics(md, i)
+		extractedMetrics := extractMetrics(md, &batchCapacity{items: i})
 		assert.Equal(t, i, extractedMetrics.DataPointCount())
 		assert.Equal(t, 20-i, md.DataPointCount())
 	}
 }
 
+func TestExtractMetricsSplitMetric(t *testing.T) {
+	md := testdata.GenerateMetrics(4)
+	srcMetric := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(2)
+	require.Equal(t, pmetric.MetricTypeSum, srcMetric.Type())
+
+	// The data points of a metric split in two keep the fields of the metric.
+	extractedMetrics := extractMetrics(md, &batchCapacity{items: 5})
+	destMetric := extractedMetrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(2)
+	assert.Equal(t, 1, destMetric.Sum().DataPoints().Len())
+	assert.Equal(t, srcMetric.Name(), destMetric.Name())
+	assert.Equal(t, srcMetric.Description(), destMetric.Description())
+	assert.Equal(t, srcMetric.Unit(), destMetric.Unit())
+	assert.Equal(t, srcMetric.Sum().AggregationTemporality(), destMetric.Sum().AggregationTemporality())
+	assert.Equal(t, srcMetric.Sum().IsMonotonic(), destMetric.Sum().IsMonotonic())
+}
+
 func TestExtractMetricsInvalidMetric(t *testing.T) {
 	md := testdata.GenerateMetricsMetricTypeInvalid()
-	extractedMetrics := extractMetrics(md, 10)
+	extractedMetrics := extractMetrics(md, &batchCapacity{items: 10})
 	assert.Equal(t, testdata.GenerateMetricsMetricTypeInvalid(), extractedMetrics)
 	assert.Equal(t, 0, md.ResourceMetrics().Len())
 }
@@ -104,6 +104,20 @@ func (or *obsReport) endLogsOp(ctx context.Context, numLogRecords int, err error
 	endSpan(ctx, err, numSent, numFailedToSend, obsmetrics.SentLogRecordsKey, obsmetrics.FailedToSendLogRecordsKey)
 }
 
+// startProfilesOp is called at the start of an Export operation.
+// The returned context should be used in other calls to the Exporter functions
+// dealing with the same export operation.
+func (or *obsReport) startProfilesOp(ctx context.Context) context.Context {
+	return or.startOp(ctx, obsmetrics.ExportProfilesOperationSuffix)
+}
+
+// endProfilesOp completes the export operation that was started with startProfilesOp.
+// There are no metrics for the profiles yet, the sent samples are only recorded on the span.
+func (or *obsReport) endProfilesOp(ctx context.Context, numSamples int, err error) {
+	numSent, numFailedToSend := toNumItems(numSamples, err)
+	endSpan(ctx, err, numSent, numFailedToSend, obsmetrics.SentSamplesKey, obsmetrics.FailedToSendSamplesKey)
+}
+
 // startOp creates the span used to trace the operation. Returning
 // the updated context and the created span.
 func (or *obsReport) startOp(ctx context.Context, operationSuffix string) context.Context {
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentprofiles"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterprofiles"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+)
+
+var profilesMarshaler = &pprofile.ProtoMarshaler{}
+var profilesUnmarshaler = &pprofile.ProtoUnmarshaler{}
+
+type profilesRequest struct {
+	pd     pprofile.Profiles
+	pusher consumerprofiles.ConsumeProfilesFunc
+}
+
+func newProfilesRequest(pd pprofile.Profiles, pusher consumerprofiles.ConsumeProfilesFunc) Request {
+	return &profilesRequest{
+		pd:     pd,
+		pusher: pusher,
+	}
+}
+
+func newProfilesRequestUnmarshalerFunc(pusher consumerprofiles.ConsumeProfilesFunc) exporterqueue.Unmarshaler[Request] {
+	return func(bytes []byte) (Request, error) {
+		profiles, err := profilesUnmarshaler.UnmarshalProfiles(bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newProfilesRequest(profiles, pusher), nil
+	}
+}
+
+func profilesRequestMarshaler(req Request) ([]byte, error) {
+	return profilesMarshaler.MarshalProfiles(req.(*profilesRequest).pd)
+}
+
+func (req *profilesRequest) Export(ctx context.Context) error {
+	return req.pusher(ctx, req.pd)
+}
+
+// ItemsCount returns the number of samples of the profiles, the unit the profiles are counted and batched in.
+func (req *profilesRequest) ItemsCount() int {
+	return req.pd.SampleCount()
+}
+
+// BytesSize returns the size of the request once proto-marshaled, used to size the queue in bytes.
+func (req *profilesRequest) BytesSize() int {
+	return profilesMarshaler.ProfilesSize(req.pd)
+}
+
+type profilesExporter struct {
+	*baseExporter
+	consumerprofiles.Profiles
+}
+
+// NewProfilesExporter creates an exporterprofiles.Profiles that wraps every request with a Span.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// while the profiles signal is in development.
+func NewProfilesExporter(
+	ctx context.Context,
+	set exporter.Settings,
+	cfg component.Config,
+	pusher consumerprofiles.ConsumeProfilesFunc,
+	options ...Option,
+) (exporterprofiles.Profiles, error) {
+	if cfg == nil {
+		return nil, errNilConfig
+	}
+	if pusher == nil {
+		return nil, errNilPushProfilesData
+	}
+	profilesOpts := []Option{
+		withMarshaler(profilesRequestMarshaler), withUnmarshaler(newProfilesRequestUnmarshalerFunc(pusher)),
+		withBatchFuncs(mergeProfiles, mergeSplitProfiles),
+	}
+	return NewProfilesRequestExporter(ctx, set, requestFromProfiles(pusher), append(profilesOpts, options...)...)
+}
+
+// RequestFromProfilesFunc converts pprofile.Profiles data into a user-defined request.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// while the profiles signal is in development.
+type RequestFromProfilesFunc func(context.Context, pprofile.Profiles) (Request, error)
+
+// requestFromProfiles returns a RequestFromProfilesFunc that converts pprofile.Profiles into a Request.
+func requestFromProfiles(pusher consumerprofiles.ConsumeProfilesFunc) RequestFromProfilesFunc {
+	return func(_ context.Context, pd pprofile.Profiles) (Request, error) {
+		return newProfilesRequest(pd, pusher), nil
+	}
+}
+
+// NewProfilesRequestExporter creates new profiles exporter based on custom ProfilesConverter and RequestSender.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// while the profiles signal is in development.
+func NewProfilesRequestExporter(
+	_ context.Context,
+	set exporter.Settings,
+	converter RequestFromProfilesFunc,
+	options ...Option,
+) (exporterprofiles.Profiles, error) {
+	if set.Logger == nil {
+		return nil, errNilLogger
+	}
+
+	if converter == nil {
+		return nil, errNilProfilesConverter
+	}
+
+	be, err := newBaseExporter(set, componentprofiles.DataTypeProfiles, newProfilesExporterWithObservability, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	pc, err := consumerprofiles.NewProfiles(func(ctx context.Context, pd pprofile.Profiles) error {
+		req, cErr := converter(ctx, pd)
+		if cErr != nil {
+			set.Logger.Error("Failed to convert profiles. Dropping data.",
+				zap.Int("dropped_samples", pd.SampleCount()),
+				zap.Error(cErr))
+			return consumererror.NewPermanent(cErr)
+		}
+		// There is no metric for the profiles failing to be enqueued yet.
+		return be.send(ctx, req)
+	}, be.consumerOptions...)
+
+	return &profilesExporter{
+		baseExporter: be,
+		Profiles:     pc,
+	}, err
+}
+
+type profilesExporterWithObservability struct {
+	baseRequestSender
+	obsrep *obsReport
+}
+
+func newProfilesExporterWithObservability(obsrep *obsReport) requestSender {
+	return &profilesExporterWithObservability{obsrep: obsrep}
+}
+
+func (pewo *profilesExporterWithObservability) send(ctx context.Context, req Request) error {
+	c := pewo.obsrep.startProfilesOp(ctx)
+	numSamples := req.ItemsCount()
+	err := pewo.nextSender.send(c, req)
+	pewo.obsrep.endProfilesOp(c, numSamples, err)
+	return err
+}
@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+)
+
+// mergeProfiles merges two profiles requests into one.
+func mergeProfiles(_ context.Context, r1 Request, r2 Request) (Request, error) {
+	pr1, ok1 := r1.(*profilesRequest)
+	pr2, ok2 := r2.(*profilesRequest)
+	if !ok1 || !ok2 {
+		return nil, errors.New("invalid input type")
+	}
+	pr2.pd.ResourceProfiles().MoveAndAppendTo(pr1.pd.ResourceProfiles())
+	return pr1, nil
+}
+
+// mergeSplitProfiles splits and/or merges the profiles into multiple requests based on the MaxSizeConfig.
+// The profiles are counted in samples, but are never split: the samples of a profile refer to its tables
+// of locations, functions and strings, so a profile is always sent as a whole.
+func mergeSplitProfiles(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Request, r2 Request) ([]Request, error) {
+	var (
+		res      []Request
+		destReq  *profilesRequest
+		capacity = newBatchCapacity(cfg)
+	)
+	for _, req := range []Request{r1, r2} {
+		if req == nil {
+			continue
+		}
+		srcReq, ok := req.(*profilesRequest)
+		if !ok {
+			return nil, errors.New("invalid input type")
+		}
+		if capacity.addAll(srcReq.pd.SampleCount(), func() int { return profilesMarshaler.ProfilesSize(srcReq.pd) }) {
+			if destReq == nil {
+				destReq = srcReq
+			} else {
+				srcReq.pd.ResourceProfiles().MoveAndAppendTo(destReq.pd.ResourceProfiles())
+			}
+			continue
+		}
+
+		// The profiles without samples are moved too, they may only carry their original payload.
+		for srcReq.pd.ResourceProfiles().Len() > 0 {
+			extractedProfiles := extractProfiles(srcReq.pd, &capacity)
+			if extractedProfiles.ResourceProfiles().Len() == 0 {
+				if destReq == nil {
+					// The next profile doesn't fit even in an empty batch, it's sent alone.
+					single := batchCapacity{items: firstProfileSampleCount(srcReq.pd)}
+					res = append(res, &profilesRequest{pd: extractProfiles(srcReq.pd, &single), pusher: srcReq.pusher})
+					continue
+				}
+				// Create new batch once capacity is reached.
+				res = append(res, destReq)
+				destReq = nil
+				capacity = newBatchCapacity(cfg)
+				continue
+			}
+			if destReq == nil {
+				destReq = &profilesRequest{pd: extractedProfiles, pusher: srcReq.pusher}
+			} else {
+				extractedProfiles.ResourceProfiles().MoveAndAppendTo(destReq.pd.ResourceProfiles())
+			}
+		}
+	}
+
+	if destReq != nil {
+		res = append(res, destReq)
+	}
+	return res, nil
+}
+
+// extractProfiles extracts profiles from the input profiles and returns a new profiles with the profiles fitting
+// in the capacity, and takes their room from it.
+func extractProfiles(srcProfiles pprofile.Profiles, capacity *batchCapacity) pprofile.Profiles {
+	destProfiles := pprofile.NewProfiles()
+	full := false
+	srcProfiles.ResourceProfiles().RemoveIf(func(srcRP pprofile.ResourceProfiles) bool {
+		if full {
+			return false
+		}
+		if capacity.add(resourceProfilesSampleCount(srcRP), func() int { return profilesMarshaler.ResourceProfilesSize(srcRP) }) {
+			srcRP.MoveTo(destProfiles.ResourceProfiles().AppendEmpty())
+			return true
+		}
+		full = true
+		if destRP := extractResourceProfiles(srcRP, capacity); destRP.ScopeProfiles().Len() > 0 {
+			destRP.MoveTo(destProfiles.ResourceProfiles().AppendEmpty())
+		}
+		return false
+	})
+	return destProfiles
+}
+
+// extractResourceProfiles extracts resource profiles and returns a new resource profiles with the profiles fitting
+// in the capacity.
+func extractResourceProfiles(srcRP pprofile.ResourceProfiles, capacity *batchCapacity) pprofile.ResourceProfiles {
+	destRP := pprofile.NewResourceProfiles()
+	destRP.SetSchemaUrl(srcRP.SchemaUrl())
+	srcRP.Resource().CopyTo(destRP.Resource())
+	nested := capacity.nested(func() int { return profilesMarshaler.ResourceProfilesSize(destRP) })
+	full := false
+	srcRP.ScopeProfiles().RemoveIf(func(srcSP pprofile.ScopeProfiles) bool {
+		if full {
+			return false
+		}
+		if nested.add(scopeProfilesSampleCount(srcSP), func() int { return profilesMarshaler.ScopeProfilesSize(srcSP) }) {
+			srcSP.MoveTo(destRP.ScopeProfiles().AppendEmpty())
+			return true
+		}
+		full = true
+		if destSP := extractScopeProfiles(srcSP, &nested); destSP.Profiles().Len() > 0 {
+			destSP.MoveTo(destRP.ScopeProfiles().AppendEmpty())
+		}
+		return false
+	})
+	capacity.takeNested(nested, func() int { return profilesMarshaler.ResourceProfilesSize(destRP) })
+	return destRP
+}
+
+// extractScopeProfiles extracts scope profiles and returns a new scope profiles with the profiles fitting
+// in the capacity.
+func extractScopeProfiles(srcSP pprofile.ScopeProfiles, capacity *batchCapacity) pprofile.ScopeProfiles {
+	destSP := pprofile.NewScopeProfiles()
+	destSP.SetSchemaUrl(srcSP.SchemaUrl())
+	srcSP.Scope().CopyTo(destSP.Scope())
+	nested := capacity.nested(func() int { return profilesMarshaler.ScopeProfilesSize(destSP) })
+	full := false
+	srcSP.Profiles().RemoveIf(func(srcPC pprofile.ProfileContainer) bool {
+		if full || !nested.add(srcPC.Profile().Sample().Len(), func() int { return profilesMarshaler.ProfileContainerSize(srcPC) }) {
+			full = true
+			return false
+		}
+		srcPC.MoveTo(destSP.Profiles().AppendEmpty())
+		return true
+	})
+	capacity.takeNested(nested, func() int { return profilesMarshaler.ScopeProfilesSize(destSP) })
+	return destSP
+}
+
+// resourceProfilesSampleCount calculates the total number of samples in the pprofile.ResourceProfiles.
+func resourceProfilesSampleCount(rp pprofile.ResourceProfiles) int {
+	count := 0
+	for k := 0; k < rp.ScopeProfiles().Len(); k++ {
+		count += scopeProfilesSampleCount(rp.ScopeProfiles().At(k))
+	}
+	return count
+}
+
+// scopeProfilesSampleCount calculates the total number of samples in the pprofile.ScopeProfiles.
+func scopeProfilesSampleCount(sp pprofile.ScopeProfiles) int {
+	count := 0
+	for k := 0; k < sp.Profiles().Len(); k++ {
+		count += sp.Profiles().At(k).Profile().Sample().Len()
+	}
+	return count
+}
+
+// firstProfileSampleCount returns the number of samples of the first profile with samples.
+func firstProfileSampleCount(pd pprofile.Profiles) int {
+	for i := 0; i < pd.ResourceProfiles().Len(); i++ {
+		sps := pd.ResourceProfiles().At(i).ScopeProfiles()
+		for j := 0; j < sps.Len(); j++ {
+			pcs := sps.At(j).Profiles()
+			for k := 0; k < pcs.Len(); k++ {
+				if n := pcs.At(k).Profile().Sample().Len(); n > 0 {
+					return n
+				}
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/testdata"
+)
+
+func TestMergeProfiles(t *testing.T) {
+	pr1 := &profilesRequest{pd: testdata.GenerateProfiles(2)}
+	pr2 := &profilesRequest{pd: testdata.GenerateProfiles(3)}
+	res, err := mergeProfiles(context.Background(), pr1, pr2)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, res.(*profilesRequest).pd.SampleCount())
+}
+
+func TestMergeProfilesInvalidInput(t *testing.T) {
+	pr1 := &logsRequest{ld: testdata.GenerateLogs(2)}
+	pr2 := &profilesRequest{pd: testdata.GenerateProfiles(3)}
+	_, err := mergeProfiles(context.Background(), pr1, pr2)
+	assert.Error(t, err)
+}
+
+func TestMergeSplitProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      exporterbatcher.MaxSizeConfig
+		pr1      Request
+		pr2      Request
+		expected []*profilesRequest
+	}{
+		{
+			name:     "both_requests_empty",
+			cfg:      exporterbatcher.MaxSizeConfig{MaxSizeItems: 10},
+			pr1:      &profilesRequest{pd: pprofile.NewProfiles()},
+			pr2:      &profilesRequest{pd: pprofile.NewProfiles()},
+			expected: []*profilesRequest{{pd: pprofile.NewProfiles()}},
+		},
+		{
+			name:     "first_request_empty",
+			cfg:      exporterbatcher.MaxSizeConfig{MaxSizeItems: 10},
+			pr1:      &profilesRequest{pd: pprofile.NewProfiles()},
+			pr2:      &profilesRequest{pd: testdata.GenerateProfiles(5)},
+			expected: []*profilesRequest{{pd: testdata.GenerateProfiles(5)}},
+		},
+		{
+			name:     "first_requests_nil",
+			cfg:      exporterbatcher.MaxSizeConfig{MaxSizeItems: 10},
+			pr1:      nil,
+			pr2:      &profilesRequest{pd: testdata.GenerateProfiles(5)},
+			expected: []*profilesRequest{{pd: testdata.GenerateProfiles(5)}},
+		},
+		{
+			name: "merge_only",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeItems: 10},
+			pr1:  &profilesRequest{pd: testdata.GenerateProfiles(4)},
+			pr2:  &profilesRequest{pd: testdata.GenerateProfiles(6)},
+			expected: []*profilesRequest{{pd: func() pprofile.Profiles {
+				profiles := testdata.GenerateProfiles(4)
+				testdata.GenerateProfiles(6).ResourceProfiles().MoveAndAppendTo(profiles.ResourceProfiles())
+				return profiles
+			}()}},
+		},
+		{
+			name: "split_only",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeItems: 4},
+			pr1:  nil,
+			pr2:  &profilesRequest{pd: testdata.GenerateProfiles(10)},
+			expected: []*profilesRequest{
+				{pd: testdata.GenerateProfiles(4)},
+				{pd: testdata.GenerateProfiles(4)},
+				{pd: testdata.GenerateProfiles(2)},
+			},
+		},
+		{
+			name: "merge_and_split",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeItems: 10},
+			pr1:  &profilesRequest{pd: testdata.GenerateProfiles(8)},
+			pr2:  &profilesRequest{pd: testdata.GenerateProfiles(20)},
+			expected: []*profilesRequest{
+				{pd: func() pprofile.Profiles {
+					profiles := testdata.GenerateProfiles(8)
+					testdata.GenerateProfiles(2).ResourceProfiles().MoveAndAppendTo(profiles.ResourceProfiles())
+					return profiles
+				}()},
+				{pd: testdata.GenerateProfiles(10)},
+				{pd: testdata.GenerateProfiles(8)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := mergeSplitProfiles(context.Background(), tt.cfg, tt.pr1, tt.pr2)
+			assert.Nil(t, err)
+			assert.Equal(t, len(tt.expected), len(res))
+			for i, r := range res {
+				assert.Equal(t, tt.expected[i], r.(*profilesRequest))
+			}
+		})
+	}
+}
+
+func TestMergeSplitProfilesKeepsProfilesWhole(t *testing.T) {
+	pd := testdata.GenerateProfiles(1)
+	samples := pd.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0).Profile().Sample()
+	for i := 0; i < 4; i++ {
+		samples.AppendEmpty().Value().Append(int64(i))
+	}
+	pd2 := testdata.GenerateProfiles(2)
+	res, err := mergeSplitProfiles(context.Background(), exporterbatcher.MaxSizeConfig{MaxSizeItems: 2},
+		&profilesRequest{pd: pd}, &profilesRequest{pd: pd2})
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, 5, res[0].(*profilesRequest).pd.SampleCount())
+	assert.Equal(t, 2, res[1].(*profilesRequest).pd.SampleCount())
+}
+
+func TestMergeSplitProfilesWithoutSamples(t *testing.T) {
+	pd := testdata.GenerateProfiles(2)
+	// A profile only carrying its original payload has no samples.
+	pd2 := pprofile.NewProfiles()
+	pd2.ResourceProfiles().AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty().ProfileID().FromRaw([]byte{1})
+
+	// The profile without samples doesn't fit in the first batch, it's sent in its own.
+	cfg := exporterbatcher.MaxSizeConfig{MaxSizeBytes: profilesMarshaler.ProfilesSize(pd)}
+	res, err := mergeSplitProfiles(context.Background(), cfg, &profilesRequest{pd: pd}, &profilesRequest{pd: pd2})
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, testdata.GenerateProfiles(2), res[0].(*profilesRequest).pd)
+	last := res[1].(*profilesRequest).pd
+	assert.Equal(t, 0, last.SampleCount())
+	require.Equal(t, 1, last.ResourceProfiles().Len())
+	assert.Equal(t, []byte{1}, last.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0).ProfileID().AsRaw())
+}
+
+func TestMergeSplitProfilesMaxSizeBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  exporterbatcher.MaxSizeConfig
+	}{
+		{
+			name: "bytes_only",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 1000},
+		},
+		{
+			name: "bytes_and_items",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 3000, MaxSizeItems: 5},
+		},
+		{
+			name: "profile_bigger_than_limit",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 50},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r1 := &profilesRequest{pd: testdata.GenerateProfiles(3)}
+			r2 := &profilesRequest{pd: testdata.GenerateProfiles(50)}
+			total := r1.pd.SampleCount() + r2.pd.SampleCount()
+			res, err := mergeSplitProfiles(context.Background(), tt.cfg, r1, r2)
+			require.NoError(t, err)
+			count := 0
+			for _, r := range res {
+				pd := r.(*profilesRequest).pd
+				count += pd.SampleCount()
+				if pd.SampleCount() > 1 {
+					assert.LessOrEqual(t, profilesMarshaler.ProfilesSize(pd), tt.cfg.MaxSizeBytes)
+				}
+				if tt.cfg.MaxSizeItems > 0 {
+					assert.LessOrEqual(t, pd.SampleCount(), tt.cfg.MaxSizeItems)
+				}
+			}
+			assert.Equal(t, total, count)
+		})
+	}
+}
+
+func TestMergeSplitProfilesInvalidInput(t *testing.T) {
+	r1 := &tracesRequest{td: testdata.GenerateTraces(2)}
+	r2 := &profilesRequest{pd: testdata.GenerateProfiles(3)}
+	_, err := mergeSplitProfiles(context.Background(), exporterbatcher.MaxSizeConfig{}, r1, r2)
+	assert.Error(t, err)
+}
+
+func TestExtractProfiles(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		pd := testdata.GenerateProfiles(10)
+		extractedProfiles := extractProfiles(pd, &batchCapacity{items: i})
+		assert.Equal(t, i, extractedProfiles.SampleCount())
+		assert.Equal(t, 10-i, pd.SampleCount())
+	}
+}
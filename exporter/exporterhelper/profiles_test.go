@@ -0,0 +1,249 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	nooptrace "go.opentelemetry.io/otel/trace/noop"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterprofiles"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
+	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/testdata"
+)
+
+const (
+	fakeProfilesParentSpanName = "fake_profiles_parent_span_name"
+)
+
+var (
+	fakeProfilesExporterConfig = struct{}{}
+)
+
+func TestProfilesRequest(t *testing.T) {
+	pr := newProfilesRequest(testdata.GenerateProfiles(2), nil)
+	assert.Equal(t, 2, pr.ItemsCount())
+
+	// The profiles requests are retried as a whole.
+	_, ok := pr.(RequestErrorHandler)
+	assert.False(t, ok)
+}
+
+func TestProfilesExporter_InvalidName(t *testing.T) {
+	pe, err := NewProfilesExporter(context.Background(), exportertest.NewNopSettings(), nil, newPushProfilesData(nil))
+	require.Nil(t, pe)
+	require.Equal(t, errNilConfig, err)
+}
+
+func TestProfilesExporter_NilLogger(t *testing.T) {
+	pe, err := NewProfilesExporter(context.Background(), exporter.Settings{}, &fakeProfilesExporterConfig, newPushProfilesData(nil))
+	require.Nil(t, pe)
+	require.Equal(t, errNilLogger, err)
+}
+
+func TestProfilesRequestExporter_NilLogger(t *testing.T) {
+	pe, err := NewProfilesRequestExporter(context.Background(), exporter.Settings{}, (&fakeRequestConverter{}).requestFromProfilesFunc)
+	require.Nil(t, pe)
+	require.Equal(t, errNilLogger, err)
+}
+
+func TestProfilesExporter_NilPushProfilesData(t *testing.T) {
+	pe, err := NewProfilesExporter(context.Background(), exportertest.NewNopSettings(), &fakeProfilesExporterConfig, nil)
+	require.Nil(t, pe)
+	require.Equal(t, errNilPushProfilesData, err)
+}
+
+func TestProfilesRequestExporter_NilProfilesConverter(t *testing.T) {
+	pe, err := NewProfilesRequestExporter(context.Background(), exportertest.NewNopSettings(), nil)
+	require.Nil(t, pe)
+	require.Equal(t, errNilProfilesConverter, err)
+}
+
+func TestProfilesExporter_Default(t *testing.T) {
+	pd := pprofile.NewProfiles()
+	pe, err := NewProfilesExporter(context.Background(), exportertest.NewNopSettings(), &fakeProfilesExporterConfig, newPushProfilesData(nil))
+	assert.NotNil(t, pe)
+	assert.NoError(t, err)
+
+	assert.Equal(t, consumer.Capabilities{MutatesData: false}, pe.Capabilities())
+	assert.NoError(t, pe.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, pe.ConsumeProfiles(context.Background(), pd))
+	assert.NoError(t, pe.Shutdown(context.Background()))
+}
+
+func TestProfilesRequestExporter_Default(t *testing.T) {
+	pd := pprofile.NewProfiles()
+	pe, err := NewProfilesRequestExporter(context.Background(), exportertest.NewNopSettings(),
+		(&fakeRequestConverter{}).requestFromProfilesFunc)
+	assert.NotNil(t, pe)
+	assert.NoError(t, err)
+
+	assert.Equal(t, consumer.Capabilities{MutatesData: false}, pe.Capabilities())
+	assert.NoError(t, pe.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, pe.ConsumeProfiles(context.Background(), pd))
+	assert.NoError(t, pe.Shutdown(context.Background()))
+}
+
+func TestProfilesExporter_WithCapabilities(t *testing.T) {
+	capabilities := consumer.Capabilities{MutatesData: true}
+	pe, err := NewProfilesExporter(context.Background(), exportertest.NewNopSettings(), &fakeProfilesExporterConfig, newPushProfilesData(nil), WithCapabilities(capabilities))
+	require.NoError(t, err)
+	require.NotNil(t, pe)
+
+	assert.Equal(t, capabilities, pe.Capabilities())
+}
+
+func TestProfilesExporter_Default_ReturnError(t *testing.T) {
+	pd := pprofile.NewProfiles()
+	want := errors.New("my_error")
+	pe, err := NewProfilesExporter(context.Background(), exportertest.NewNopSettings(), &fakeProfilesExporterConfig, newPushProfilesData(want))
+	require.NoError(t, err)
+	require.NotNil(t, pe)
+	require.Equal(t, want, pe.ConsumeProfiles(context.Background(), pd))
+}
+
+func TestProfilesRequestExporter_Default_ConvertError(t *testing.T) {
+	pd := pprofile.NewProfiles()
+	want := errors.New("convert_error")
+	pe, err := NewProfilesRequestExporter(context.Background(), exportertest.NewNopSettings(),
+		(&fakeRequestConverter{profilesError: want}).requestFromProfilesFunc)
+	require.NoError(t, err)
+	require.NotNil(t, pe)
+	require.Equal(t, consumererror.NewPermanent(want), pe.ConsumeProfiles(context.Background(), pd))
+}
+
+func TestProfilesRequestExporter_Default_ExportError(t *testing.T) {
+	pd := pprofile.NewProfiles()
+	want := errors.New("export_error")
+	pe, err := NewProfilesRequestExporter(context.Background(), exportertest.NewNopSettings(),
+		(&fakeRequestConverter{requestError: want}).requestFromProfilesFunc)
+	require.NoError(t, err)
+	require.NotNil(t, pe)
+	require.Equal(t, want, pe.ConsumeProfiles(context.Background(), pd))
+}
+
+func TestProfilesExporter_WithPersistentQueue(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID
+	rCfg := configretry.NewDefaultBackOffConfig()
+	ps := consumertest.ProfilesSink{}
+	set := exportertest.NewNopSettings()
+	set.ID = component.MustNewIDWithName("test_profiles", "with_persistent_queue")
+	pe, err := NewProfilesExporter(context.Background(), set, &fakeProfilesExporterConfig, ps.ConsumeProfiles, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}}
+	require.NoError(t, pe.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, pe.Shutdown(context.Background())) })
+
+	profiles := testdata.GenerateProfiles(2)
+	require.NoError(t, pe.ConsumeProfiles(context.Background(), profiles))
+	require.Eventually(t, func() bool {
+		return len(ps.AllProfiles()) == 1 && ps.AllProfiles()[0].SampleCount() == 2
+	}, 500*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestProfilesExporter_WithSpan(t *testing.T) {
+	set := exportertest.NewNopSettings()
+	sr := new(tracetest.SpanRecorder)
+	set.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	otel.SetTracerProvider(set.TracerProvider)
+	defer otel.SetTracerProvider(nooptrace.NewTracerProvider())
+
+	pe, err := NewProfilesExporter(context.Background(), set, &fakeProfilesExporterConfig, newPushProfilesData(nil))
+	require.Nil(t, err)
+	require.NotNil(t, pe)
+	checkWrapSpanForProfilesExporter(t, sr, set.TracerProvider.Tracer("test"), pe, nil, 1)
+}
+
+func TestProfilesRequestExporter_WithSpan_ReturnError(t *testing.T) {
+	set := exportertest.NewNopSettings()
+	sr := new(tracetest.SpanRecorder)
+	set.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	otel.SetTracerProvider(set.TracerProvider)
+	defer otel.SetTracerProvider(nooptrace.NewTracerProvider())
+
+	want := errors.New("my_error")
+	pe, err := NewProfilesRequestExporter(context.Background(), set, (&fakeRequestConverter{requestError: want}).requestFromProfilesFunc)
+	require.Nil(t, err)
+	require.NotNil(t, pe)
+	checkWrapSpanForProfilesExporter(t, sr, set.TracerProvider.Tracer("test"), pe, want, 1)
+}
+
+func TestProfilesExporter_WithShutdown(t *testing.T) {
+	shutdownCalled := false
+	shutdown := func(context.Context) error { shutdownCalled = true; return nil }
+
+	pe, err := NewProfilesExporter(context.Background(), exportertest.NewNopSettings(), &fakeProfilesExporterConfig, newPushProfilesData(nil), WithShutdown(shutdown))
+	assert.NotNil(t, pe)
+	assert.NoError(t, err)
+
+	assert.Nil(t, pe.Shutdown(context.Background()))
+	assert.True(t, shutdownCalled)
+}
+
+func newPushProfilesData(retError error) consumerprofiles.ConsumeProfilesFunc {
+	return func(_ context.Context, _ pprofile.Profiles) error {
+		return retError
+	}
+}
+
+func generateProfilesTraffic(t *testing.T, tracer trace.Tracer, pe exporterprofiles.Profiles, numRequests int, wantError error) {
+	pd := testdata.GenerateProfiles(1)
+	ctx, span := tracer.Start(context.Background(), fakeProfilesParentSpanName)
+	defer span.End()
+	for i := 0; i < numRequests; i++ {
+		require.Equal(t, wantError, pe.ConsumeProfiles(ctx, pd))
+	}
+}
+
+func checkWrapSpanForProfilesExporter(t *testing.T, sr *tracetest.SpanRecorder, tracer trace.Tracer, pe exporterprofiles.Profiles,
+	wantError error, numSamples int64) { // nolint: unparam
+	const numRequests = 5
+	generateProfilesTraffic(t, tracer, pe, numRequests, wantError)
+
+	// Inspection time!
+	gotSpanData := sr.Ended()
+	require.Equal(t, numRequests+1, len(gotSpanData))
+
+	parentSpan := gotSpanData[numRequests]
+	require.Equalf(t, fakeProfilesParentSpanName, parentSpan.Name(), "SpanData %v", parentSpan)
+	for _, sd := range gotSpanData[:numRequests] {
+		require.Equalf(t, parentSpan.SpanContext(), sd.Parent(), "Exporter span not a child\nSpanData %v", sd)
+		checkStatus(t, sd, wantError)
+
+		sentSamples := numSamples
+		var failedToSendSamples int64
+		if wantError != nil {
+			sentSamples = 0
+			failedToSendSamples = numSamples
+		}
+		require.Containsf(t, sd.Attributes(), attribute.KeyValue{Key: obsmetrics.SentSamplesKey, Value: attribute.Int64Value(sentSamples)}, "SpanData %v", sd)
+		require.Containsf(t, sd.Attributes(), attribute.KeyValue{Key: obsmetrics.FailedToSendSamplesKey, Value: attribute.Int64Value(failedToSendSamples)}, "SpanData %v", sd)
+	}
+}
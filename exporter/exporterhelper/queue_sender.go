@@ -6,6 +6,9 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -14,13 +17,28 @@ import (
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterqueue"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
 	"go.opentelemetry.io/collector/exporter/internal/queue"
 	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
 )
 
-const defaultQueueSize = 1000
+const (
+	defaultQueueSize = 1000
+
+	// drainPollInterval is how often the queue is checked for being empty while it's drained on shutdown.
+	drainPollInterval = 10 * time.Millisecond
+)
+
+var (
+	errDrainTimeout      = errors.New("shutdown drain timeout expired")
+	errQueueNotEnabled   = errors.New("sending queue is not enabled")
+	errQueueNotPurgeable = errors.New("sending queue cannot be purged")
+	errNotSizedInBytes   = errors.New("sending queue is sized in bytes, but the request doesn't implement the BytesSize() int method")
+)
 
 // QueueSettings defines configuration for queueing batches before sending to the consumerSender.
 type QueueSettings struct {
@@ -32,9 +50,30 @@ type QueueSettings struct {
 	NumConsumers int `mapstructure:"num_consumers"`
 	// QueueSize is the maximum number of batches allowed in queue at a given time.
 	QueueSize int `mapstructure:"queue_size"`
+	// QueueSizeBytes is the maximum total size, in bytes, of the batches allowed in queue at a given time.
+	// When set, it takes precedence over QueueSize and the queue is bounded by the proto-marshaled size of the batches.
+	QueueSizeBytes int64 `mapstructure:"queue_size_bytes"`
 	// StorageID if not empty, enables the persistent storage and uses the component specified
 	// as a storage extension for the persistent queue
 	StorageID *component.ID `mapstructure:"storage"`
+	// SpillOver configures the queue to keep the batches in memory and to use the storage only as an overflow,
+	// when the memory is full or when the exports have been failing for a while. It requires StorageID to be set.
+	SpillOver exporterqueue.SpillOverConfig `mapstructure:"spill_over"`
+	// AdaptiveConcurrency configures the number of batches exported concurrently to be adjusted based on the
+	// observed latency and errors. NumConsumers is then the initial number of batches exported concurrently.
+	AdaptiveConcurrency exporterqueue.AdaptiveConcurrencyConfig `mapstructure:"adaptive_concurrency"`
+	// MetadataKeys is the list of client.Metadata keys stored with the batches in the persistent storage, and restored
+	// when the batches are exported, e.g. to keep the tenant of the data received. It requires StorageID to be set.
+	MetadataKeys []string `mapstructure:"metadata_keys"`
+	// Compression is the algorithm the batches are compressed with in the persistent storage.
+	// It requires StorageID to be set.
+	Compression configcompression.Type `mapstructure:"compression"`
+	// Encryption configures the batches to be encrypted in the persistent storage. It requires StorageID to be set.
+	Encryption exporterqueue.EncryptionConfig `mapstructure:"encryption"`
+	// ShutdownDrainTimeout is the maximum duration the exporter keeps sending the queued batches on shutdown,
+	// with the retries still enabled, until the queue is empty. Once it expires, the batches left are kept in the
+	// storage if StorageID is set, or dropped otherwise. Zero disables the draining.
+	ShutdownDrainTimeout time.Duration `mapstructure:"shutdown_drain_timeout"`
 }
 
 // NewDefaultQueueSettings returns the default settings for QueueSettings.
@@ -55,7 +94,11 @@ func (qCfg *QueueSettings) Validate() error {
 		return nil
 	}
 
-	if qCfg.QueueSize <= 0 {
+	if qCfg.QueueSizeBytes < 0 {
+		return errors.New("queue size in bytes must not be negative")
+	}
+
+	if qCfg.QueueSizeBytes == 0 && qCfg.QueueSize <= 0 {
 		return errors.New("queue size must be positive")
 	}
 
@@ -63,30 +106,86 @@ func (qCfg *QueueSettings) Validate() error {
 		return errors.New("number of queue consumers must be positive")
 	}
 
-	return nil
+	if qCfg.SpillOver.Enabled && qCfg.StorageID == nil {
+		return errors.New("spill over requires a storage to be set")
+	}
+
+	if len(qCfg.MetadataKeys) > 0 && qCfg.StorageID == nil {
+		return errors.New("metadata keys require a storage to be set")
+	}
+
+	if qCfg.ShutdownDrainTimeout < 0 {
+		return errors.New("shutdown drain timeout must not be negative")
+	}
+
+	if qCfg.Compression.IsCompressed() && qCfg.StorageID == nil {
+		return errors.New("compression requires a storage to be set")
+	}
+
+	if qCfg.Encryption.Enabled && qCfg.StorageID == nil {
+		return errors.New("encryption requires a storage to be set")
+	}
+
+	if err := qCfg.AdaptiveConcurrency.Validate(); err != nil {
+		return err
+	}
+
+	if err := qCfg.Encryption.Validate(); err != nil {
+		return err
+	}
+
+	return qCfg.SpillOver.Validate()
 }
 
 type queueSender struct {
 	baseRequestSender
-	queue          exporterqueue.Queue[Request]
-	numConsumers   int
+	queue        exporterqueue.Queue[Request]
+	numConsumers int
+	sizedInBytes bool
+	// persistent indicates whether the requests failing because of the shutdown are kept to be sent after restart.
+	persistent     bool
 	traceAttribute attribute.KeyValue
 	consumers      *queue.Consumers[Request]
+	// limiter is set if the number of requests exported concurrently is adjusted based on the export results.
+	limiter *queue.AdaptiveLimiter
 
 	obsrep     *obsReport
 	exporterID component.ID
+	logger     *zap.Logger
+
+	drainTimeout time.Duration
+	// drainExpired is set once the shutdown drain timeout expired, so the requests left are not sent anymore.
+	drainExpired atomic.Bool
+	// abandonedItems is the number of items dropped by the memory queue once the drain timeout expired.
+	abandonedItems atomic.Int64
+	// inFlight is the number of requests taken from the queue that are being exported.
+	inFlight atomic.Int64
 }
 
-func newQueueSender(q exporterqueue.Queue[Request], set exporter.Settings, numConsumers int,
+func newQueueSender(q exporterqueue.Queue[Request], set exporter.Settings, cfg exporterqueue.Config,
 	exportFailureMessage string, obsrep *obsReport) *queueSender {
 	qs := &queueSender{
 		queue:          q,
-		numConsumers:   numConsumers,
+		numConsumers:   cfg.NumConsumers,
+		sizedInBytes:   cfg.QueueSizeBytes > 0,
+		persistent:     queue.IsPersistent[Request](q),
 		traceAttribute: attribute.String(obsmetrics.ExporterKey, set.ID.String()),
 		obsrep:         obsrep,
 		exporterID:     set.ID,
+		logger:         set.Logger,
+		drainTimeout:   cfg.ShutdownDrainTimeout,
 	}
 	consumeFunc := func(ctx context.Context, req Request) error {
+		if qs.drainExpired.Load() {
+			if qs.persistent {
+				// Leave the request taken before the consumers were stopped in the storage to be sent after restart.
+				return experr.NewShutdownErr(errDrainTimeout)
+			}
+			qs.abandonedItems.Add(int64(req.ItemsCount()))
+			return nil
+		}
+		qs.inFlight.Add(1)
+		defer qs.inFlight.Add(-1)
 		err := qs.nextSender.send(ctx, req)
 		if err != nil {
 			set.Logger.Error("Exporting failed. Dropping data."+exportFailureMessage,
@@ -94,10 +193,44 @@ func newQueueSender(q exporterqueue.Queue[Request], set exporter.Settings, numCo
 		}
 		return err
 	}
-	qs.consumers = queue.NewQueueConsumers[Request](q, numConsumers, consumeFunc)
+	if cfg.AdaptiveConcurrency.Enabled {
+		acCfg := cfg.AdaptiveConcurrency
+		qs.limiter = queue.NewAdaptiveLimiter(cfg.NumConsumers, acCfg.MinConsumers, acCfg.MaxConsumers)
+		qs.consumers = queue.NewAdaptiveQueueConsumers[Request](q, acCfg.MaxConsumers, qs.limiter, consumeFunc)
+		return qs
+	}
+	qs.consumers = queue.NewQueueConsumers[Request](q, cfg.NumConsumers, consumeFunc)
 	return qs
 }
 
+// attemptLimiterSender tells the adaptive limiter of the queue about the result of every attempt to export. It's
+// placed below the retry sender, so the limiter reacts to the failed attempts before the retries are exhausted.
+type attemptLimiterSender struct {
+	baseRequestSender
+	limiter *queue.AdaptiveLimiter
+}
+
+func (as *attemptLimiterSender) send(ctx context.Context, req Request) error {
+	token := as.limiter.StartAttempt()
+	start := time.Now()
+	err := as.nextSender.send(ctx, req)
+	as.limiter.RecordAttempt(token, classifyExportErr(err), time.Since(start))
+	return err
+}
+
+// classifyExportErr tells the adaptive limiter whether the export error means that the destination is overloaded.
+// Data rejected by the destination and exports interrupted by the shutdown tell nothing about its load.
+func classifyExportErr(err error) queue.ExportResult {
+	switch {
+	case err == nil:
+		return queue.ExportSucceeded
+	case consumererror.IsPermanent(err), experr.IsShutdownErr(err), errors.Is(err, context.Canceled):
+		return queue.ExportIgnored
+	default:
+		return queue.ExportOverloaded
+	}
+}
+
 // Start is invoked during service startup.
 func (qs *queueSender) Start(ctx context.Context, host component.Host) error {
 	if err := qs.consumers.Start(ctx, host); err != nil {
@@ -105,19 +238,132 @@ func (qs *queueSender) Start(ctx context.Context, host component.Host) error {
 	}
 
 	dataTypeAttr := attribute.String(obsmetrics.DataTypeKey, qs.obsrep.dataType.String())
-	return multierr.Append(
-		qs.obsrep.telemetryBuilder.InitExporterQueueSize(func() int64 { return int64(qs.queue.Size()) },
+	if qs.limiter != nil {
+		if err := qs.obsrep.telemetryBuilder.InitExporterQueueConcurrencyLimit(func() int64 { return int64(qs.limiter.Limit()) },
+			metric.WithAttributeSet(attribute.NewSet(qs.traceAttribute, dataTypeAttr))); err != nil {
+			return err
+		}
+	}
+	if !qs.sizedInBytes {
+		return multierr.Append(
+			qs.obsrep.telemetryBuilder.InitExporterQueueSize(func() int64 { return int64(qs.queue.Size()) },
+				metric.WithAttributeSet(attribute.NewSet(qs.traceAttribute, dataTypeAttr))),
+			qs.obsrep.telemetryBuilder.InitExporterQueueCapacity(func() int64 { return int64(qs.queue.Capacity()) },
+				metric.WithAttributeSet(attribute.NewSet(qs.traceAttribute))),
+		)
+	}
+
+	// The queue sized in bytes has no capacity in batches, only the number of batches it holds is reported.
+	err := multierr.Append(
+		qs.obsrep.telemetryBuilder.InitExporterQueueSizeBytes(func() int64 { return int64(qs.queue.Size()) },
 			metric.WithAttributeSet(attribute.NewSet(qs.traceAttribute, dataTypeAttr))),
-		qs.obsrep.telemetryBuilder.InitExporterQueueCapacity(func() int64 { return int64(qs.queue.Capacity()) },
+		qs.obsrep.telemetryBuilder.InitExporterQueueCapacityBytes(func() int64 { return int64(qs.queue.Capacity()) },
 			metric.WithAttributeSet(attribute.NewSet(qs.traceAttribute))),
 	)
+	if _, ok := queue.Length[Request](qs.queue); ok {
+		err = multierr.Append(err, qs.obsrep.telemetryBuilder.InitExporterQueueSize(func() int64 {
+			length, _ := queue.Length[Request](qs.queue)
+			return int64(length)
+		}, metric.WithAttributeSet(attribute.NewSet(qs.traceAttribute, dataTypeAttr))))
+	}
+	return err
 }
 
 // Shutdown is invoked during service shutdown.
 func (qs *queueSender) Shutdown(ctx context.Context) error {
 	// Stop the queue and consumers, this will drain the queue and will call the retry (which is stopped) that will only
 	// try once every request.
-	return qs.consumers.Shutdown(ctx)
+	if err := qs.consumers.Shutdown(ctx); err != nil {
+		return err
+	}
+	if abandoned := qs.abandonedItems.Load(); abandoned > 0 {
+		qs.logger.Error("Shutdown drain timeout expired. Dropping data.", zap.Int64("dropped_items", abandoned))
+	}
+	return nil
+}
+
+// unit returns the unit the queue is sized in.
+func (qs *queueSender) unit() string {
+	if qs.sizedInBytes {
+		return "bytes"
+	}
+	return "requests"
+}
+
+// checkSizedInBytes returns an error if the queue is sized in bytes, and the request cannot be sized in bytes.
+func (qs *queueSender) checkSizedInBytes(req Request) error {
+	if !qs.sizedInBytes {
+		return nil
+	}
+	if _, ok := req.(interface{ BytesSize() int }); !ok {
+		return fmt.Errorf("%w: %T", errNotSizedInBytes, req)
+	}
+	return nil
+}
+
+// status returns the status of the queue and of its consumers.
+func (qs *queueSender) status() exporterqueue.Status {
+	st := exporterqueue.Status{
+		Kind:      queue.Kind[Request](qs.queue),
+		Size:      qs.queue.Size(),
+		Capacity:  qs.queue.Capacity(),
+		Unit:      qs.unit(),
+		Consumers: qs.consumers.NumConsumers(),
+		Paused:    qs.consumers.Paused(),
+	}
+	if qs.limiter != nil {
+		st.Consumers = qs.limiter.Limit()
+	}
+	if iq, ok := qs.queue.(queue.Inspectable); ok {
+		if oldest, ok := iq.OldestItemTime(); ok {
+			st.OldestItemAge = time.Since(oldest)
+		}
+	}
+	return st
+}
+
+// purge drops the requests waiting in the queue.
+func (qs *queueSender) purge(ctx context.Context) (int, error) {
+	iq, ok := qs.queue.(queue.Inspectable)
+	if !ok {
+		return 0, errQueueNotPurgeable
+	}
+	n, err := iq.Purge(ctx)
+	if err != nil {
+		return n, err
+	}
+	qs.logger.Warn("Sending queue purged. Dropping data.", zap.Int("dropped_requests", n))
+	return n, nil
+}
+
+// drain keeps the consumers sending the queued requests, with the retries still enabled, until the queue is empty
+// or the shutdown drain timeout expires. Then the requests left are kept in the storage by the persistent queue,
+// or dropped by the memory queue.
+func (qs *queueSender) drain(ctx context.Context) {
+	if qs.drainTimeout <= 0 {
+		return
+	}
+	// The queue cannot be drained if it's paused.
+	qs.consumers.Resume()
+	ctx, cancel := context.WithTimeout(ctx, qs.drainTimeout)
+	defer cancel()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for qs.queue.Size() > 0 || qs.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			qs.drainExpired.Store(true)
+			if qs.persistent {
+				// Stop reading from the storage, so the requests left keep their order and are not marked
+				// as dispatched. They are sent after restart.
+				qs.consumers.Stop()
+				qs.logger.Warn("Shutdown drain timeout expired. The queued data is kept in the storage to be sent after restart.",
+					zap.Int("queue_size", qs.queue.Size()), zap.String("unit", qs.unit()))
+			}
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 // send implements the requestSender interface. It puts the request in the queue.
@@ -127,6 +373,10 @@ func (qs *queueSender) send(ctx context.Context, req Request) error {
 	c := context.WithoutCancel(ctx)
 
 	span := trace.SpanFromContext(c)
+	if err := qs.checkSizedInBytes(req); err != nil {
+		// The request would be accounted as zero bytes, and the queue would be unbounded.
+		return consumererror.NewPermanent(err)
+	}
 	if err := qs.queue.Offer(c, req); err != nil {
 		span.AddEvent("Failed to enqueue item.", trace.WithAttributes(qs.traceAttribute))
 		return err
@@ -5,7 +5,10 @@ package exporterhelper
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -17,12 +20,19 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterqueue"
 	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
 	"go.opentelemetry.io/collector/exporter/internal/queue"
 	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/testdata"
 )
 
 func TestQueuedRetry_StopWhileWaiting(t *testing.T) {
@@ -59,6 +69,138 @@ func TestQueuedRetry_StopWhileWaiting(t *testing.T) {
 	require.Zero(t, be.queueSender.(*queueSender).queue.Size())
 }
 
+func TestQueuedRetry_ShutdownDrain(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.ShutdownDrainTimeout = 5 * time.Second
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = 10 * time.Millisecond
+	be, err := newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender,
+		withMarshaler(mockRequestMarshaler), withUnmarshaler(mockRequestUnmarshaler(&mockRequest{})),
+		WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Every request fails once, so it's only sent if the retries are still enabled while the queue is drained.
+	reqs := make([]*mockRequest, 3)
+	for i := range reqs {
+		reqs[i] = newMockRequest(2, errors.New("transient error"))
+		require.NoError(t, be.send(context.Background(), reqs[i]))
+	}
+
+	require.NoError(t, be.Shutdown(context.Background()))
+	for _, req := range reqs {
+		assert.Equal(t, int64(2), req.requestCount.Load())
+	}
+	assert.Zero(t, be.queueSender.(*queueSender).queue.Size())
+}
+
+func TestQueuedRetry_ShutdownDrainTimeoutDropsData(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.ShutdownDrainTimeout = 50 * time.Millisecond
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 0 // retry infinitely so the drain timeout expires
+	set := exportertest.NewNopSettings()
+	logger, observed := observer.New(zap.WarnLevel)
+	set.Logger = zap.New(logger)
+	be, err := newBaseExporter(set, defaultDataType, newNoopObsrepSender,
+		withMarshaler(mockRequestMarshaler), withUnmarshaler(mockRequestUnmarshaler(&mockRequest{})),
+		WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, be.send(context.Background(), newErrorRequest()))
+	}
+	// Wait for the first request to be retried by the consumer, so the other two are the ones left in the queue.
+	assert.Eventually(t, func() bool {
+		return be.queueSender.(*queueSender).queue.Size() == 2
+	}, time.Second, time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, be.Shutdown(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// The request being retried fails with the retries stopped, the requests left in the queue are dropped.
+	logs := observed.FilterMessage("Shutdown drain timeout expired. Dropping data.").All()
+	require.Len(t, logs, 1)
+	assert.Equal(t, int64(14), logs[0].ContextMap()["dropped_items"])
+	assert.Zero(t, be.queueSender.(*queueSender).queue.Size())
+}
+
+func TestQueuedRetry_Inspector(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Minute
+	cbCfg := NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	be, err := newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender,
+		withMarshaler(mockRequestMarshaler), withUnmarshaler(mockRequestUnmarshaler(&mockRequest{})),
+		WithRetry(rCfg), WithCircuitBreaker(cbCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	st, ok := be.QueueStatus()
+	require.True(t, ok)
+	assert.Equal(t, exporterqueue.Status{
+		Kind:                "memory",
+		Capacity:            defaultQueueSize,
+		Unit:                "requests",
+		Consumers:           1,
+		CircuitBreakerState: "closed",
+	}, st)
+
+	// The consumer waits to retry the failed request.
+	require.NoError(t, be.send(context.Background(), newErrorRequest()))
+	assert.Eventually(t, func() bool {
+		st, _ = be.QueueStatus()
+		return st.RetryingRequests == 1
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, be.PauseQueue())
+	reqs := []*mockRequest{newMockRequest(2, nil), newMockRequest(3, nil)}
+	for _, req := range reqs {
+		require.NoError(t, be.send(context.Background(), req))
+	}
+	st, _ = be.QueueStatus()
+	assert.True(t, st.Paused)
+	assert.Equal(t, 2, st.Size)
+	assert.Positive(t, st.OldestItemAge)
+
+	n, err := be.PurgeQueue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	st, _ = be.QueueStatus()
+	assert.Zero(t, st.Size)
+	assert.Zero(t, st.OldestItemAge)
+
+	require.NoError(t, be.ResumeQueue())
+	st, _ = be.QueueStatus()
+	assert.False(t, st.Paused)
+	require.NoError(t, be.Shutdown(context.Background()))
+	for _, req := range reqs {
+		assert.Zero(t, req.requestCount.Load())
+	}
+}
+
+func TestQueuedRetry_InspectorDisabledQueue(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	be, err := newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender,
+		withMarshaler(mockRequestMarshaler), withUnmarshaler(mockRequestUnmarshaler(&mockRequest{})), WithQueue(qCfg))
+	require.NoError(t, err)
+
+	_, ok := be.QueueStatus()
+	assert.False(t, ok)
+	assert.ErrorIs(t, be.PauseQueue(), errQueueNotEnabled)
+	assert.ErrorIs(t, be.ResumeQueue(), errQueueNotEnabled)
+	_, err = be.PurgeQueue(context.Background())
+	assert.ErrorIs(t, err, errQueueNotEnabled)
+}
+
 func TestQueuedRetry_DoNotPreserveCancellation(t *testing.T) {
 	qCfg := NewDefaultQueueSettings()
 	qCfg.NumConsumers = 1
@@ -232,6 +374,151 @@ func TestQueuedRetry_QueueMetricsReported(t *testing.T) {
 	}
 }
 
+func TestQueuedRetry_QueueBytesMetricsReported(t *testing.T) {
+	tt, err := componenttest.SetupTelemetry(defaultID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 0 // to make every request go straight to the queue
+	qCfg.QueueSizeBytes = 1 << 20
+	set := exporter.Settings{ID: defaultID, TelemetrySettings: tt.TelemetrySettings(), BuildInfo: component.NewDefaultBuildInfo()}
+	te, err := NewTracesExporter(context.Background(), set, &fakeTracesExporterConfig, newTraceDataPusher(nil), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, tt.CheckExporterMetricGauge("otelcol_exporter_queue_capacity_bytes", int64(1<<20)))
+
+	td := testdata.GenerateTraces(10)
+	require.NoError(t, te.ConsumeTraces(context.Background(), td))
+	require.NoError(t, tt.CheckExporterMetricGauge("otelcol_exporter_queue_size_bytes", int64(tracesMarshaler.TracesSize(td)),
+		attribute.String(obsmetrics.DataTypeKey, component.DataTypeTraces.String())))
+	// The size of the queue keeps being reported in batches.
+	require.NoError(t, tt.CheckExporterMetricGauge("otelcol_exporter_queue_size", int64(1),
+		attribute.String(obsmetrics.DataTypeKey, component.DataTypeTraces.String())))
+
+	assert.NoError(t, te.Shutdown(context.Background()))
+}
+
+func TestQueuedRetry_AdaptiveConcurrency(t *testing.T) {
+	tt, err := componenttest.SetupTelemetry(defaultID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 4
+	qCfg.AdaptiveConcurrency = exporterqueue.AdaptiveConcurrencyConfig{Enabled: true, MinConsumers: 1, MaxConsumers: 8}
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.Enabled = false
+	set := exporter.Settings{ID: defaultID, TelemetrySettings: tt.TelemetrySettings(), BuildInfo: component.NewDefaultBuildInfo()}
+	be, err := newBaseExporter(set, defaultDataType, newObservabilityConsumerSender,
+		withMarshaler(mockRequestMarshaler), withUnmarshaler(mockRequestUnmarshaler(&mockRequest{})),
+		WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	ocs := be.obsrepSender.(*observabilityConsumerSender)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	require.NoError(t, tt.CheckExporterMetricGauge("otelcol_exporter_queue_concurrency_limit", int64(4),
+		attribute.String(obsmetrics.DataTypeKey, defaultDataType.String())))
+
+	// The throttled exports decrease the concurrency limit.
+	for i := 0; i < 10; i++ {
+		ocs.run(func() {
+			require.NoError(t, be.send(context.Background(), newErrorRequest()))
+		})
+	}
+	ocs.awaitAsyncProcessing()
+	assert.Equal(t, 1, be.queueSender.(*queueSender).limiter.Limit())
+	require.NoError(t, tt.CheckExporterMetricGauge("otelcol_exporter_queue_concurrency_limit", int64(1),
+		attribute.String(obsmetrics.DataTypeKey, defaultDataType.String())))
+}
+
+func TestQueuedRetry_AdaptiveConcurrencyThrottledAttempt(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 4
+	qCfg.AdaptiveConcurrency = exporterqueue.AdaptiveConcurrencyConfig{Enabled: true, MinConsumers: 1, MaxConsumers: 8}
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = time.Minute
+
+	// The destination throttles the exports until it's released.
+	released := make(chan struct{})
+	var attempts atomic.Int64
+	sink := new(consumertest.TracesSink)
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+		func(ctx context.Context, td ptrace.Traces) error {
+			attempts.Add(1)
+			select {
+			case <-released:
+				return sink.ConsumeTraces(ctx, td)
+			default:
+				return NewThrottleRetry(errors.New("throttled"), time.Millisecond)
+			}
+		}, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, te.Shutdown(context.Background()))
+	})
+	limiter := te.(*traceExporter).queueSender.(*queueSender).limiter
+
+	// The throttled attempts decrease the limit while the request is still retried.
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	assert.Eventually(t, func() bool { return limiter.Limit() < 4 }, time.Second, time.Millisecond)
+	assert.Empty(t, sink.AllTraces())
+	assert.Positive(t, attempts.Load())
+
+	close(released)
+	assert.Eventually(t, func() bool { return len(sink.AllTraces()) == 1 }, time.Second, time.Millisecond)
+}
+
+func TestClassifyExportErr(t *testing.T) {
+	assert.Equal(t, queue.ExportSucceeded, classifyExportErr(nil))
+	assert.Equal(t, queue.ExportIgnored, classifyExportErr(consumererror.NewPermanent(errors.New("bad data"))))
+	assert.Equal(t, queue.ExportIgnored, classifyExportErr(experr.NewShutdownErr(errors.New("stopped"))))
+	assert.Equal(t, queue.ExportIgnored, classifyExportErr(context.Canceled))
+	assert.Equal(t, queue.ExportOverloaded, classifyExportErr(NewThrottleRetry(errors.New("throttled"), time.Second)))
+	assert.Equal(t, queue.ExportOverloaded, classifyExportErr(context.DeadlineExceeded))
+}
+
+func TestQueuedRetry_RejectOnFullBytes(t *testing.T) {
+	td := testdata.GenerateTraces(10)
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 0 // to make every request go straight to the queue
+	qCfg.QueueSizeBytes = int64(tracesMarshaler.TracesSize(td)) + 1
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(nil), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, te.Shutdown(context.Background()))
+	})
+
+	require.NoError(t, te.ConsumeTraces(context.Background(), td))
+	require.ErrorIs(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)), queue.ErrQueueIsFull)
+}
+
+func TestQueuedRetry_RejectNotSizedInBytes(t *testing.T) {
+	qCfg := exporterqueue.NewDefaultConfig()
+	qCfg.QueueSizeBytes = 1 << 20
+	// The requests are checked when they are sent, the exporter is created without calling the converter.
+	te, err := NewTracesRequestExporter(context.Background(), exportertest.NewNopSettings(),
+		(&fakeRequestConverter{}).requestFromTracesFunc,
+		WithRequestQueue(qCfg, exporterqueue.NewMemoryQueueFactory[Request]()))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, te.Shutdown(context.Background()))
+	})
+
+	err = te.ConsumeTraces(context.Background(), testdata.GenerateTraces(1))
+	require.ErrorIs(t, err, errNotSizedInBytes)
+	assert.True(t, consumererror.IsPermanent(err))
+}
+
 func TestNoCancellationContext(t *testing.T) {
 	deadline := time.Now().Add(1 * time.Second)
 	ctx, cancelFunc := context.WithDeadline(context.Background(), deadline)
@@ -255,11 +542,55 @@ func TestQueueSettings_Validate(t *testing.T) {
 	qCfg.QueueSize = 0
 	assert.EqualError(t, qCfg.Validate(), "queue size must be positive")
 
+	// The queue size is not required when the queue is sized in bytes.
+	qCfg.QueueSizeBytes = 1024
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.QueueSizeBytes = -1
+	assert.EqualError(t, qCfg.Validate(), "queue size in bytes must not be negative")
+
 	qCfg = NewDefaultQueueSettings()
 	qCfg.NumConsumers = 0
 
 	assert.EqualError(t, qCfg.Validate(), "number of queue consumers must be positive")
 
+	qCfg = NewDefaultQueueSettings()
+	qCfg.SpillOver.Enabled = true
+	qCfg.SpillOver.MemoryQueueSize = 100
+	assert.EqualError(t, qCfg.Validate(), "spill over requires a storage to be set")
+
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.SpillOver.MemoryQueueSize = 0
+	assert.EqualError(t, qCfg.Validate(), "spill over memory queue size must be positive")
+
+	qCfg = NewDefaultQueueSettings()
+	qCfg.AdaptiveConcurrency.Enabled = true
+	qCfg.AdaptiveConcurrency.MinConsumers = 5
+	qCfg.AdaptiveConcurrency.MaxConsumers = 2
+	assert.EqualError(t, qCfg.Validate(), "adaptive concurrency max_consumers must not be lower than min_consumers")
+
+	qCfg = NewDefaultQueueSettings()
+	qCfg.MetadataKeys = []string{"X-Tenant"}
+	assert.EqualError(t, qCfg.Validate(), "metadata keys require a storage to be set")
+
+	qCfg = NewDefaultQueueSettings()
+	qCfg.Compression = configcompression.TypeZstd
+	assert.EqualError(t, qCfg.Validate(), "compression requires a storage to be set")
+
+	qCfg = NewDefaultQueueSettings()
+	qCfg.Encryption.Enabled = true
+	assert.EqualError(t, qCfg.Validate(), "encryption requires a storage to be set")
+
+	qCfg.StorageID = &storageID
+	assert.EqualError(t, qCfg.Validate(), "encryption requires at least one key")
+
+	qCfg = NewDefaultQueueSettings()
+	qCfg.ShutdownDrainTimeout = -time.Second
+	assert.EqualError(t, qCfg.Validate(), "shutdown drain timeout must not be negative")
+
 	// Confirm Validate doesn't return error with invalid config when feature is disabled
 	qCfg.Enabled = false
 	assert.NoError(t, qCfg.Validate())
@@ -429,6 +760,206 @@ func TestQueuedRetryPersistentEnabled_NoDataLossOnShutdown(t *testing.T) {
 	replacedReq.checkNumRequests(t, 1)
 }
 
+func TestQueuedRetrySpillOver_NoDataLossOnShutdown(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID
+	qCfg.SpillOver.Enabled = true
+	qCfg.SpillOver.MemoryQueueSize = 10
+
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 0 // retry infinitely so shutdown can be triggered
+
+	mockReq := newErrorRequest()
+	be, err := newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender, withMarshaler(mockRequestMarshaler),
+		withUnmarshaler(mockRequestUnmarshaler(mockReq)), WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+
+	var extensions = map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}
+	host := &mockHost{ext: extensions}
+
+	require.NoError(t, be.Start(context.Background(), host))
+
+	// The request is kept in memory, the storage is not used yet.
+	require.NoError(t, be.send(context.Background(), mockReq))
+	assert.Eventually(t, func() bool {
+		return be.queueSender.(*queueSender).queue.Size() == 0
+	}, time.Second, 1*time.Millisecond)
+
+	// shuts down the exporter, the in-flight request should be moved to the storage.
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	// start the exporter again replacing the preserved mockRequest in the unmarshaler with a new one that doesn't fail.
+	replacedReq := newMockRequest(1, nil)
+	be, err = newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender, withMarshaler(mockRequestMarshaler),
+		withUnmarshaler(mockRequestUnmarshaler(replacedReq)), WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, be.Shutdown(context.Background())) })
+
+	// wait for the item to be consumed from the queue
+	replacedReq.checkNumRequests(t, 1)
+}
+
+func TestQueuedRetryPersistentEnabled_ShutdownDrainTimeout(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.ShutdownDrainTimeout = 50 * time.Millisecond
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID
+
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 0 // retry infinitely so the drain timeout expires
+
+	set := exportertest.NewNopSettings()
+	logger, observed := observer.New(zap.WarnLevel)
+	set.Logger = zap.New(logger)
+	mockReq := newErrorRequest()
+	be, err := newBaseExporter(set, defaultDataType, newNoopObsrepSender, withMarshaler(mockRequestMarshaler),
+		withUnmarshaler(mockRequestUnmarshaler(mockReq)), WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+
+	var extensions = map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}
+	host := &mockHost{ext: extensions}
+	require.NoError(t, be.Start(context.Background(), host))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, be.send(context.Background(), mockReq))
+	}
+	assert.Eventually(t, func() bool {
+		return be.queueSender.(*queueSender).queue.Size() == 2
+	}, time.Second, time.Millisecond)
+
+	// The requests not sent before the drain timeout expires are kept in the storage.
+	require.NoError(t, be.Shutdown(context.Background()))
+	logs := observed.FilterMessage("Shutdown drain timeout expired. The queued data is kept in the storage to be sent after restart.").All()
+	require.Len(t, logs, 1)
+	assert.Equal(t, int64(2), logs[0].ContextMap()["queue_size"])
+	assert.Empty(t, observed.FilterMessage("Shutdown drain timeout expired. Dropping data.").All())
+
+	// All the requests are sent after restart.
+	replacedReq := newMockRequest(1, nil)
+	be, err = newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender, withMarshaler(mockRequestMarshaler),
+		withUnmarshaler(mockRequestUnmarshaler(replacedReq)), WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, be.Shutdown(context.Background())) })
+	replacedReq.checkNumRequests(t, 3)
+}
+
+func TestQueuedRetryPersistentRequestQueue_ShutdownDrainTimeout(t *testing.T) {
+	qCfg := exporterqueue.NewDefaultConfig()
+	qCfg.NumConsumers = 1
+	qCfg.ShutdownDrainTimeout = 50 * time.Millisecond
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 0 // retry infinitely so the drain timeout expires
+
+	set := exportertest.NewNopSettings()
+	logger, observed := observer.New(zap.WarnLevel)
+	set.Logger = zap.New(logger)
+	mockReq := newErrorRequest()
+	// The queue is persistent because of the factory, there is no storage in the queue settings of the request exporters.
+	be, err := newBaseExporter(set, defaultDataType, newNoopObsrepSender, WithRetry(rCfg),
+		WithRequestQueue(qCfg, exporterqueue.NewPersistentQueueFactory[Request](&storageID, exporterqueue.PersistentQueueSettings[Request]{
+			Marshaler:   mockRequestMarshaler,
+			Unmarshaler: mockRequestUnmarshaler(mockReq),
+		})))
+	require.NoError(t, err)
+
+	var extensions = map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}
+	host := &mockHost{ext: extensions}
+	require.NoError(t, be.Start(context.Background(), host))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, be.send(context.Background(), mockReq))
+	}
+	assert.Eventually(t, func() bool {
+		return be.queueSender.(*queueSender).queue.Size() == 2
+	}, time.Second, time.Millisecond)
+
+	// The requests not sent before the drain timeout expires are kept in the storage.
+	require.NoError(t, be.Shutdown(context.Background()))
+	assert.Len(t, observed.FilterMessage("Shutdown drain timeout expired. The queued data is kept in the storage to be sent after restart.").All(), 1)
+	assert.Empty(t, observed.FilterMessage("Shutdown drain timeout expired. Dropping data.").All())
+
+	// All the requests are sent after restart.
+	replacedReq := newMockRequest(1, nil)
+	be, err = newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender, WithRetry(rCfg),
+		WithRequestQueue(qCfg, exporterqueue.NewPersistentQueueFactory[Request](&storageID, exporterqueue.PersistentQueueSettings[Request]{
+			Marshaler:   mockRequestMarshaler,
+			Unmarshaler: mockRequestUnmarshaler(replacedReq),
+		})))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, be.Shutdown(context.Background())) })
+	replacedReq.checkNumRequests(t, 3)
+}
+
+func TestQueuedRetryPersistentEnabled_EncryptionKeyRotation(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID
+	key1 := exporterqueue.EncryptionKeyConfig{ID: "k1", Key: configopaque.String(base64.StdEncoding.EncodeToString(make([]byte, 32)))}
+	qCfg.Encryption = exporterqueue.EncryptionConfig{Enabled: true, Keys: []exporterqueue.EncryptionKeyConfig{key1}}
+
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 0 // retry infinitely so shutdown can be triggered
+
+	mockReq := newErrorRequest()
+	be, err := newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender, withMarshaler(mockRequestMarshaler),
+		withUnmarshaler(mockRequestUnmarshaler(mockReq)), WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+
+	var extensions = map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}
+	host := &mockHost{ext: extensions}
+	require.NoError(t, be.Start(context.Background(), host))
+	require.NoError(t, be.send(context.Background(), mockReq))
+	assert.Eventually(t, func() bool {
+		return be.queueSender.(*queueSender).queue.Size() == 0
+	}, time.Second, 1*time.Millisecond)
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	// The request encrypted with the previous key is sent after restart with a new active key.
+	key2 := exporterqueue.EncryptionKeyConfig{ID: "k2", Key: configopaque.String(base64.StdEncoding.EncodeToString(make([]byte, 16)))}
+	qCfg.Encryption.Keys = append(qCfg.Encryption.Keys, key2)
+	qCfg.Encryption.ActiveKey = "k2"
+	replacedReq := newMockRequest(1, nil)
+	be, err = newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender, withMarshaler(mockRequestMarshaler),
+		withUnmarshaler(mockRequestUnmarshaler(replacedReq)), WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, be.Shutdown(context.Background())) })
+	replacedReq.checkNumRequests(t, 1)
+}
+
+func TestQueuedRetry_EncryptionInvalidKey(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID
+	qCfg.Encryption = exporterqueue.EncryptionConfig{Enabled: true, Keys: []exporterqueue.EncryptionKeyConfig{
+		{ID: "k1", KeyFile: filepath.Join(t.TempDir(), "missing")},
+	}}
+	_, err := newBaseExporter(defaultSettings, defaultDataType, newNoopObsrepSender, withMarshaler(mockRequestMarshaler),
+		withUnmarshaler(mockRequestUnmarshaler(&mockRequest{})), WithQueue(qCfg))
+	assert.ErrorContains(t, err, `failed to read encryption key "k1"`)
+}
+
 func TestQueueSenderNoStartShutdown(t *testing.T) {
 	queue := queue.NewBoundedMemoryQueue[Request](queue.MemoryQueueSettings[Request]{})
 	set := exportertest.NewNopSettings()
@@ -437,7 +968,7 @@ func TestQueueSenderNoStartShutdown(t *testing.T) {
 		exporterCreateSettings: exportertest.NewNopSettings(),
 	})
 	assert.NoError(t, err)
-	qs := newQueueSender(queue, set, 1, "", obsrep)
+	qs := newQueueSender(queue, set, exporterqueue.Config{NumConsumers: 1}, "", obsrep)
 	assert.NoError(t, qs.Shutdown(context.Background()))
 }
 
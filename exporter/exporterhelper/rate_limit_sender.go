@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
+)
+
+var errRateLimited = errors.New("waiting for the rate limit")
+
+// RateLimitSettings defines configuration for limiting the rate at which the exporter sends data, so it stays
+// within the quota enforced by the destination. Every limit is optional, a zero rate disables it.
+// The limits apply to the requests before they're retried, so the retries are not limited.
+type RateLimitSettings struct {
+	// Enabled indicates whether to limit the rate at which the data is sent.
+	Enabled bool `mapstructure:"enabled"`
+	// RequestsPerSecond is the maximum number of requests sent per second.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	// RequestsBurst is the number of requests that can be sent at once, above the rate.
+	// Defaults to RequestsPerSecond, rounded up.
+	RequestsBurst int `mapstructure:"requests_burst"`
+	// ItemsPerSecond is the maximum number of items (spans, metric data points or log records) sent per second.
+	ItemsPerSecond float64 `mapstructure:"items_per_second"`
+	// ItemsBurst is the number of items that can be sent at once, above the rate.
+	// Defaults to ItemsPerSecond, rounded up.
+	ItemsBurst int `mapstructure:"items_burst"`
+	// BytesPerSecond is the maximum number of bytes sent per second, measured as the proto-marshaled size
+	// of the data. It's ignored by the exporters created with New[Traces|Metrics|Logs]RequestExporter unless
+	// the requests implement the `BytesSize() int` method.
+	BytesPerSecond float64 `mapstructure:"bytes_per_second"`
+	// BytesBurst is the number of bytes that can be sent at once, above the rate.
+	// Defaults to BytesPerSecond, rounded up.
+	BytesBurst int `mapstructure:"bytes_burst"`
+}
+
+// NewDefaultRateLimitSettings returns the default settings for RateLimitSettings.
+func NewDefaultRateLimitSettings() RateLimitSettings {
+	return RateLimitSettings{}
+}
+
+// Validate checks if the RateLimitSettings configuration is valid
+func (rlCfg *RateLimitSettings) Validate() error {
+	if !rlCfg.Enabled {
+		return nil
+	}
+	if rlCfg.RequestsPerSecond < 0 || rlCfg.ItemsPerSecond < 0 || rlCfg.BytesPerSecond < 0 {
+		return errors.New("rate limits must not be negative")
+	}
+	if rlCfg.RequestsBurst < 0 || rlCfg.ItemsBurst < 0 || rlCfg.BytesBurst < 0 {
+		return errors.New("rate limit bursts must not be negative")
+	}
+	if rlCfg.RequestsPerSecond == 0 && rlCfg.ItemsPerSecond == 0 && rlCfg.BytesPerSecond == 0 {
+		return errors.New("rate limit requires at least one of requests_per_second, items_per_second or bytes_per_second to be set")
+	}
+	return nil
+}
+
+// rateLimitSender is a requestSender that delays the requests to keep the data sent within the configured rates.
+type rateLimitSender struct {
+	baseRequestSender
+	limits []*rateLimit
+	stopCh chan struct{}
+}
+
+func newRateLimitSender(cfg RateLimitSettings) *rateLimitSender {
+	rls := &rateLimitSender{stopCh: make(chan struct{})}
+	now := time.Now()
+	if cfg.RequestsPerSecond > 0 {
+		rls.limits = append(rls.limits, newRateLimit(&queue.RequestSizer[Request]{}, cfg.RequestsPerSecond, cfg.RequestsBurst, now))
+	}
+	if cfg.ItemsPerSecond > 0 {
+		rls.limits = append(rls.limits, newRateLimit(&queue.ItemsSizer[Request]{}, cfg.ItemsPerSecond, cfg.ItemsBurst, now))
+	}
+	if cfg.BytesPerSecond > 0 {
+		rls.limits = append(rls.limits, newRateLimit(&queue.BytesSizer[Request]{}, cfg.BytesPerSecond, cfg.BytesBurst, now))
+	}
+	return rls
+}
+
+// Shutdown releases the requests waiting for the rate limit with a shutdown error,
+// so the persistent queue can keep them to be sent after restart.
+func (rls *rateLimitSender) Shutdown(context.Context) error {
+	close(rls.stopCh)
+	return nil
+}
+
+func (rls *rateLimitSender) send(ctx context.Context, req Request) error {
+	now := time.Now()
+	var delay time.Duration
+	sizes := make([]float64, len(rls.limits))
+	for i, l := range rls.limits {
+		sizes[i] = float64(l.sizer.Sizeof(req))
+		delay = max(delay, l.reserve(sizes[i], now))
+	}
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			rls.cancel(sizes)
+			return fmt.Errorf("request is cancelled or timed out while %w: %w", errRateLimited, ctx.Err())
+		case <-rls.stopCh:
+			rls.cancel(sizes)
+			return experr.NewShutdownErr(errRateLimited)
+		case <-timer.C:
+		}
+	}
+	return rls.nextSender.send(ctx, req)
+}
+
+// cancel gives back the tokens reserved for a request that is not sent.
+func (rls *rateLimitSender) cancel(sizes []float64) {
+	for i, l := range rls.limits {
+		l.release(sizes[i])
+	}
+}
+
+// rateLimit is a token bucket refilled at the given rate, up to the burst. A request takes as many tokens as its size,
+// possibly more than available, in which case the bucket goes into debt and the request waits until it's repaid.
+// This way, requests bigger than the burst are still sent, at the pace of the rate.
+type rateLimit struct {
+	sizer queue.Sizer[Request]
+	rate  float64
+	burst float64
+
+	// mu guards everything declared below.
+	mu      sync.Mutex
+	tokens  float64
+	updated time.Time
+}
+
+func newRateLimit(sizer queue.Sizer[Request], rate float64, burst int, now time.Time) *rateLimit {
+	b := float64(burst)
+	if burst == 0 {
+		b = math.Ceil(rate)
+	}
+	return &rateLimit{
+		sizer:   sizer,
+		rate:    rate,
+		burst:   b,
+		tokens:  b,
+		updated: now,
+	}
+}
+
+// reserve takes the given number of tokens and returns how long to wait before sending the request.
+func (l *rateLimit) reserve(n float64, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if elapsed := now.Sub(l.updated); elapsed > 0 {
+		l.tokens = min(l.burst, l.tokens+elapsed.Seconds()*l.rate)
+		l.updated = now
+	}
+	l.tokens -= n
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+func (l *rateLimit) release(n float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.burst, l.tokens+n)
+}
@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/testdata"
+)
+
+func TestRateLimitSettings_Validate(t *testing.T) {
+	rlCfg := NewDefaultRateLimitSettings()
+	assert.NoError(t, rlCfg.Validate())
+
+	rlCfg.Enabled = true
+	assert.EqualError(t, rlCfg.Validate(),
+		"rate limit requires at least one of requests_per_second, items_per_second or bytes_per_second to be set")
+
+	rlCfg.ItemsPerSecond = 1000
+	assert.NoError(t, rlCfg.Validate())
+
+	rlCfg.BytesPerSecond = -1
+	assert.EqualError(t, rlCfg.Validate(), "rate limits must not be negative")
+
+	rlCfg.BytesPerSecond = 0
+	rlCfg.RequestsBurst = -1
+	assert.EqualError(t, rlCfg.Validate(), "rate limit bursts must not be negative")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	rlCfg.Enabled = false
+	assert.NoError(t, rlCfg.Validate())
+}
+
+func TestRateLimit_Reserve(t *testing.T) {
+	now := time.Now()
+	l := newRateLimit(nil, 10, 0, now)
+
+	// The burst defaults to the rate.
+	assert.Equal(t, time.Duration(0), l.reserve(10, now))
+	assert.Equal(t, 100*time.Millisecond, l.reserve(1, now))
+
+	// The tokens given back are available again.
+	l.release(1)
+	assert.Equal(t, time.Duration(0), l.reserve(0, now))
+
+	// The bucket is refilled at the rate, up to the burst.
+	assert.Equal(t, time.Duration(0), l.reserve(5, now.Add(500*time.Millisecond)))
+	assert.Equal(t, time.Duration(0), l.reserve(10, now.Add(time.Hour)))
+
+	// A request bigger than the burst waits for the tokens it takes above the burst.
+	assert.Equal(t, time.Second, l.reserve(20, now.Add(2*time.Hour)))
+}
+
+func TestRateLimitSender_Requests(t *testing.T) {
+	rls := newRateLimitSender(RateLimitSettings{Enabled: true, RequestsPerSecond: 20, RequestsBurst: 2})
+	next := &switchableSender{}
+	rls.setNextSender(next)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, rls.send(context.Background(), newMockRequest(1, nil)))
+	}
+	// The first two requests are sent right away, the third one waits for 1/20s.
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.Equal(t, int64(3), next.requests.Load())
+}
+
+func TestRateLimitSender_Items(t *testing.T) {
+	rls := newRateLimitSender(RateLimitSettings{Enabled: true, RequestsPerSecond: 1000, ItemsPerSecond: 100})
+	next := &switchableSender{}
+	rls.setNextSender(next)
+
+	start := time.Now()
+	require.NoError(t, rls.send(context.Background(), newMockRequest(100, nil)))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+	require.NoError(t, rls.send(context.Background(), newMockRequest(10, nil)))
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestRateLimitSender_WaitInterrupted(t *testing.T) {
+	rls := newRateLimitSender(RateLimitSettings{Enabled: true, ItemsPerSecond: 1})
+	next := &switchableSender{}
+	rls.setNextSender(next)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := rls.send(ctx, newMockRequest(100, nil))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, errRateLimited)
+
+	// The tokens of the cancelled request are given back.
+	require.NoError(t, rls.send(context.Background(), newMockRequest(1, nil)))
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- rls.send(context.Background(), newMockRequest(100, nil))
+	}()
+	require.NoError(t, rls.Shutdown(context.Background()))
+	assert.True(t, experr.IsShutdownErr(<-errCh))
+	assert.Equal(t, int64(1), next.requests.Load())
+}
+
+func TestTracesExporter_WithRateLimitBytes(t *testing.T) {
+	td := testdata.GenerateTraces(2)
+	size := (&ptrace.ProtoMarshaler{}).TracesSize(td)
+	sink := new(consumertest.TracesSink)
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopSettings(), &fakeTracesExporterConfig,
+		sink.ConsumeTraces, WithRateLimit(RateLimitSettings{Enabled: true, BytesPerSecond: float64(size) * 10}))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+
+	start := time.Now()
+	for i := 0; i < 11; i++ {
+		require.NoError(t, te.ConsumeTraces(context.Background(), td))
+	}
+	// The burst allows 10 requests per second, the 11th one waits for 1/10s.
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Equal(t, 22, sink.SpanCount())
+	assert.NoError(t, te.Shutdown(context.Background()))
+}
+
+func TestRateLimitSender_ForwardsErrors(t *testing.T) {
+	rls := newRateLimitSender(RateLimitSettings{Enabled: true, RequestsPerSecond: 1000})
+	next := &switchableSender{}
+	next.setErr(errors.New("failed"))
+	rls.setNextSender(next)
+	assert.EqualError(t, rls.send(context.Background(), newMockRequest(1, nil)), "failed")
+}
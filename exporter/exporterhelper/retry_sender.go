@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -43,12 +44,38 @@ func NewThrottleRetry(err error, delay time.Duration) error {
 	}
 }
 
+// retryErr is returned by the retrySender when it gives up on a request, it carries the number of attempts made.
+type retryErr struct {
+	err      error
+	attempts int64
+}
+
+func (r retryErr) Error() string {
+	return r.err.Error()
+}
+
+func (r retryErr) Unwrap() error {
+	return r.err
+}
+
+// attemptsFromErr returns the number of attempts made to send a request that failed with the given error.
+// If the request was not sent through the retrySender, it was attempted only once.
+func attemptsFromErr(err error) int64 {
+	var re retryErr
+	if errors.As(err, &re) {
+		return re.attempts
+	}
+	return 1
+}
+
 type retrySender struct {
 	baseRequestSender
 	traceAttribute attribute.KeyValue
 	cfg            configretry.BackOffConfig
 	stopCh         chan struct{}
 	logger         *zap.Logger
+	// retrying is the number of requests waiting for the backoff to expire before being retried.
+	retrying atomic.Int64
 }
 
 func newRetrySender(config configretry.BackOffConfig, set exporter.Settings) *retrySender {
@@ -93,14 +120,14 @@ func (rs *retrySender) send(ctx context.Context, req Request) error {
 
 		// Immediately drop data on permanent errors.
 		if consumererror.IsPermanent(err) {
-			return fmt.Errorf("not retryable error: %w", err)
+			return retryErr{err: fmt.Errorf("not retryable error: %w", err), attempts: retryNum + 1}
 		}
 
 		req = extractPartialRequest(req, err)
 
 		backoffDelay := expBackoff.NextBackOff()
 		if backoffDelay == backoff.Stop {
-			return fmt.Errorf("no more retries left: %w", err)
+			return retryErr{err: fmt.Errorf("no more retries left: %w", err), attempts: retryNum + 1}
 		}
 
 		throttleErr := throttleRetry{}
@@ -123,16 +150,27 @@ func (rs *retrySender) send(ctx context.Context, req Request) error {
 		retryNum++
 
 		// back-off, but get interrupted when shutting down or request is cancelled or timed out.
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("request is cancelled or timed out %w", err)
-		case <-rs.stopCh:
-			return experr.NewShutdownErr(err)
-		case <-time.After(backoffDelay):
+		if err = rs.backoff(ctx, backoffDelay, err); err != nil {
+			return retryErr{err: err, attempts: retryNum}
 		}
 	}
 }
 
+// backoff waits for the delay to expire before the request is retried. It returns an error wrapping the export error
+// if the wait is interrupted by the shutdown or the request context.
+func (rs *retrySender) backoff(ctx context.Context, delay time.Duration, err error) error {
+	rs.retrying.Add(1)
+	defer rs.retrying.Add(-1)
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("request is cancelled or timed out %w", err)
+	case <-rs.stopCh:
+		return experr.NewShutdownErr(err)
+	case <-time.After(delay):
+		return nil
+	}
+}
+
 // max returns the larger of x or y.
 func max(x, y time.Duration) time.Duration {
 	if x < y {
@@ -63,6 +63,11 @@ func (req *tracesRequest) ItemsCount() int {
 	return req.td.SpanCount()
 }
 
+// BytesSize returns the size of the request once proto-marshaled, used to size the queue in bytes.
+func (req *tracesRequest) BytesSize() int {
+	return tracesMarshaler.TracesSize(req.td)
+}
+
 type traceExporter struct {
 	*baseExporter
 	consumer.Traces
@@ -25,9 +25,9 @@ func mergeTraces(_ context.Context, r1 Request, r2 Request) (Request, error) {
 // mergeSplitTraces splits and/or merges the traces into multiple requests based on the MaxSizeConfig.
 func mergeSplitTraces(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Request, r2 Request) ([]Request, error) {
 	var (
-		res          []Request
-		destReq      *tracesRequest
-		capacityLeft = cfg.MaxSizeItems
+		res      []Request
+		destReq  *tracesRequest
+		capacity = newBatchCapacity(cfg)
 	)
 	for _, req := range []Request{r1, r2} {
 		if req == nil {
@@ -37,33 +37,35 @@ func mergeSplitTraces(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 R
 		if !ok {
 			return nil, errors.New("invalid input type")
 		}
-		if srcReq.td.SpanCount() <= capacityLeft {
+		if capacity.addAll(srcReq.td.SpanCount(), func() int { return tracesMarshaler.TracesSize(srcReq.td) }) {
 			if destReq == nil {
 				destReq = srcReq
 			} else {
 				srcReq.td.ResourceSpans().MoveAndAppendTo(destReq.td.ResourceSpans())
 			}
-			capacityLeft -= destReq.td.SpanCount()
 			continue
 		}
 
-		for {
-			extractedTraces := extractTraces(srcReq.td, capacityLeft)
+		for srcReq.td.SpanCount() > 0 {
+			extractedTraces := extractTraces(srcReq.td, &capacity)
 			if extractedTraces.SpanCount() == 0 {
-				break
+				if destReq == nil {
+					// The next span doesn't fit even in an empty batch, it's sent alone.
+					single := batchCapacity{items: 1}
+					res = append(res, &tracesRequest{td: extractTraces(srcReq.td, &single), pusher: srcReq.pusher})
+					continue
+				}
+				// Create new batch once capacity is reached.
+				res = append(res, destReq)
+				destReq = nil
+				capacity = newBatchCapacity(cfg)
+				continue
 			}
-			capacityLeft -= extractedTraces.SpanCount()
 			if destReq == nil {
 				destReq = &tracesRequest{td: extractedTraces, pusher: srcReq.pusher}
 			} else {
 				extractedTraces.ResourceSpans().MoveAndAppendTo(destReq.td.ResourceSpans())
 			}
-			// Create new batch once capacity is reached.
-			if capacityLeft == 0 {
-				res = append(res, destReq)
-				destReq = nil
-				capacityLeft = cfg.MaxSizeItems
-			}
 		}
 	}
 
@@ -73,58 +75,68 @@ func mergeSplitTraces(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 R
 	return res, nil
 }
 
-// extractTraces extracts a new traces with a maximum number of spans.
-func extractTraces(srcTraces ptrace.Traces, count int) ptrace.Traces {
+// extractTraces extracts a new traces with the spans fitting in the capacity, and takes their room from it.
+func extractTraces(srcTraces ptrace.Traces, capacity *batchCapacity) ptrace.Traces {
 	destTraces := ptrace.NewTraces()
+	full := false
 	srcTraces.ResourceSpans().RemoveIf(func(srcRS ptrace.ResourceSpans) bool {
-		if count == 0 {
+		if full {
 			return false
 		}
-		needToExtract := resourceTracesCount(srcRS) > count
-		if needToExtract {
-			srcRS = extractResourceSpans(srcRS, count)
+		if capacity.add(resourceTracesCount(srcRS), func() int { return tracesMarshaler.ResourceSpansSize(srcRS) }) {
+			srcRS.MoveTo(destTraces.ResourceSpans().AppendEmpty())
+			return true
 		}
-		count -= resourceTracesCount(srcRS)
-		srcRS.MoveTo(destTraces.ResourceSpans().AppendEmpty())
-		return !needToExtract
+		full = true
+		if destRS := extractResourceSpans(srcRS, capacity); resourceTracesCount(destRS) > 0 {
+			destRS.MoveTo(destTraces.ResourceSpans().AppendEmpty())
+		}
+		return false
 	})
 	return destTraces
 }
 
-// extractResourceSpans extracts spans and returns a new resource spans with the specified number of spans.
-func extractResourceSpans(srcRS ptrace.ResourceSpans, count int) ptrace.ResourceSpans {
+// extractResourceSpans extracts spans and returns a new resource spans with the spans fitting in the capacity.
+func extractResourceSpans(srcRS ptrace.ResourceSpans, capacity *batchCapacity) ptrace.ResourceSpans {
 	destRS := ptrace.NewResourceSpans()
 	destRS.SetSchemaUrl(srcRS.SchemaUrl())
 	srcRS.Resource().CopyTo(destRS.Resource())
+	nested := capacity.nested(func() int { return tracesMarshaler.ResourceSpansSize(destRS) })
+	full := false
 	srcRS.ScopeSpans().RemoveIf(func(srcSS ptrace.ScopeSpans) bool {
-		if count == 0 {
+		if full {
 			return false
 		}
-		needToExtract := srcSS.Spans().Len() > count
-		if needToExtract {
-			srcSS = extractScopeSpans(srcSS, count)
+		if nested.add(srcSS.Spans().Len(), func() int { return tracesMarshaler.ScopeSpansSize(srcSS) }) {
+			srcSS.MoveTo(destRS.ScopeSpans().AppendEmpty())
+			return true
+		}
+		full = true
+		if destSS := extractScopeSpans(srcSS, &nested); destSS.Spans().Len() > 0 {
+			destSS.MoveTo(destRS.ScopeSpans().AppendEmpty())
 		}
-		count -= srcSS.Spans().Len()
-		srcSS.MoveTo(destRS.ScopeSpans().AppendEmpty())
-		return !needToExtract
+		return false
 	})
-	srcRS.Resource().CopyTo(destRS.Resource())
+	capacity.takeNested(nested, func() int { return tracesMarshaler.ResourceSpansSize(destRS) })
 	return destRS
 }
 
-// extractScopeSpans extracts spans and returns a new scope spans with the specified number of spans.
-func extractScopeSpans(srcSS ptrace.ScopeSpans, count int) ptrace.ScopeSpans {
+// extractScopeSpans extracts spans and returns a new scope spans with the spans fitting in the capacity.
+func extractScopeSpans(srcSS ptrace.ScopeSpans, capacity *batchCapacity) ptrace.ScopeSpans {
 	destSS := ptrace.NewScopeSpans()
 	destSS.SetSchemaUrl(srcSS.SchemaUrl())
 	srcSS.Scope().CopyTo(destSS.Scope())
+	nested := capacity.nested(func() int { return tracesMarshaler.ScopeSpansSize(destSS) })
+	full := false
 	srcSS.Spans().RemoveIf(func(srcSpan ptrace.Span) bool {
-		if count == 0 {
+		if full || !nested.add(1, func() int { return tracesMarshaler.SpanSize(srcSpan) }) {
+			full = true
 			return false
 		}
 		srcSpan.MoveTo(destSS.Spans().AppendEmpty())
-		count--
 		return true
 	})
+	capacity.takeNested(nested, func() int { return tracesMarshaler.ScopeSpansSize(destSS) })
 	return destSS
 }
 
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -142,6 +143,47 @@ func TestMergeSplitTraces(t *testing.T) {
 	}
 }
 
+func TestMergeSplitTracesMaxSizeBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  exporterbatcher.MaxSizeConfig
+	}{
+		{
+			name: "bytes_only",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 1000},
+		},
+		{
+			name: "bytes_and_items",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 3000, MaxSizeItems: 5},
+		},
+		{
+			name: "span_bigger_than_limit",
+			cfg:  exporterbatcher.MaxSizeConfig{MaxSizeBytes: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r1 := &tracesRequest{td: testdata.GenerateTraces(3)}
+			r2 := &tracesRequest{td: testdata.GenerateTraces(50)}
+			total := r1.td.SpanCount() + r2.td.SpanCount()
+			res, err := mergeSplitTraces(context.Background(), tt.cfg, r1, r2)
+			require.NoError(t, err)
+			count := 0
+			for _, r := range res {
+				td := r.(*tracesRequest).td
+				count += td.SpanCount()
+				if td.SpanCount() > 1 {
+					assert.LessOrEqual(t, tracesMarshaler.TracesSize(td), tt.cfg.MaxSizeBytes)
+				}
+				if tt.cfg.MaxSizeItems > 0 {
+					assert.LessOrEqual(t, td.SpanCount(), tt.cfg.MaxSizeItems)
+				}
+			}
+			assert.Equal(t, total, count)
+		})
+	}
+}
+
 func TestMergeSplitTracesInvalidInput(t *testing.T) {
 	r1 := &tracesRequest{td: testdata.GenerateTraces(2)}
 	r2 := &metricsRequest{md: testdata.GenerateMetrics(3)}
@@ -152,7 +194,7 @@ func TestMergeSplitTracesInvalidInput(t *testing.T) {
 func TestExtractTraces(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		td := testdata.GenerateTraces(10)
-		extractedTraces := extractTraces(td, i)
+		extractedTraces := extractTraces(td, &batchCapacity{items: i})
 		assert.Equal(t, i, extractedTraces.SpanCount())
 		assert.Equal(t, 10-i, td.SpanCount())
 	}
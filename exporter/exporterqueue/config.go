@@ -5,8 +5,10 @@ package exporterqueue // import "go.opentelemetry.io/collector/exporter/exporter
 
 import (
 	"errors"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
 )
 
 // Config defines configuration for queueing requests before exporting.
@@ -20,6 +22,28 @@ type Config struct {
 	NumConsumers int `mapstructure:"num_consumers"`
 	// QueueSize is the maximum number of requests allowed in queue at any given time.
 	QueueSize int `mapstructure:"queue_size"`
+	// QueueSizeBytes is the maximum total size, in bytes, of the requests allowed in queue at any given time.
+	// When set, it takes precedence over QueueSize and the queue is bounded by the serialized size of the requests.
+	// The requests must implement the `BytesSize() int` method, as the requests of the exporters created with
+	// New[Traces|Metrics|Logs]Exporter do with their proto-marshaled size. The requests which don't implement it
+	// are rejected when they are sent.
+	QueueSizeBytes int64 `mapstructure:"queue_size_bytes"`
+	// AdaptiveConcurrency configures the number of requests exported concurrently to be adjusted based on the
+	// observed latency and errors, starting at NumConsumers.
+	AdaptiveConcurrency AdaptiveConcurrencyConfig `mapstructure:"adaptive_concurrency"`
+	// MetadataKeys is the list of client.Metadata keys stored in the persistent storage along with the requests,
+	// and restored in the context the requests are exported with. The memory queue keeps the whole context,
+	// so it's only used by the persistent queue. The client.Info auth data is never stored.
+	MetadataKeys []string `mapstructure:"metadata_keys"`
+	// Compression is the algorithm the requests are compressed with before being written to the persistent storage.
+	// The algorithm is stored with every request, so it can be changed without losing the requests already stored.
+	// It's only used by the persistent queue.
+	Compression configcompression.Type `mapstructure:"compression"`
+	// ShutdownDrainTimeout is the maximum duration the exporter keeps sending the queued requests on shutdown,
+	// with the retries still enabled, until the queue is empty. Once it expires, the requests left are kept in the
+	// storage by the persistent queue, or dropped by the memory queue. If zero, the queue isn't drained with retries:
+	// the memory queue tries every request left once, and the persistent queue keeps them.
+	ShutdownDrainTimeout time.Duration `mapstructure:"shutdown_drain_timeout"`
 }
 
 // NewDefaultConfig returns the default Config.
@@ -41,9 +65,43 @@ func (qCfg *Config) Validate() error {
 	if qCfg.NumConsumers <= 0 {
 		return errors.New("number of consumers must be positive")
 	}
-	if qCfg.QueueSize <= 0 {
+	if qCfg.QueueSizeBytes < 0 {
+		return errors.New("queue size in bytes must not be negative")
+	}
+	if qCfg.QueueSizeBytes == 0 && qCfg.QueueSize <= 0 {
 		return errors.New("queue size must be positive")
 	}
+	if qCfg.ShutdownDrainTimeout < 0 {
+		return errors.New("shutdown drain timeout must not be negative")
+	}
+	return qCfg.AdaptiveConcurrency.Validate()
+}
+
+// AdaptiveConcurrencyConfig defines configuration for adjusting the number of requests exported concurrently from
+// the queue. The limit grows while the exports succeed with a stable latency, and shrinks when the latency increases
+// or the exports fail with retryable errors, e.g. because the destination is throttling.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type AdaptiveConcurrencyConfig struct {
+	// Enabled indicates whether to adjust the number of requests exported concurrently.
+	Enabled bool `mapstructure:"enabled"`
+	// MinConsumers is the lower bound of the number of requests exported concurrently.
+	MinConsumers int `mapstructure:"min_consumers"`
+	// MaxConsumers is the upper bound of the number of requests exported concurrently.
+	MaxConsumers int `mapstructure:"max_consumers"`
+}
+
+// Validate checks if the AdaptiveConcurrencyConfig configuration is valid
+func (acCfg *AdaptiveConcurrencyConfig) Validate() error {
+	if !acCfg.Enabled {
+		return nil
+	}
+	if acCfg.MinConsumers <= 0 {
+		return errors.New("adaptive concurrency min_consumers must be positive")
+	}
+	if acCfg.MaxConsumers < acCfg.MinConsumers {
+		return errors.New("adaptive concurrency max_consumers must not be lower than min_consumers")
+	}
 	return nil
 }
 
@@ -58,4 +116,39 @@ type PersistentQueueConfig struct {
 	// StorageID if not empty, enables the persistent storage and uses the component specified
 	// as a storage extension for the persistent queue
 	StorageID *component.ID `mapstructure:"storage"`
+	// SpillOver configures the queue to keep the requests in memory and to use the storage only as an overflow.
+	SpillOver SpillOverConfig `mapstructure:"spill_over"`
+	// Encryption configures the requests to be encrypted in the storage.
+	// It must be passed to NewEncryptedPersistentQueueSettings.
+	Encryption EncryptionConfig `mapstructure:"encryption"`
+}
+
+// SpillOverConfig defines configuration for a queue keeping requests in memory and spilling them over to the
+// persistent storage when the memory is full or when the exports have been failing for a while.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type SpillOverConfig struct {
+	// Enabled indicates whether to keep requests in memory before spilling them over to the storage.
+	// It has no effect if no storage is configured.
+	Enabled bool `mapstructure:"enabled"`
+	// MemoryQueueSize is the maximum size of the requests kept in memory, measured in the same unit as the queue size:
+	// number of requests, or bytes if QueueSizeBytes is set. The queue size applies to the storage only.
+	MemoryQueueSize int64 `mapstructure:"memory_queue_size"`
+	// SpillAfter is the duration the exports must have been failing continuously before new requests are written
+	// directly to the storage. If zero, the requests are spilled over only when the memory is full.
+	SpillAfter time.Duration `mapstructure:"spill_after"`
+}
+
+// Validate checks if the SpillOverConfig configuration is valid
+func (soCfg *SpillOverConfig) Validate() error {
+	if !soCfg.Enabled {
+		return nil
+	}
+	if soCfg.MemoryQueueSize <= 0 {
+		return errors.New("spill over memory queue size must be positive")
+	}
+	if soCfg.SpillAfter < 0 {
+		return errors.New("spill over spill_after must not be negative")
+	}
+	return nil
 }
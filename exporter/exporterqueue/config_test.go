@@ -5,6 +5,7 @@ package exporterqueue
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -20,7 +21,55 @@ func TestQueueConfig_Validate(t *testing.T) {
 	qCfg.QueueSize = 0
 	assert.EqualError(t, qCfg.Validate(), "queue size must be positive")
 
+	// The queue size is not required when the queue is sized in bytes.
+	qCfg.QueueSizeBytes = 1024
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.QueueSizeBytes = -1
+	assert.EqualError(t, qCfg.Validate(), "queue size in bytes must not be negative")
+
+	qCfg = NewDefaultConfig()
+	qCfg.ShutdownDrainTimeout = -time.Second
+	assert.EqualError(t, qCfg.Validate(), "shutdown drain timeout must not be negative")
+
+	qCfg = NewDefaultConfig()
+	qCfg.AdaptiveConcurrency = AdaptiveConcurrencyConfig{Enabled: true}
+	assert.EqualError(t, qCfg.Validate(), "adaptive concurrency min_consumers must be positive")
+
 	// Confirm Validate doesn't return error with invalid config when feature is disabled
 	qCfg.Enabled = false
 	assert.NoError(t, qCfg.Validate())
 }
+
+func TestSpillOverConfig_Validate(t *testing.T) {
+	soCfg := SpillOverConfig{Enabled: true, MemoryQueueSize: 100, SpillAfter: time.Minute}
+	assert.NoError(t, soCfg.Validate())
+
+	soCfg.SpillAfter = -time.Second
+	assert.EqualError(t, soCfg.Validate(), "spill over spill_after must not be negative")
+
+	soCfg.SpillAfter = 0
+	assert.NoError(t, soCfg.Validate())
+
+	soCfg.MemoryQueueSize = 0
+	assert.EqualError(t, soCfg.Validate(), "spill over memory queue size must be positive")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	soCfg.Enabled = false
+	assert.NoError(t, soCfg.Validate())
+}
+
+func TestAdaptiveConcurrencyConfig_Validate(t *testing.T) {
+	acCfg := AdaptiveConcurrencyConfig{Enabled: true, MinConsumers: 1, MaxConsumers: 10}
+	assert.NoError(t, acCfg.Validate())
+
+	acCfg.MaxConsumers = 0
+	assert.EqualError(t, acCfg.Validate(), "adaptive concurrency max_consumers must not be lower than min_consumers")
+
+	acCfg.MinConsumers = 0
+	assert.EqualError(t, acCfg.Validate(), "adaptive concurrency min_consumers must be positive")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	acCfg.Enabled = false
+	assert.NoError(t, acCfg.Validate())
+}
@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterqueue // import "go.opentelemetry.io/collector/exporter/exporterqueue"
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
+)
+
+// EncryptionConfig defines configuration for encrypting the requests written to the persistent storage with AES-GCM.
+// Every request is stored with the ID of the key it's encrypted with, so the key can be rotated by adding a new key,
+// making it the active one, and removing the previous key once the requests encrypted with it are exported.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type EncryptionConfig struct {
+	// Enabled indicates whether to encrypt the requests written to the persistent storage.
+	Enabled bool `mapstructure:"enabled"`
+	// Keys are the keys the requests read from the storage can be decrypted with.
+	Keys []EncryptionKeyConfig `mapstructure:"keys"`
+	// ActiveKey is the ID of the key the requests written to the storage are encrypted with.
+	// Defaults to the ID of the first key.
+	ActiveKey string `mapstructure:"active_key"`
+}
+
+// EncryptionKeyConfig defines an AES key, base64-encoded, either inline or in a file.
+// The decoded key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type EncryptionKeyConfig struct {
+	// ID identifies the key in the header of the requests encrypted with it.
+	ID string `mapstructure:"id"`
+	// Key is the base64-encoded key.
+	Key configopaque.String `mapstructure:"key"`
+	// KeyFile is the path to a file holding the base64-encoded key.
+	KeyFile string `mapstructure:"key_file"`
+}
+
+// Validate checks if the EncryptionConfig configuration is valid
+func (eCfg *EncryptionConfig) Validate() error {
+	if !eCfg.Enabled {
+		return nil
+	}
+	if len(eCfg.Keys) == 0 {
+		return errors.New("encryption requires at least one key")
+	}
+	ids := make(map[string]struct{}, len(eCfg.Keys))
+	for _, k := range eCfg.Keys {
+		if k.ID == "" {
+			return errors.New("encryption key id must not be empty")
+		}
+		if len(k.ID) > 255 {
+			return fmt.Errorf("encryption key id %q must not be longer than 255 bytes", k.ID)
+		}
+		if _, ok := ids[k.ID]; ok {
+			return fmt.Errorf("duplicate encryption key id %q", k.ID)
+		}
+		ids[k.ID] = struct{}{}
+		if (k.Key == "") == (k.KeyFile == "") {
+			return fmt.Errorf("encryption key %q requires exactly one of key or key_file to be set", k.ID)
+		}
+	}
+	if _, ok := ids[eCfg.ActiveKey]; eCfg.ActiveKey != "" && !ok {
+		return fmt.Errorf("encryption active_key %q is not one of the keys", eCfg.ActiveKey)
+	}
+	return nil
+}
+
+// load returns the decoded key.
+func (kCfg *EncryptionKeyConfig) load() ([]byte, error) {
+	encoded := string(kCfg.Key)
+	if kCfg.KeyFile != "" {
+		buf, err := os.ReadFile(kCfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key %q: %w", kCfg.ID, err)
+		}
+		encoded = strings.TrimSpace(string(buf))
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption key %q: %w", kCfg.ID, err)
+	}
+	return key, nil
+}
+
+// NewEncryptedPersistentQueueSettings returns a copy of the settings making the persistent queue encrypt the requests
+// with the active key, once marshaled and compressed, and decrypt them with the key they were encrypted with.
+// The settings are returned as is if the encryption is disabled. The keys are read when called, so the errors are
+// reported at startup.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func NewEncryptedPersistentQueueSettings[T any](cfg EncryptionConfig, set PersistentQueueSettings[T]) (PersistentQueueSettings[T], error) {
+	if !cfg.Enabled {
+		return set, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return set, err
+	}
+	keys := make(map[string][]byte, len(cfg.Keys))
+	for i := range cfg.Keys {
+		key, err := cfg.Keys[i].load()
+		if err != nil {
+			return set, err
+		}
+		keys[cfg.Keys[i].ID] = key
+	}
+	activeID := cfg.ActiveKey
+	if activeID == "" {
+		activeID = cfg.Keys[0].ID
+	}
+	enc, err := queue.NewEncryptor(keys, activeID)
+	if err != nil {
+		return set, err
+	}
+	set.encryptor = enc
+	return set, nil
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterqueue
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
+)
+
+func TestEncryptionConfig_Validate(t *testing.T) {
+	eCfg := EncryptionConfig{}
+	assert.NoError(t, eCfg.Validate())
+
+	eCfg.Enabled = true
+	assert.EqualError(t, eCfg.Validate(), "encryption requires at least one key")
+
+	eCfg.Keys = []EncryptionKeyConfig{{Key: "a2V5"}}
+	assert.EqualError(t, eCfg.Validate(), "encryption key id must not be empty")
+
+	eCfg.Keys = []EncryptionKeyConfig{{ID: strings.Repeat("k", 256), Key: "a2V5"}}
+	assert.EqualError(t, eCfg.Validate(), `encryption key id "`+strings.Repeat("k", 256)+`" must not be longer than 255 bytes`)
+
+	eCfg.Keys = []EncryptionKeyConfig{{ID: "k1"}}
+	assert.EqualError(t, eCfg.Validate(), `encryption key "k1" requires exactly one of key or key_file to be set`)
+
+	eCfg.Keys = []EncryptionKeyConfig{{ID: "k1", Key: "a2V5", KeyFile: "key"}}
+	assert.EqualError(t, eCfg.Validate(), `encryption key "k1" requires exactly one of key or key_file to be set`)
+
+	eCfg.Keys = []EncryptionKeyConfig{{ID: "k1", Key: "a2V5"}, {ID: "k1", KeyFile: "key"}}
+	assert.EqualError(t, eCfg.Validate(), `duplicate encryption key id "k1"`)
+
+	eCfg.Keys[1].ID = "k2"
+	assert.NoError(t, eCfg.Validate())
+
+	eCfg.ActiveKey = "k3"
+	assert.EqualError(t, eCfg.Validate(), `encryption active_key "k3" is not one of the keys`)
+
+	eCfg.ActiveKey = "k2"
+	assert.NoError(t, eCfg.Validate())
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	eCfg.Enabled = false
+	eCfg.Keys = nil
+	assert.NoError(t, eCfg.Validate())
+}
+
+type stringRequest string
+
+func (stringRequest) ItemsCount() int {
+	return 1
+}
+
+type mockHost struct {
+	component.Host
+	ext map[component.ID]component.Component
+}
+
+func (nh *mockHost) GetExtensions() map[component.ID]component.Component {
+	return nh.ext
+}
+
+func TestNewEncryptedPersistentQueueSettings(t *testing.T) {
+	set := PersistentQueueSettings[stringRequest]{
+		Marshaler:   func(s stringRequest) ([]byte, error) { return []byte(s), nil },
+		Unmarshaler: func(buf []byte) (stringRequest, error) { return stringRequest(buf), nil },
+	}
+	storageID := component.MustNewID("file_storage")
+	host := &mockHost{ext: map[component.ID]component.Component{storageID: queue.NewMockStorageExtension(nil)}}
+	restart := func(eCfg EncryptionConfig) Queue[stringRequest] {
+		encSet, err := NewEncryptedPersistentQueueSettings(eCfg, set)
+		require.NoError(t, err)
+		q := NewPersistentQueueFactory[stringRequest](&storageID, encSet)(context.Background(),
+			Settings{DataType: component.DataTypeTraces, ExporterSettings: exportertest.NewNopSettings()}, NewDefaultConfig())
+		require.NoError(t, q.Start(context.Background(), host))
+		return q
+	}
+	consume := func(q Queue[stringRequest]) stringRequest {
+		var got stringRequest
+		require.True(t, q.Consume(func(_ context.Context, req stringRequest) error {
+			got = req
+			return nil
+		}))
+		return got
+	}
+
+	key1 := EncryptionKeyConfig{ID: "k1", Key: configopaque.String(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", 32))))}
+	key2 := EncryptionKeyConfig{ID: "k2", KeyFile: filepath.Join(t.TempDir(), "key")}
+	require.NoError(t, os.WriteFile(key2.KeyFile, []byte(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("2", 16)))+"\n"), 0600))
+
+	q := restart(EncryptionConfig{Enabled: true, Keys: []EncryptionKeyConfig{key1}})
+	require.NoError(t, q.Offer(context.Background(), "old telemetry"))
+	require.NoError(t, q.Shutdown(context.Background()))
+
+	// The key is rotated, the requests encrypted with the previous key can still be read.
+	q = restart(EncryptionConfig{Enabled: true, Keys: []EncryptionKeyConfig{key1, key2}, ActiveKey: "k2"})
+	assert.Equal(t, stringRequest("old telemetry"), consume(q))
+	require.NoError(t, q.Offer(context.Background(), "new telemetry"))
+	require.NoError(t, q.Shutdown(context.Background()))
+
+	q = restart(EncryptionConfig{Enabled: true, Keys: []EncryptionKeyConfig{key2}})
+	assert.Equal(t, stringRequest("new telemetry"), consume(q))
+	require.NoError(t, q.Shutdown(context.Background()))
+
+	// The settings are returned as is if the encryption is disabled.
+	plainSet, err := NewEncryptedPersistentQueueSettings(EncryptionConfig{}, set)
+	require.NoError(t, err)
+	assert.Nil(t, plainSet.encryptor)
+}
+
+func TestNewEncryptedPersistentQueueSettings_Errors(t *testing.T) {
+	set := PersistentQueueSettings[stringRequest]{}
+
+	_, err := NewEncryptedPersistentQueueSettings(EncryptionConfig{Enabled: true}, set)
+	assert.EqualError(t, err, "encryption requires at least one key")
+
+	_, err = NewEncryptedPersistentQueueSettings(EncryptionConfig{
+		Enabled: true,
+		Keys:    []EncryptionKeyConfig{{ID: "k1", KeyFile: filepath.Join(t.TempDir(), "missing")}},
+	}, set)
+	assert.ErrorContains(t, err, `failed to read encryption key "k1"`)
+
+	_, err = NewEncryptedPersistentQueueSettings(EncryptionConfig{
+		Enabled: true,
+		Keys:    []EncryptionKeyConfig{{ID: "k1", Key: "not base64!"}},
+	}, set)
+	assert.ErrorContains(t, err, `failed to decode encryption key "k1"`)
+
+	_, err = NewEncryptedPersistentQueueSettings(EncryptionConfig{
+		Enabled: true,
+		Keys:    []EncryptionKeyConfig{{ID: "k1", Key: configopaque.String(base64.StdEncoding.EncodeToString([]byte("short")))}},
+	}, set)
+	assert.ErrorContains(t, err, `invalid encryption key "k1"`)
+}
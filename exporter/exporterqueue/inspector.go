@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterqueue // import "go.opentelemetry.io/collector/exporter/exporterqueue"
+
+import (
+	"context"
+	"time"
+)
+
+// Status is a snapshot of the state of the sending queue of an exporter, and of the requests taken from it.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type Status struct {
+	// Kind is the kind of the queue: "memory", "persistent", "spill_over", or "custom".
+	Kind string
+	// Size is the current size of the queue, measured in Unit.
+	Size int
+	// Capacity is the capacity of the queue, measured in Unit.
+	Capacity int
+	// Unit is the unit the queue is sized in: "requests" or "bytes".
+	Unit string
+	// OldestItemAge is the time the oldest request has been waiting in the queue for,
+	// or zero if the queue is empty or the queue doesn't report it.
+	OldestItemAge time.Duration
+	// Consumers is the number of consumers of the queue.
+	Consumers int
+	// Paused indicates whether the consumers are paused.
+	Paused bool
+	// RetryingRequests is the number of requests taken from the queue that are waiting to be retried.
+	RetryingRequests int64
+	// CircuitBreakerState is the state of the circuit breaker: "closed", "half-open", "open",
+	// or empty if the circuit breaker is not enabled.
+	CircuitBreakerState string
+}
+
+// Inspector is implemented by the exporters created with the exporterhelper, to let the sending queue be inspected
+// and administered at runtime, e.g. while handling an incident.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type Inspector interface {
+	// QueueStatus returns the status of the sending queue, or false if the sending queue is not enabled.
+	QueueStatus() (Status, bool)
+	// PauseQueue stops the consumers from taking the requests from the queue until ResumeQueue is called.
+	// The requests keep being queued in the meantime.
+	PauseQueue() error
+	// ResumeQueue lets the paused consumers take the requests from the queue again.
+	ResumeQueue() error
+	// PurgeQueue drops all the requests waiting in the queue, and returns how many were dropped.
+	// The requests already taken from the queue are not affected.
+	PurgeQueue(ctx context.Context) (int, error)
+}
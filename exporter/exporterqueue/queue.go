@@ -63,6 +63,9 @@ type PersistentQueueSettings[T any] struct {
 	Marshaler Marshaler[T]
 	// Unmarshaler is used to deserialize requests after reading them from the persistent storage.
 	Unmarshaler Unmarshaler[T]
+
+	// encryptor is set by NewEncryptedPersistentQueueSettings.
+	encryptor *queue.Encryptor
 }
 
 // NewPersistentQueueFactory returns a factory to create a new persistent queue.
@@ -82,6 +85,40 @@ func NewPersistentQueueFactory[T itemsCounter](storageID *component.ID, factoryS
 			Marshaler:        factorySettings.Marshaler,
 			Unmarshaler:      factorySettings.Unmarshaler,
 			ExporterSettings: set.ExporterSettings,
+			MetadataKeys:     cfg.MetadataKeys,
+			Compression:      cfg.Compression,
+			Encryptor:        factorySettings.encryptor,
+		})
+	}
+}
+
+// NewSpillOverQueueFactory returns a factory to create a new queue keeping the requests in memory, and spilling them
+// over to the persistent storage when the memory is full or when the exports have been failing for longer than
+// spillOverCfg.SpillAfter. The requests still in memory on shutdown are written to the storage.
+// If spill over is disabled, it falls back to the persistent queue, and if storageID is nil to the memory queue.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func NewSpillOverQueueFactory[T itemsCounter](storageID *component.ID, spillOverCfg SpillOverConfig,
+	factorySettings PersistentQueueSettings[T]) Factory[T] {
+	if storageID == nil || !spillOverCfg.Enabled {
+		return NewPersistentQueueFactory[T](storageID, factorySettings)
+	}
+	return func(_ context.Context, set Settings, cfg Config) Queue[T] {
+		return queue.NewHybridQueue[T](queue.HybridQueueSettings[T]{
+			PersistentQueueSettings: queue.PersistentQueueSettings[T]{
+				Sizer:            sizerFromConfig[T](cfg),
+				Capacity:         capacityFromConfig(cfg),
+				DataType:         set.DataType,
+				StorageID:        *storageID,
+				Marshaler:        factorySettings.Marshaler,
+				Unmarshaler:      factorySettings.Unmarshaler,
+				ExporterSettings: set.ExporterSettings,
+				MetadataKeys:     cfg.MetadataKeys,
+				Compression:      cfg.Compression,
+				Encryptor:        factorySettings.encryptor,
+			},
+			MemoryCapacity: spillOverCfg.MemoryQueueSize,
+			SpillAfter:     spillOverCfg.SpillAfter,
 		})
 	}
 }
@@ -90,12 +127,16 @@ type itemsCounter interface {
 	ItemsCount() int
 }
 
-func sizerFromConfig[T itemsCounter](Config) queue.Sizer[T] {
-	// TODO: Handle other ways to measure the queue size once they are added.
+func sizerFromConfig[T itemsCounter](cfg Config) queue.Sizer[T] {
+	if cfg.QueueSizeBytes > 0 {
+		return &queue.BytesSizer[T]{}
+	}
 	return &queue.RequestSizer[T]{}
 }
 
 func capacityFromConfig(cfg Config) int64 {
-	// TODO: Handle other ways to measure the queue size once they are added.
+	if cfg.QueueSizeBytes > 0 {
+		return cfg.QueueSizeBytes
+	}
 	return int64(cfg.QueueSize)
 }
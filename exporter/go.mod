@@ -4,17 +4,25 @@ go 1.21.0
 
 require (
 	github.com/cenkalti/backoff/v4 v4.3.0
+	github.com/golang/snappy v0.0.4
 	github.com/google/uuid v1.6.0
+	github.com/klauspost/compress v1.17.9
 	github.com/stretchr/testify v1.9.0
 	go.opentelemetry.io/collector v0.106.1
+	go.opentelemetry.io/collector/client v0.106.1
 	go.opentelemetry.io/collector/component v0.106.1
+	go.opentelemetry.io/collector/component/componentprofiles v0.106.1
+	go.opentelemetry.io/collector/config/configcompression v1.12.0
+	go.opentelemetry.io/collector/config/configopaque v1.12.0
 	go.opentelemetry.io/collector/config/configretry v1.12.0
 	go.opentelemetry.io/collector/config/configtelemetry v0.106.1
 	go.opentelemetry.io/collector/consumer v0.106.1
 	go.opentelemetry.io/collector/consumer/consumerprofiles v0.106.1
 	go.opentelemetry.io/collector/consumer/consumertest v0.106.1
+	go.opentelemetry.io/collector/exporter/exporterprofiles v0.106.1
 	go.opentelemetry.io/collector/extension v0.106.1
 	go.opentelemetry.io/collector/pdata v1.12.0
+	go.opentelemetry.io/collector/pdata/pprofile v0.106.1
 	go.opentelemetry.io/collector/pdata/testdata v0.106.1
 	go.opentelemetry.io/collector/receiver v0.106.1
 	go.opentelemetry.io/otel v1.28.0
@@ -55,7 +63,6 @@ require (
 	go.opentelemetry.io/collector/confmap v0.106.1 // indirect
 	go.opentelemetry.io/collector/featuregate v1.12.0 // indirect
 	go.opentelemetry.io/collector/internal/globalgates v0.106.1 // indirect
-	go.opentelemetry.io/collector/pdata/pprofile v0.106.1 // indirect
 	go.opentelemetry.io/otel/exporters/prometheus v0.50.0 // indirect
 	golang.org/x/net v0.26.0 // indirect
 	golang.org/x/text v0.16.0 // indirect
@@ -95,3 +102,13 @@ replace go.opentelemetry.io/collector/config/configtelemetry => ../config/config
 replace go.opentelemetry.io/collector/consumer/consumerprofiles => ../consumer/consumerprofiles
 
 replace go.opentelemetry.io/collector/consumer/consumertest => ../consumer/consumertest
+
+replace go.opentelemetry.io/collector/client => ../client
+
+replace go.opentelemetry.io/collector/config/configopaque => ../config/configopaque
+
+replace go.opentelemetry.io/collector/config/configcompression => ../config/configcompression
+
+replace go.opentelemetry.io/collector/component/componentprofiles => ../component/componentprofiles
+
+replace go.opentelemetry.io/collector/exporter/exporterprofiles => ./exporterprofiles
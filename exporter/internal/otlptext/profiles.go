@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlptext // import "go.opentelemetry.io/collector/exporter/internal/otlptext"
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+)
+
+// NewTextProfilesMarshaler returns a pprofile.Marshaler to encode to OTLP text bytes.
+func NewTextProfilesMarshaler() pprofile.Marshaler {
+	return textProfilesMarshaler{}
+}
+
+type textProfilesMarshaler struct{}
+
+// MarshalProfiles pprofile.Profiles to OTLP text.
+func (textProfilesMarshaler) MarshalProfiles(pd pprofile.Profiles) ([]byte, error) {
+	buf := dataBuffer{}
+	rps := pd.ResourceProfiles()
+	for i := 0; i < rps.Len(); i++ {
+		buf.logEntry("ResourceProfiles #%d", i)
+		rp := rps.At(i)
+		buf.logEntry("Resource SchemaURL: %s", rp.SchemaUrl())
+		buf.logAttributes("Resource attributes", rp.Resource().Attributes())
+		sps := rp.ScopeProfiles()
+		for j := 0; j < sps.Len(); j++ {
+			buf.logEntry("ScopeProfiles #%d", j)
+			sp := sps.At(j)
+			buf.logEntry("ScopeProfiles SchemaURL: %s", sp.SchemaUrl())
+			buf.logInstrumentationScope(sp.Scope())
+
+			profiles := sp.Profiles()
+			for k := 0; k < profiles.Len(); k++ {
+				buf.logEntry("Profile #%d", k)
+				pc := profiles.At(k)
+				buf.logAttr("Profile ID", hex.EncodeToString(pc.ProfileID().AsRaw()))
+				buf.logAttr("Start time", pc.StartTime().String())
+				buf.logAttr("End time", pc.EndTime().String())
+				buf.logAttr("Dropped attrs", strconv.FormatUint(uint64(pc.DroppedAttributesCount()), 10))
+				buf.logProfile(pc.Profile())
+				buf.logAttributes("Attributes", pc.Attributes())
+				buf.logSamples(pc.Profile())
+			}
+		}
+	}
+
+	return buf.buf.Bytes(), nil
+}
+
+func (b *dataBuffer) logProfile(p pprofile.Profile) {
+	st := p.StringTable()
+	b.logAttr("Period type", valueTypeToString(st, p.PeriodType()))
+	b.logAttr("Period", strconv.FormatInt(p.Period(), 10))
+	b.logAttr("Sample types", strings.Join(sampleTypesToStrings(p), ", "))
+	for i := 0; i < p.Comment().Len(); i++ {
+		b.logAttr("Comment", stringAt(st, p.Comment().At(i)))
+	}
+}
+
+// logSamples logs the samples of the profile, resolving them into stack traces with the tables of the profile.
+func (b *dataBuffer) logSamples(p pprofile.Profile) {
+	st := p.StringTable()
+	sampleTypes := sampleTypesToStrings(p)
+	samples := p.Sample()
+	for i := 0; i < samples.Len(); i++ {
+		b.logEntry("Sample #%d", i)
+		s := samples.At(i)
+		b.logEntry("     -> Values: %s", sampleValuesToString(sampleTypes, s.Value()))
+		if s.Label().Len() > 0 {
+			b.logEntry("     -> Labels:")
+			for j := 0; j < s.Label().Len(); j++ {
+				l := s.Label().At(j)
+				if l.Str() != 0 {
+					b.logEntry("          -> %s: %s", stringAt(st, l.Key()), stringAt(st, l.Str()))
+				} else {
+					b.logEntry("          -> %s: %d %s", stringAt(st, l.Key()), l.Num(), stringAt(st, l.NumUnit()))
+				}
+			}
+		}
+		frames := sampleFrames(p, s)
+		if len(frames) > 0 {
+			b.logEntry("     -> Stack trace:")
+			for j, frame := range frames {
+				b.logEntry("          #%d %s", j, frame)
+			}
+		}
+	}
+}
+
+// sampleFrames returns the frames of the sample, the innermost first.
+func sampleFrames(p pprofile.Profile, s pprofile.Sample) []string {
+	var frames []string
+	for _, locIdx := range sampleLocationIndices(p, s) {
+		if locIdx >= uint64(p.Location().Len()) {
+			frames = append(frames, fmt.Sprintf("<invalid location %d>", locIdx))
+			continue
+		}
+		frames = append(frames, locationFrames(p, p.Location().At(int(locIdx)))...)
+	}
+	return frames
+}
+
+// sampleLocationIndices returns the indices in the location table of the locations of the sample.
+func sampleLocationIndices(p pprofile.Profile, s pprofile.Sample) []uint64 {
+	// The deprecated location index, if set, refers to the location table directly.
+	if s.LocationIndex().Len() > 0 {
+		return s.LocationIndex().AsRaw()
+	}
+	var indices []uint64
+	for i := s.LocationsStartIndex(); i < s.LocationsStartIndex()+s.LocationsLength(); i++ {
+		if i >= uint64(p.LocationIndices().Len()) {
+			break
+		}
+		indices = append(indices, uint64(p.LocationIndices().At(int(i))))
+	}
+	return indices
+}
+
+// locationFrames returns a frame for each of the lines of the location, several lines being inlined functions,
+// or a frame with the address of the location if it has no line.
+func locationFrames(p pprofile.Profile, loc pprofile.Location) []string {
+	st := p.StringTable()
+	var mapping string
+	if loc.MappingIndex() < uint64(p.Mapping().Len()) {
+		if filename := stringAt(st, p.Mapping().At(int(loc.MappingIndex())).Filename()); filename != "" {
+			mapping = " [" + filename + "]"
+		}
+	}
+	if loc.Line().Len() == 0 {
+		return []string{fmt.Sprintf("0x%x%s", loc.Address(), mapping)}
+	}
+	frames := make([]string, loc.Line().Len())
+	for i := 0; i < loc.Line().Len(); i++ {
+		line := loc.Line().At(i)
+		if line.FunctionIndex() >= uint64(p.Function().Len()) {
+			frames[i] = fmt.Sprintf("<invalid function %d>%s", line.FunctionIndex(), mapping)
+			continue
+		}
+		fn := p.Function().At(int(line.FunctionIndex()))
+		frames[i] = fmt.Sprintf("%s %s:%d%s", stringAt(st, fn.Name()), stringAt(st, fn.Filename()), line.Line(), mapping)
+	}
+	return frames
+}
+
+func sampleTypesToStrings(p pprofile.Profile) []string {
+	sampleTypes := make([]string, p.SampleType().Len())
+	for i := 0; i < p.SampleType().Len(); i++ {
+		sampleTypes[i] = valueTypeToString(p.StringTable(), p.SampleType().At(i))
+	}
+	return sampleTypes
+}
+
+// sampleValuesToString returns the values of the sample along with their type.
+func sampleValuesToString(sampleTypes []string, values pcommon.Int64Slice) string {
+	res := make([]string, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		if i < len(sampleTypes) {
+			res[i] = fmt.Sprintf("%s=%d", sampleTypes[i], values.At(i))
+		} else {
+			res[i] = strconv.FormatInt(values.At(i), 10)
+		}
+	}
+	return strings.Join(res, " ")
+}
+
+func valueTypeToString(st pcommon.StringSlice, vt pprofile.ValueType) string {
+	return stringAt(st, vt.Type()) + "/" + stringAt(st, vt.Unit())
+}
+
+// stringAt returns the string at the index of the string table, or an empty string if the index is out of range.
+func stringAt(st pcommon.StringSlice, idx int64) string {
+	if idx < 0 || idx >= int64(st.Len()) {
+		return ""
+	}
+	return st.At(int(idx))
+}
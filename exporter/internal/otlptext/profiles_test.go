@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlptext
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/testdata"
+)
+
+func TestProfilesText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   pprofile.Profiles
+		out  string
+	}{
+		{
+			name: "empty_profiles",
+			in:   pprofile.NewProfiles(),
+			out:  "empty.out",
+		},
+		{
+			name: "profiles_with_two_profiles",
+			in:   testdata.GenerateProfiles(2),
+			out:  "two_profiles.out",
+		},
+		{
+			name: "profiles_with_stack_traces",
+			in:   generateProfilesWithStackTraces(),
+			out:  "stack_traces.out",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTextProfilesMarshaler().MarshalProfiles(tt.in)
+			assert.NoError(t, err)
+			out, err := os.ReadFile(filepath.Join("testdata", "profiles", tt.out))
+			require.NoError(t, err)
+			expected := strings.ReplaceAll(string(out), "\r", "")
+			assert.Equal(t, expected, string(got))
+		})
+	}
+}
+
+func generateProfilesWithStackTraces() pprofile.Profiles {
+	pd := pprofile.NewProfiles()
+	rp := pd.ResourceProfiles().AppendEmpty()
+	rp.Resource().Attributes().PutStr("service.name", "checkout")
+	sp := rp.ScopeProfiles().AppendEmpty()
+	sp.Scope().SetName("profiler")
+	sp.Scope().SetVersion("1.0.0")
+	pc := sp.Profiles().AppendEmpty()
+	pc.ProfileID().FromRaw([]byte("profileA"))
+	pc.SetStartTime(pcommon.NewTimestampFromTime(time.Date(2020, 2, 11, 20, 26, 12, 0, time.UTC)))
+	pc.SetEndTime(pcommon.NewTimestampFromTime(time.Date(2020, 2, 11, 20, 26, 22, 0, time.UTC)))
+	pc.Attributes().PutStr("profile.kind", "cpu")
+
+	p := pc.Profile()
+	p.StringTable().FromRaw([]string{"", "samples", "count", "cpu", "nanoseconds", "main", "main.go",
+		"handle", "handler.go", "/usr/bin/checkout", "thread", "busy", "depth", "frames", "sampled by the profiler"})
+	p.PeriodType().SetType(3)
+	p.PeriodType().SetUnit(4)
+	p.SetPeriod(10000000)
+	p.Comment().FromRaw([]int64{14})
+	st := p.SampleType().AppendEmpty()
+	st.SetType(1)
+	st.SetUnit(2)
+	st = p.SampleType().AppendEmpty()
+	st.SetType(3)
+	st.SetUnit(4)
+
+	p.Mapping().AppendEmpty().SetFilename(9)
+	fn := p.Function().AppendEmpty()
+	fn.SetName(5)
+	fn.SetFilename(6)
+	fn = p.Function().AppendEmpty()
+	fn.SetName(7)
+	fn.SetFilename(8)
+
+	// The first location has the handle function inlined in main.
+	loc := p.Location().AppendEmpty()
+	loc.SetAddress(0x1000)
+	line := loc.Line().AppendEmpty()
+	line.SetFunctionIndex(1)
+	line.SetLine(12)
+	line = loc.Line().AppendEmpty()
+	line.SetFunctionIndex(0)
+	line.SetLine(42)
+	// The second location is not symbolized.
+	p.Location().AppendEmpty().SetAddress(0x2000)
+	p.LocationIndices().FromRaw([]int64{1, 0})
+
+	s := p.Sample().AppendEmpty()
+	s.Value().FromRaw([]int64{1, 10000000})
+	s.SetLocationsStartIndex(0)
+	s.SetLocationsLength(2)
+	l := s.Label().AppendEmpty()
+	l.SetKey(10)
+	l.SetStr(11)
+	l = s.Label().AppendEmpty()
+	l.SetKey(12)
+	l.SetNum(2)
+	l.SetNumUnit(13)
+
+	s = p.Sample().AppendEmpty()
+	s.Value().FromRaw([]int64{3, 30000000})
+	s.LocationIndex().FromRaw([]uint64{0, 5})
+	return pd
+}
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// latencyTolerance is the ratio between the latency of an export and the baseline latency above which
+	// the destination is considered overloaded.
+	latencyTolerance = 2.0
+	// backoffRatio is the factor applied to the limit when the destination is considered overloaded.
+	backoffRatio = 0.75
+	// baselineSmoothing is the weight of every new latency sample in the baseline latency.
+	baselineSmoothing = 0.05
+)
+
+// ExportResult describes what the result of an export tells about the state of the destination.
+type ExportResult int
+
+const (
+	// ExportIgnored is the result of an export that tells nothing about the destination, e.g. rejected data.
+	ExportIgnored ExportResult = iota
+	// ExportSucceeded is the result of a successful export. Its latency is compared to the baseline.
+	ExportSucceeded
+	// ExportOverloaded is the result of an export failing because the destination cannot keep up,
+	// e.g. when it's throttling or timing out.
+	ExportOverloaded
+)
+
+// AdaptiveLimiter limits the number of concurrent exports using an additive-increase/multiplicative-decrease
+// algorithm. The limit grows by one every time a full window of attempts succeeds with a latency close to the
+// baseline, and is reduced by backoffRatio when an attempt is overloaded or its latency goes above
+// latencyTolerance times the baseline. The limit always stays within the configured bounds.
+//
+// The exports are limited as a whole, retries included, while the limit is adjusted on the result of every attempt,
+// so that it reacts to the state of the destination without waiting for the retries to be exhausted.
+type AdaptiveLimiter struct {
+	minLimit float64
+	maxLimit float64
+
+	// mu guards everything declared below.
+	mu       sync.Mutex
+	notFull  *sync.Cond
+	limit    float64
+	inFlight int
+	// attempts is the number of attempts started so far. It's used to ignore the results of the attempts started
+	// before the last decrease, so the limit is decreased once for a burst of failing attempts.
+	attempts     uint64
+	lastDecrease uint64
+	// baseline is the moving average of the successful attempts latency in nanoseconds, zero until the first sample.
+	baseline float64
+}
+
+// NewAdaptiveLimiter creates a limiter starting at the initial limit, bounded by minLimit and maxLimit.
+func NewAdaptiveLimiter(initial, minLimit, maxLimit int) *AdaptiveLimiter {
+	l := &AdaptiveLimiter{
+		minLimit: float64(minLimit),
+		maxLimit: float64(maxLimit),
+		limit:    float64(min(max(initial, minLimit), maxLimit)),
+	}
+	l.notFull = sync.NewCond(&l.mu)
+	return l
+}
+
+// Acquire blocks until the number of exports in flight is below the limit. Release must be called once the export
+// is done.
+func (l *AdaptiveLimiter) Acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.inFlight >= int(l.limit) {
+		l.notFull.Wait()
+	}
+	l.inFlight++
+}
+
+// Release marks an export acquired with Acquire as done.
+func (l *AdaptiveLimiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	l.notFull.Broadcast()
+}
+
+// StartAttempt returns the token to pass to RecordAttempt once the attempt to export is done.
+func (l *AdaptiveLimiter) StartAttempt() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.attempts++
+	return l.attempts
+}
+
+// RecordAttempt adjusts the limit based on the result of the attempt started with the given token.
+func (l *AdaptiveLimiter) RecordAttempt(token uint64, res ExportResult, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// Increasing the limit only makes sense if the exports are actually limited by it.
+	saturated := l.inFlight >= int(l.limit)
+
+	switch res {
+	case ExportSucceeded:
+		sample := float64(latency)
+		if l.baseline == 0 {
+			l.baseline = sample
+		}
+		switch {
+		case sample > l.baseline*latencyTolerance:
+			l.decrease(token)
+		case saturated:
+			l.limit = min(l.limit+1/l.limit, l.maxLimit)
+			l.notFull.Broadcast()
+		}
+		l.baseline += (sample - l.baseline) * baselineSmoothing
+	case ExportOverloaded:
+		l.decrease(token)
+	}
+}
+
+func (l *AdaptiveLimiter) decrease(token uint64) {
+	if token <= l.lastDecrease {
+		return
+	}
+	l.limit = max(l.limit*backoffRatio, l.minLimit)
+	l.lastDecrease = l.attempts
+}
+
+// Limit returns the current limit of concurrent exports.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
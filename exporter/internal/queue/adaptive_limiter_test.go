@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func TestAdaptiveLimiter_InitialLimitBounded(t *testing.T) {
+	assert.Equal(t, 5, NewAdaptiveLimiter(5, 1, 10).Limit())
+	assert.Equal(t, 2, NewAdaptiveLimiter(1, 2, 10).Limit())
+	assert.Equal(t, 10, NewAdaptiveLimiter(20, 2, 10).Limit())
+}
+
+// export acquires the limiter for a single attempt with the given result.
+func export(l *AdaptiveLimiter, res ExportResult, latency time.Duration) {
+	l.Acquire()
+	l.RecordAttempt(l.StartAttempt(), res, latency)
+	l.Release()
+}
+
+func TestAdaptiveLimiter_IncreaseWhenSaturated(t *testing.T) {
+	l := NewAdaptiveLimiter(2, 1, 3)
+
+	// The limit is not increased if the exports are not limited by it.
+	export(l, ExportSucceeded, time.Millisecond)
+	assert.Equal(t, 2, l.Limit())
+
+	// A full window of successful attempts increases the limit by one, up to the maximum.
+	for i := 0; i < 10; i++ {
+		l.Acquire()
+		l.Acquire()
+		tokens := []uint64{l.StartAttempt(), l.StartAttempt()}
+		for _, token := range tokens {
+			l.RecordAttempt(token, ExportSucceeded, time.Millisecond)
+		}
+		l.Release()
+		l.Release()
+	}
+	assert.Equal(t, 3, l.Limit())
+}
+
+func TestAdaptiveLimiter_DecreaseOnOverload(t *testing.T) {
+	l := NewAdaptiveLimiter(8, 2, 10)
+
+	// The attempts failing at the same time decrease the limit once.
+	tokens := []uint64{l.StartAttempt(), l.StartAttempt(), l.StartAttempt()}
+	for _, token := range tokens {
+		l.RecordAttempt(token, ExportOverloaded, time.Millisecond)
+	}
+	assert.Equal(t, 6, l.Limit())
+
+	// The ignored results don't change the limit.
+	export(l, ExportIgnored, time.Millisecond)
+	assert.Equal(t, 6, l.Limit())
+
+	// The limit doesn't go below the minimum.
+	for i := 0; i < 10; i++ {
+		export(l, ExportOverloaded, time.Millisecond)
+	}
+	assert.Equal(t, 2, l.Limit())
+}
+
+func TestAdaptiveLimiter_DecreaseOnLatency(t *testing.T) {
+	l := NewAdaptiveLimiter(8, 1, 10)
+	for i := 0; i < 10; i++ {
+		export(l, ExportSucceeded, 10*time.Millisecond)
+	}
+	assert.Equal(t, 8, l.Limit())
+
+	export(l, ExportSucceeded, 50*time.Millisecond)
+	assert.Equal(t, 6, l.Limit())
+}
+
+func TestAdaptiveLimiter_DecreaseWhileAcquired(t *testing.T) {
+	l := NewAdaptiveLimiter(4, 1, 4)
+	l.Acquire()
+
+	// The failed attempts of an export still in progress, e.g. retried, decrease the limit.
+	l.RecordAttempt(l.StartAttempt(), ExportOverloaded, time.Millisecond)
+	assert.Equal(t, 3, l.Limit())
+	l.RecordAttempt(l.StartAttempt(), ExportOverloaded, time.Millisecond)
+	assert.Equal(t, 2, l.Limit())
+	l.Release()
+}
+
+func TestAdaptiveLimiter_AcquireBlocksAtLimit(t *testing.T) {
+	l := NewAdaptiveLimiter(1, 1, 1)
+	l.Acquire()
+
+	var acquired atomic.Bool
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		l.Acquire()
+		l.Release()
+		acquired.Store(true)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, acquired.Load())
+
+	l.Release()
+	wg.Wait()
+	assert.True(t, acquired.Load())
+}
+
+func TestAdaptiveQueueConsumers(t *testing.T) {
+	q := NewBoundedMemoryQueue[int](MemoryQueueSettings[int]{Sizer: &RequestSizer[int]{}, Capacity: 100})
+	l := NewAdaptiveLimiter(4, 1, 4)
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	var consumed atomic.Int64
+	consumers := NewAdaptiveQueueConsumers[int](q, 4, l, func(context.Context, int) error {
+		token := l.StartAttempt()
+		defer l.RecordAttempt(token, ExportOverloaded, time.Millisecond)
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		consumed.Add(1)
+		return errors.New("throttled")
+	})
+	require.NoError(t, consumers.Start(context.Background(), componenttest.NewNopHost()))
+	for i := 0; i < 50; i++ {
+		require.NoError(t, q.Offer(context.Background(), i))
+	}
+	assert.Eventually(t, func() bool { return consumed.Load() == 50 }, time.Second, time.Millisecond)
+	assert.NoError(t, consumers.Shutdown(context.Background()))
+
+	// All the exports failing, the limit drops to the minimum.
+	assert.Equal(t, 1, l.Limit())
+	assert.LessOrEqual(t, maxInFlight, 4)
+}
@@ -7,6 +7,7 @@ package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 )
@@ -59,6 +60,16 @@ func (q *boundedMemoryQueue[T]) Shutdown(context.Context) error {
 	return nil
 }
 
+// OldestItemTime returns the time the oldest request still waiting in the queue was offered at.
+func (q *boundedMemoryQueue[T]) OldestItemTime() (time.Time, bool) {
+	return q.sizedChannel.oldest()
+}
+
+// Purge drops all the requests waiting in the queue.
+func (q *boundedMemoryQueue[T]) Purge(context.Context) (int, error) {
+	return len(q.sizedChannel.drain()), nil
+}
+
 type memQueueEl[T any] struct {
 	req T
 	ctx context.Context
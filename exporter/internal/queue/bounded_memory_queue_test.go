@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -121,6 +122,9 @@ func TestQueueUsage(t *testing.T) {
 	t.Run("items_based", func(t *testing.T) {
 		queueUsage(t, &ItemsSizer[fakeReq]{}, 10)
 	})
+	t.Run("bytes_based", func(t *testing.T) {
+		queueUsage(t, &BytesSizer[fakeReq]{}, 10)
+	})
 }
 
 func benchmarkQueueUsage(b *testing.B, sizer Sizer[fakeReq], requestsCount int) {
@@ -164,3 +168,86 @@ type fakeReq struct {
 func (r fakeReq) ItemsCount() int {
 	return r.itemsCount
 }
+
+func (r fakeReq) BytesSize() int {
+	return r.itemsCount
+}
+
+func TestBoundedQueueBytesSizer(t *testing.T) {
+	q := NewBoundedMemoryQueue[fakeReq](MemoryQueueSettings[fakeReq]{Sizer: &BytesSizer[fakeReq]{}, Capacity: 25})
+	require.NoError(t, q.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, q.Offer(context.Background(), fakeReq{10}))
+	require.NoError(t, q.Offer(context.Background(), fakeReq{10}))
+	assert.Equal(t, 20, q.Size())
+	assert.ErrorIs(t, q.Offer(context.Background(), fakeReq{10}), ErrQueueIsFull)
+	require.NoError(t, q.Offer(context.Background(), fakeReq{5}))
+	assert.Equal(t, 25, q.Size())
+	length, ok := Length[fakeReq](q)
+	assert.True(t, ok)
+	assert.Equal(t, 3, length)
+
+	assert.True(t, q.Consume(func(context.Context, fakeReq) error { return nil }))
+	assert.Equal(t, 15, q.Size())
+	length, _ = Length[fakeReq](q)
+	assert.Equal(t, 2, length)
+	assert.NoError(t, q.Shutdown(context.Background()))
+}
+
+func TestBoundedQueue_Purge(t *testing.T) {
+	q := NewBoundedMemoryQueue[string](MemoryQueueSettings[string]{Sizer: &RequestSizer[string]{}, Capacity: 10})
+	assert.Equal(t, "memory", Kind(q))
+	inspectable := q.(Inspectable)
+	_, ok := inspectable.OldestItemTime()
+	assert.False(t, ok)
+
+	for _, s := range []string{"a", "b", "c"} {
+		require.NoError(t, q.Offer(context.Background(), s))
+	}
+	_, ok = inspectable.OldestItemTime()
+	assert.True(t, ok)
+
+	n, err := inspectable.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, 0, q.Size())
+	_, ok = inspectable.OldestItemTime()
+	assert.False(t, ok)
+
+	// The queue keeps working after being purged.
+	require.NoError(t, q.Offer(context.Background(), "d"))
+	assert.True(t, q.Consume(func(_ context.Context, item string) error {
+		assert.Equal(t, "d", item)
+		return nil
+	}))
+	assert.NoError(t, q.Shutdown(context.Background()))
+}
+
+func TestQueueConsumers_PauseResume(t *testing.T) {
+	q := NewBoundedMemoryQueue[string](MemoryQueueSettings[string]{Sizer: &RequestSizer[string]{}, Capacity: 10})
+	consumed := make(chan string, 10)
+	consumers := NewQueueConsumers(q, 1, func(_ context.Context, item string) error {
+		consumed <- item
+		return nil
+	})
+	require.NoError(t, consumers.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, 1, consumers.NumConsumers())
+
+	consumers.Pause()
+	assert.True(t, consumers.Paused())
+	require.NoError(t, q.Offer(context.Background(), "a"))
+	require.NoError(t, q.Offer(context.Background(), "b"))
+	// The consumer may take one request, but it isn't consumed while paused.
+	assert.Never(t, func() bool { return len(consumed) > 0 }, 50*time.Millisecond, time.Millisecond)
+
+	consumers.Resume()
+	assert.False(t, consumers.Paused())
+	assert.Equal(t, "a", <-consumed)
+	assert.Equal(t, "b", <-consumed)
+
+	// Paused consumers are resumed on shutdown, so the queue is drained.
+	consumers.Pause()
+	require.NoError(t, q.Offer(context.Background(), "c"))
+	assert.NoError(t, consumers.Shutdown(context.Background()))
+	assert.Equal(t, "c", <-consumed)
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+
+	"go.opentelemetry.io/collector/config/configcompression"
+)
+
+var (
+	// The zstd encoder and decoder are expensive to create and safe for concurrent use, so they're shared.
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil)
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil)
+	})
+)
+
+// compress compresses the item with the given algorithm.
+func compress(typ configcompression.Type, buf []byte) ([]byte, error) {
+	switch typ {
+	case configcompression.TypeSnappy:
+		return snappy.Encode(nil, buf), nil
+	case configcompression.TypeZstd:
+		enc, err := zstdEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(buf, nil), nil
+	}
+
+	var out bytes.Buffer
+	var w io.WriteCloser
+	switch typ {
+	case configcompression.TypeGzip:
+		w = gzip.NewWriter(&out)
+	case configcompression.TypeZlib:
+		w = zlib.NewWriter(&out)
+	case configcompression.TypeDeflate:
+		// The default level is always valid.
+		w, _ = flate.NewWriter(&out, flate.DefaultCompression)
+	default:
+		return nil, fmt.Errorf("unsupported compression type %q", typ)
+	}
+	if _, err := w.Write(buf); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// decompress decompresses the item compressed with the given algorithm.
+func decompress(typ configcompression.Type, buf []byte) ([]byte, error) {
+	var r io.ReadCloser
+	switch typ {
+	case configcompression.TypeSnappy:
+		return snappy.Decode(nil, buf)
+	case configcompression.TypeZstd:
+		dec, err := zstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.DecodeAll(buf, nil)
+	case configcompression.TypeGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(buf))
+		if err != nil {
+			return nil, err
+		}
+		r = gr
+	case configcompression.TypeZlib:
+		zr, err := zlib.NewReader(bytes.NewReader(buf))
+		if err != nil {
+			return nil, err
+		}
+		r = zr
+	case configcompression.TypeDeflate:
+		r = flate.NewReader(bytes.NewReader(buf))
+	default:
+		return nil, fmt.Errorf("unsupported compression type %q", typ)
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
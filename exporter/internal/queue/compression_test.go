@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config/configcompression"
+)
+
+func TestCompression_RoundTrip(t *testing.T) {
+	plain := bytes.Repeat([]byte("some telemetry "), 100)
+	for _, typ := range []configcompression.Type{
+		configcompression.TypeGzip,
+		configcompression.TypeZlib,
+		configcompression.TypeDeflate,
+		configcompression.TypeSnappy,
+		configcompression.TypeZstd,
+	} {
+		t.Run(string(typ), func(t *testing.T) {
+			buf, err := compress(typ, plain)
+			require.NoError(t, err)
+			assert.Less(t, len(buf), len(plain))
+
+			got, err := decompress(typ, buf)
+			require.NoError(t, err)
+			assert.Equal(t, plain, got)
+
+			_, err = decompress(typ, []byte("not compressed"))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCompression_Unsupported(t *testing.T) {
+	_, err := compress("lz4", []byte("some telemetry"))
+	require.EqualError(t, err, `unsupported compression type "lz4"`)
+	_, err = decompress("lz4", []byte("some telemetry"))
+	require.EqualError(t, err, `unsupported compression type "lz4"`)
+}
@@ -6,6 +6,7 @@ package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 
 	"go.opentelemetry.io/collector/component"
 )
@@ -15,15 +16,39 @@ type Consumers[T any] struct {
 	numConsumers int
 	consumeFunc  func(context.Context, T) error
 	stopWG       sync.WaitGroup
+
+	// limiter, if set, bounds the number of consumers exporting at the same time. It's told about the result of
+	// every attempt to export by the consume function.
+	limiter *AdaptiveLimiter
+
+	// pauseMu guards paused. resumed is broadcast once the consumers are resumed.
+	pauseMu sync.Mutex
+	resumed *sync.Cond
+	paused  bool
+
+	// stopped is set once the consumers must not take any more requests from the queue.
+	stopped atomic.Bool
 }
 
 func NewQueueConsumers[T any](q Queue[T], numConsumers int, consumeFunc func(context.Context, T) error) *Consumers[T] {
-	return &Consumers[T]{
+	qc := &Consumers[T]{
 		queue:        q,
 		numConsumers: numConsumers,
 		consumeFunc:  consumeFunc,
 		stopWG:       sync.WaitGroup{},
 	}
+	qc.resumed = sync.NewCond(&qc.pauseMu)
+	return qc
+}
+
+// NewAdaptiveQueueConsumers creates consumers exporting concurrently up to the limit set by the limiter. The consume
+// function must record the result of every attempt to export with the limiter, so it adjusts the limit.
+// numConsumers must be the maximum limit of the limiter.
+func NewAdaptiveQueueConsumers[T any](q Queue[T], numConsumers int, limiter *AdaptiveLimiter,
+	consumeFunc func(context.Context, T) error) *Consumers[T] {
+	qc := NewQueueConsumers[T](q, numConsumers, consumeFunc)
+	qc.limiter = limiter
+	return qc
 }
 
 // Start ensures that queue and all consumers are started.
@@ -39,8 +64,12 @@ func (qc *Consumers[T]) Start(ctx context.Context, host component.Host) error {
 		go func() {
 			startWG.Done()
 			defer qc.stopWG.Done()
-			for {
-				if !qc.queue.Consume(qc.consumeFunc) {
+			if qc.limiter != nil {
+				qc.consumeLimited()
+				return
+			}
+			for !qc.stopped.Load() {
+				if !qc.queue.Consume(qc.consume) {
 					return
 				}
 			}
@@ -51,8 +80,74 @@ func (qc *Consumers[T]) Start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
-// Shutdown ensures that queue and all consumers are stopped.
+// consumeLimited consumes the queue until it's stopped, waiting for the limiter before taking every request.
+func (qc *Consumers[T]) consumeLimited() {
+	for {
+		qc.limiter.Acquire()
+		if qc.stopped.Load() {
+			qc.limiter.Release()
+			return
+		}
+		consumed := qc.queue.Consume(qc.consume)
+		qc.limiter.Release()
+		if !consumed {
+			return
+		}
+	}
+}
+
+// consume waits for the consumers to be resumed if paused, and applies the consume function on the request.
+func (qc *Consumers[T]) consume(ctx context.Context, req T) error {
+	qc.waitResumed()
+	return qc.consumeFunc(ctx, req)
+}
+
+// Pause stops the consumers from consuming the requests until Resume is called. The requests already taken from
+// the queue by the consumers are held until then, at most one per consumer.
+func (qc *Consumers[T]) Pause() {
+	qc.pauseMu.Lock()
+	defer qc.pauseMu.Unlock()
+	qc.paused = true
+}
+
+// Resume lets the consumers consume the requests again.
+func (qc *Consumers[T]) Resume() {
+	qc.pauseMu.Lock()
+	defer qc.pauseMu.Unlock()
+	qc.paused = false
+	qc.resumed.Broadcast()
+}
+
+// Paused returns true if the consumers are paused.
+func (qc *Consumers[T]) Paused() bool {
+	qc.pauseMu.Lock()
+	defer qc.pauseMu.Unlock()
+	return qc.paused
+}
+
+func (qc *Consumers[T]) waitResumed() {
+	qc.pauseMu.Lock()
+	defer qc.pauseMu.Unlock()
+	for qc.paused {
+		qc.resumed.Wait()
+	}
+}
+
+// Stop stops the consumers from taking more requests from the queue, the requests left stay in it.
+// The requests already taken are still consumed. Consumers waiting for a request in an empty queue take
+// at most one more request each, which must not happen once the producers are stopped.
+func (qc *Consumers[T]) Stop() {
+	qc.stopped.Store(true)
+}
+
+// NumConsumers returns the number of consumers.
+func (qc *Consumers[T]) NumConsumers() int {
+	return qc.numConsumers
+}
+
+// Shutdown ensures that queue and all consumers are stopped. Paused consumers are resumed, so the queue is drained.
 func (qc *Consumers[T]) Shutdown(ctx context.Context) error {
+	qc.Resume()
 	if err := qc.queue.Shutdown(ctx); err != nil {
 		return err
 	}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// encryptionFormatVersion is the version of the format of the encrypted items, stored as their first byte.
+const encryptionFormatVersion byte = 1
+
+// ErrDecryption is the error returned when an item read from the storage cannot be decrypted.
+var ErrDecryption = errors.New("failed to decrypt the item read from the persistent queue")
+
+// Encryptor encrypts the items written to the persistent storage with AES-GCM. Every item is prefixed by
+// a header holding the ID of the key it's encrypted with, so the items written before the active key is rotated
+// can still be decrypted with the previous keys. The encrypted items are laid out as follows:
+//
+//	version (1 byte) | key ID length (1 byte) | key ID | nonce | ciphertext and tag
+//
+// The header is authenticated along with the ciphertext.
+type Encryptor struct {
+	aeads    map[string]cipher.AEAD
+	activeID string
+}
+
+// NewEncryptor returns an Encryptor encrypting the items with the key of ID activeID, and decrypting them with any
+// of the given keys. The keys must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func NewEncryptor(keys map[string][]byte, activeID string) (*Encryptor, error) {
+	e := &Encryptor{aeads: make(map[string]cipher.AEAD, len(keys)), activeID: activeID}
+	for id, key := range keys {
+		if len(id) > math.MaxUint8 {
+			return nil, fmt.Errorf("encryption key ID %q is longer than %d bytes", id, math.MaxUint8)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key %q: %w", id, err)
+		}
+		e.aeads[id] = aead
+	}
+	if _, ok := e.aeads[activeID]; !ok {
+		return nil, fmt.Errorf("active encryption key %q is not one of the keys", activeID)
+	}
+	return e, nil
+}
+
+// Encrypt encrypts the item with the active key.
+func (e *Encryptor) Encrypt(buf []byte) ([]byte, error) {
+	aead := e.aeads[e.activeID]
+	header := make([]byte, 0, 2+len(e.activeID)+aead.NonceSize()+len(buf)+aead.Overhead())
+	header = append(header, encryptionFormatVersion, byte(len(e.activeID)))
+	header = append(header, e.activeID...)
+	nonce := header[len(header) : len(header)+aead.NonceSize()]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate the encryption nonce: %w", err)
+	}
+	return aead.Seal(header[:len(header)+len(nonce)], nonce, buf, header), nil
+}
+
+// Decrypt decrypts the item with the key it was encrypted with. The returned error wraps ErrDecryption.
+func (e *Encryptor) Decrypt(buf []byte) ([]byte, error) {
+	if len(buf) < 2 {
+		return nil, fmt.Errorf("%w: the item is not encrypted or is corrupted", ErrDecryption)
+	}
+	if buf[0] != encryptionFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported encryption format version %d, the item may not be encrypted", ErrDecryption, buf[0])
+	}
+	headerLen := 2 + int(buf[1])
+	if len(buf) < headerLen {
+		return nil, fmt.Errorf("%w: the item is corrupted", ErrDecryption)
+	}
+	id := string(buf[2:headerLen])
+	aead, ok := e.aeads[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: the item is encrypted with the unknown key %q", ErrDecryption, id)
+	}
+	if len(buf) < headerLen+aead.NonceSize() {
+		return nil, fmt.Errorf("%w: the item is corrupted", ErrDecryption)
+	}
+	nonce := buf[headerLen : headerLen+aead.NonceSize()]
+	plain, err := aead.Open(nil, nonce, buf[headerLen+aead.NonceSize():], buf[:headerLen])
+	if err != nil {
+		return nil, fmt.Errorf("%w: the key %q doesn't match or the item is corrupted: %w", ErrDecryption, id, err)
+	}
+	return plain, nil
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKey1 = bytes.Repeat([]byte{1}, 32)
+	testKey2 = bytes.Repeat([]byte{2}, 16)
+)
+
+func TestEncryptor_RoundTrip(t *testing.T) {
+	enc, err := NewEncryptor(map[string][]byte{"k1": testKey1}, "k1")
+	require.NoError(t, err)
+
+	plain := []byte("some telemetry")
+	buf, err := enc.Encrypt(plain)
+	require.NoError(t, err)
+	assert.NotContains(t, string(buf), string(plain))
+
+	// Every item is encrypted with a different nonce.
+	buf2, err := enc.Encrypt(plain)
+	require.NoError(t, err)
+	assert.NotEqual(t, buf, buf2)
+
+	got, err := enc.Decrypt(buf)
+	require.NoError(t, err)
+	assert.Equal(t, plain, got)
+
+	got, err = enc.Decrypt(buf2)
+	require.NoError(t, err)
+	assert.Equal(t, plain, got)
+}
+
+func TestEncryptor_KeyRotation(t *testing.T) {
+	enc1, err := NewEncryptor(map[string][]byte{"k1": testKey1}, "k1")
+	require.NoError(t, err)
+	old, err := enc1.Encrypt([]byte("old"))
+	require.NoError(t, err)
+
+	// The items encrypted with the previous key are still decrypted while the key is kept.
+	enc2, err := NewEncryptor(map[string][]byte{"k1": testKey1, "k2": testKey2}, "k2")
+	require.NoError(t, err)
+	got, err := enc2.Decrypt(old)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("old"), got)
+
+	buf, err := enc2.Encrypt([]byte("new"))
+	require.NoError(t, err)
+	_, err = enc1.Decrypt(buf)
+	require.ErrorIs(t, err, ErrDecryption)
+	assert.ErrorContains(t, err, `the item is encrypted with the unknown key "k2"`)
+}
+
+func TestEncryptor_DecryptErrors(t *testing.T) {
+	enc, err := NewEncryptor(map[string][]byte{"k1": testKey1}, "k1")
+	require.NoError(t, err)
+	buf, err := enc.Encrypt([]byte("some telemetry"))
+	require.NoError(t, err)
+
+	// Same key ID, different key.
+	wrongKey, err := NewEncryptor(map[string][]byte{"k1": testKey2}, "k1")
+	require.NoError(t, err)
+	_, err = wrongKey.Decrypt(buf)
+	require.ErrorIs(t, err, ErrDecryption)
+	assert.ErrorContains(t, err, `the key "k1" doesn't match or the item is corrupted`)
+
+	tampered := bytes.Clone(buf)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = enc.Decrypt(tampered)
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	// The key ID is authenticated.
+	renamed, err := NewEncryptor(map[string][]byte{"k1": testKey1, "k9": testKey1}, "k1")
+	require.NoError(t, err)
+	tampered = bytes.Clone(buf)
+	tampered[3] = '9'
+	_, err = renamed.Decrypt(tampered)
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	_, err = enc.Decrypt([]byte("not encrypted"))
+	require.ErrorIs(t, err, ErrDecryption)
+	assert.ErrorContains(t, err, "unsupported encryption format version")
+
+	for _, b := range [][]byte{nil, {encryptionFormatVersion, 5, 'k'}, buf[:6]} {
+		_, err = enc.Decrypt(b)
+		assert.ErrorIs(t, err, ErrDecryption)
+	}
+}
+
+func TestNewEncryptor_Errors(t *testing.T) {
+	_, err := NewEncryptor(map[string][]byte{"k1": []byte("short")}, "k1")
+	assert.ErrorContains(t, err, `invalid encryption key "k1"`)
+
+	_, err = NewEncryptor(map[string][]byte{"k1": testKey1}, "k2")
+	assert.EqualError(t, err, `active encryption key "k2" is not one of the keys`)
+}
@@ -0,0 +1,244 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+)
+
+// hybridQueue keeps the requests in memory as long as there is room for them, and spills them over to a persistentQueue
+// once the in-memory capacity is exhausted or when the consumers have been failing to export for longer than
+// spillAfter. It gives the latency of the memory queue under normal load, and the durability of the persistent queue
+// during outages.
+//
+// Every offered request goes through the same in-memory sizedChannel, either carrying the request itself or a marker
+// pointing to the next request in the storage, so the requests are consumed in the order they were offered
+// regardless of where they are kept.
+type hybridQueue[T any] struct {
+	// mem is used for the capacity control of the in-memory requests. The markers of spilled requests have zero size.
+	mem *sizedChannel[hybridQueueEl[T]]
+	pq  *persistentQueue[T]
+
+	// stopMu is held for reading by every Consume call, so the storage isn't closed on shutdown while the requests
+	// taken from memory are being exported. The ones failing with a shutdown error can still be moved to the storage.
+	stopMu sync.RWMutex
+
+	sizer      Sizer[T]
+	spillAfter time.Duration
+	logger     *zap.Logger
+
+	// failingSince is the Unix time in nanoseconds of the first failed export since the last successful one,
+	// or zero if the last export succeeded.
+	failingSince atomic.Int64
+	spilling     atomic.Bool
+}
+
+// HybridQueueSettings defines internal parameters for hybridQueue creation.
+// The embedded PersistentQueueSettings configure the storage used for the requests that don't fit in memory.
+type HybridQueueSettings[T any] struct {
+	PersistentQueueSettings[T]
+	// MemoryCapacity is the capacity of the in-memory part of the queue, measured with the same Sizer.
+	MemoryCapacity int64
+	// SpillAfter is the duration after which new requests are spilled over to the storage when all the exports have
+	// been failing in the meantime. Zero means that the requests are spilled over only when the memory is full.
+	SpillAfter time.Duration
+}
+
+// NewHybridQueue creates a new queue keeping the requests in memory and spilling them over to the storage.
+func NewHybridQueue[T any](set HybridQueueSettings[T]) Queue[T] {
+	return &hybridQueue[T]{
+		mem:        newSizedChannel[hybridQueueEl[T]](set.MemoryCapacity, nil, 0),
+		pq:         NewPersistentQueue[T](set.PersistentQueueSettings).(*persistentQueue[T]),
+		sizer:      set.Sizer,
+		spillAfter: set.SpillAfter,
+		logger:     set.ExporterSettings.Logger,
+	}
+}
+
+// Start starts the underlying persistent queue. The requests restored from the storage are consumed first.
+func (hq *hybridQueue[T]) Start(ctx context.Context, host component.Host) error {
+	if err := hq.pq.Start(ctx, host); err != nil {
+		return err
+	}
+	for i := hq.pq.sizedChannel.length(); i > 0; i-- {
+		if err := hq.mem.push(hybridQueueEl[T]{onDisk: true}, 0, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Offer inserts the specified element into the in-memory part of the queue if there is room for it and the exports
+// are not failing for too long, otherwise the element is written to the storage.
+// It returns ErrQueueIsFull if no space is currently available in the storage either.
+func (hq *hybridQueue[T]) Offer(ctx context.Context, req T) error {
+	if !hq.shouldSpill() {
+		err := hq.mem.push(hybridQueueEl[T]{ctx: ctx, req: req}, hq.sizer.Sizeof(req), nil)
+		if !errors.Is(err, ErrQueueIsFull) {
+			return err
+		}
+	}
+	if err := hq.pq.Offer(ctx, req); err != nil {
+		return err
+	}
+	return hq.mem.push(hybridQueueEl[T]{onDisk: true}, 0, nil)
+}
+
+// Consume applies the provided function on the head of queue.
+// The call blocks until there is an item available or the queue is stopped.
+// The function returns true when an item is consumed or false if the queue is stopped and emptied.
+func (hq *hybridQueue[T]) Consume(consumeFunc func(context.Context, T) error) bool {
+	hq.stopMu.RLock()
+	defer hq.stopMu.RUnlock()
+
+	el, ok := hq.mem.pop(func(el hybridQueueEl[T]) int64 {
+		if el.onDisk {
+			return 0
+		}
+		return hq.sizer.Sizeof(el.req)
+	})
+	if !ok {
+		return false
+	}
+
+	trackedConsumeFunc := func(ctx context.Context, req T) error {
+		err := consumeFunc(ctx, req)
+		hq.recordExportResult(err)
+		return err
+	}
+	if !el.onDisk {
+		// The in-memory requests are not retried on consume errors, same as in the memory queue. However, if the queue
+		// is shutting down, the request is moved to the storage, so it's picked up again after restart.
+		if err := trackedConsumeFunc(el.ctx, el.req); experr.IsShutdownErr(err) && !hq.persist(el.ctx, el.req) {
+			hq.logger.Error("Failed to move an in-flight request to the storage on shutdown, dropping it")
+		}
+		return true
+	}
+	// Every marker matches exactly one element in the persistent queue, so this call never blocks for long.
+	// If the request cannot be read from the storage, it's skipped.
+	hq.pq.consumeNext(trackedConsumeFunc)
+	return true
+}
+
+// Shutdown stops the queue. The requests still kept in memory are written to the storage, so they are exported
+// after the next start instead of being lost.
+func (hq *hybridQueue[T]) Shutdown(ctx context.Context) error {
+	hq.mem.shutdown()
+	// If the queue is not started, there is nothing to move to the storage.
+	if hq.pq.client == nil {
+		return nil
+	}
+
+	dropped := 0
+	for _, el := range hq.mem.drain() {
+		if el.onDisk {
+			continue
+		}
+		if !hq.persist(el.ctx, el.req) {
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		hq.logger.Error("Failed to move in-memory requests to the storage on shutdown, dropping them",
+			zap.Int(zapNumberOfItems, dropped))
+	}
+
+	// Wait for the requests being consumed to be either exported or moved to the storage.
+	hq.stopMu.Lock()
+	defer hq.stopMu.Unlock()
+	return hq.pq.Shutdown(ctx)
+}
+
+// persist writes the request to the storage without notifying the consumers. It's used on shutdown only, to keep
+// the requests that haven't been exported for the next start. It returns false if the request could not be written.
+func (hq *hybridQueue[T]) persist(ctx context.Context, req T) bool {
+	hq.pq.mu.Lock()
+	defer hq.pq.mu.Unlock()
+	if hq.pq.stopped {
+		return false
+	}
+	return hq.pq.putInternal(ctx, req) == nil
+}
+
+// OldestItemTime returns the time the oldest request still waiting in the queue was offered at, whether it's kept
+// in memory or in the storage. The requests restored from the storage are considered offered at start.
+func (hq *hybridQueue[T]) OldestItemTime() (time.Time, bool) {
+	return hq.mem.oldest()
+}
+
+// Purge drops all the requests waiting in memory and deletes the ones waiting in the storage.
+func (hq *hybridQueue[T]) Purge(ctx context.Context) (int, error) {
+	els := hq.mem.drain()
+	markers := 0
+	for _, el := range els {
+		if el.onDisk {
+			markers++
+		}
+	}
+	// Only the requests matching the drained markers are deleted, the others are being consumed.
+	// The markers are in the same order as the requests in the storage, so these are the oldest ones.
+	n, err := hq.pq.discard(ctx, uint64(markers))
+	return len(els) - markers + n, err
+}
+
+// Size returns the total size of the requests kept in memory and in the storage.
+func (hq *hybridQueue[T]) Size() int {
+	size := hq.mem.Size()
+	if hq.pq.sizedChannel != nil {
+		size += hq.pq.Size()
+	}
+	return size
+}
+
+// Capacity returns the total capacity of the in-memory and the persistent parts of the queue.
+func (hq *hybridQueue[T]) Capacity() int {
+	return hq.mem.Capacity() + int(hq.pq.set.Capacity)
+}
+
+// shouldSpill returns true if the exports have been failing for longer than spillAfter.
+func (hq *hybridQueue[T]) shouldSpill() bool {
+	if hq.spillAfter <= 0 {
+		return false
+	}
+	since := hq.failingSince.Load()
+	spill := since != 0 && time.Since(time.Unix(0, since)) >= hq.spillAfter
+	if spill != hq.spilling.Swap(spill) {
+		if spill {
+			hq.logger.Warn("Exports have been failing for too long, spilling new requests over to the storage",
+				zap.Duration("spill_after", hq.spillAfter))
+		} else {
+			hq.logger.Info("Exports are succeeding again, keeping new requests in memory")
+		}
+	}
+	return spill
+}
+
+func (hq *hybridQueue[T]) recordExportResult(err error) {
+	if err == nil {
+		hq.failingSince.Store(0)
+		return
+	}
+	// Failures caused by the shutdown don't tell anything about the health of the destination.
+	if experr.IsShutdownErr(err) {
+		return
+	}
+	hq.failingSince.CompareAndSwap(0, time.Now().UnixNano())
+}
+
+// hybridQueueEl is the type of the elements passed to the sizedChannel by the hybridQueue.
+// It either carries a request kept in memory or marks the next request in the persistent queue.
+type hybridQueueEl[T any] struct {
+	ctx    context.Context
+	req    T
+	onDisk bool
+}
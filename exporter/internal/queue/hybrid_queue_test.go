@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+func createTestHybridQueue(t *testing.T, ext storage.Extension, memCapacity, diskCapacity int64,
+	spillAfter time.Duration) *hybridQueue[tracesRequest] {
+	hq := newTestHybridQueue(memCapacity, diskCapacity, spillAfter)
+	require.NoError(t, hq.Start(context.Background(), &mockHost{ext: map[component.ID]component.Component{{}: ext}}))
+	return hq
+}
+
+func newTestHybridQueue(memCapacity, diskCapacity int64, spillAfter time.Duration) *hybridQueue[tracesRequest] {
+	return NewHybridQueue[tracesRequest](HybridQueueSettings[tracesRequest]{
+		PersistentQueueSettings: PersistentQueueSettings[tracesRequest]{
+			Sizer:            &RequestSizer[tracesRequest]{},
+			Capacity:         diskCapacity,
+			DataType:         component.DataTypeTraces,
+			StorageID:        component.ID{},
+			Marshaler:        marshalTracesRequest,
+			Unmarshaler:      unmarshalTracesRequest,
+			ExporterSettings: exportertest.NewNopSettings(),
+		},
+		MemoryCapacity: memCapacity,
+		SpillAfter:     spillAfter,
+	}).(*hybridQueue[tracesRequest])
+}
+
+// consumeSpanCounts consumes n requests from the queue and returns the number of spans of each of them.
+func consumeSpanCounts(t *testing.T, q Queue[tracesRequest], n int) []int {
+	var spanCounts []int
+	for i := 0; i < n; i++ {
+		require.True(t, q.Consume(func(_ context.Context, req tracesRequest) error {
+			spanCounts = append(spanCounts, req.traces.SpanCount())
+			return nil
+		}))
+	}
+	return spanCounts
+}
+
+func TestHybridQueue_SpillOverWhenMemoryFull(t *testing.T) {
+	hq := createTestHybridQueue(t, NewMockStorageExtension(nil), 2, 10, 0)
+	assert.Equal(t, 12, hq.Capacity())
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, i)))
+	}
+	assert.Equal(t, 5, hq.Size())
+	assert.Equal(t, 2, hq.mem.Size())
+	assert.Equal(t, 3, hq.pq.Size())
+
+	// The requests are consumed in the order they were offered, regardless of where they are kept.
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, consumeSpanCounts(t, hq, 5))
+	assert.Equal(t, 0, hq.Size())
+
+	// Once the memory is available again, the requests are kept in memory.
+	require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, 1)))
+	assert.Equal(t, 1, hq.mem.Size())
+	assert.Equal(t, 0, hq.pq.Size())
+	assert.NoError(t, hq.Shutdown(context.Background()))
+}
+
+func TestHybridQueue_Full(t *testing.T) {
+	hq := createTestHybridQueue(t, NewMockStorageExtension(nil), 1, 1, 0)
+
+	require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, 1)))
+	require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, 1)))
+	assert.ErrorIs(t, hq.Offer(context.Background(), newTracesRequest(1, 1)), ErrQueueIsFull)
+	assert.Equal(t, 2, hq.Size())
+	assert.NoError(t, hq.Shutdown(context.Background()))
+}
+
+func TestHybridQueue_SpillAfterFailures(t *testing.T) {
+	hq := createTestHybridQueue(t, NewMockStorageExtension(nil), 10, 10, time.Nanosecond)
+
+	require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, 1)))
+	assert.Equal(t, 1, hq.mem.Size())
+
+	// A shutdown error doesn't make the queue spill over.
+	hq.recordExportResult(experr.NewShutdownErr(errors.New("stopped")))
+	assert.False(t, hq.shouldSpill())
+
+	require.True(t, hq.Consume(func(context.Context, tracesRequest) error { return errors.New("export failed") }))
+	require.Eventually(t, hq.shouldSpill, time.Second, time.Millisecond)
+	require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, 3)))
+	assert.Equal(t, 0, hq.mem.Size())
+	assert.Equal(t, 1, hq.pq.Size())
+
+	// A successful export switches back to memory.
+	assert.Equal(t, []int{3}, consumeSpanCounts(t, hq, 1))
+	require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, 4)))
+	assert.Equal(t, 1, hq.mem.Size())
+	assert.Equal(t, 0, hq.pq.Size())
+	assert.NoError(t, hq.Shutdown(context.Background()))
+}
+
+func TestHybridQueue_ShutdownMovesMemoryToStorage(t *testing.T) {
+	ext := NewMockStorageExtension(nil)
+	hq := createTestHybridQueue(t, ext, 2, 10, 0)
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, i)))
+	}
+	assert.Equal(t, 2, hq.mem.Size())
+	assert.NoError(t, hq.Shutdown(context.Background()))
+	assert.Equal(t, 0, hq.mem.Size())
+
+	// After the restart, the restored requests are consumed before the new ones.
+	newHQ := createTestHybridQueue(t, ext, 2, 10, 0)
+	assert.Equal(t, 4, newHQ.Size())
+	require.NoError(t, newHQ.Offer(context.Background(), newTracesRequest(1, 5)))
+	assert.Equal(t, 1, newHQ.mem.Size())
+	assert.Equal(t, []int{3, 4, 1, 2, 5}, consumeSpanCounts(t, newHQ, 5))
+	assert.Equal(t, 0, newHQ.Size())
+	assert.NoError(t, newHQ.Shutdown(context.Background()))
+}
+
+func TestHybridQueue_InFlightRequestMovedToStorageOnShutdownErr(t *testing.T) {
+	ext := NewMockStorageExtension(nil)
+	hq := createTestHybridQueue(t, ext, 10, 10, 0)
+	require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, 1)))
+	require.True(t, hq.Consume(func(context.Context, tracesRequest) error {
+		return experr.NewShutdownErr(errors.New("stopped"))
+	}))
+	assert.Equal(t, 0, hq.mem.Size())
+	assert.Equal(t, 1, hq.pq.Size())
+	assert.NoError(t, hq.Shutdown(context.Background()))
+
+	newHQ := createTestHybridQueue(t, ext, 10, 10, 0)
+	assert.Equal(t, []int{1}, consumeSpanCounts(t, newHQ, 1))
+	assert.NoError(t, newHQ.Shutdown(context.Background()))
+}
+
+func TestHybridQueue_ShutdownNotStarted(t *testing.T) {
+	hq := newTestHybridQueue(10, 10, 0)
+	assert.Equal(t, 0, hq.Size())
+	assert.NoError(t, hq.Shutdown(context.Background()))
+}
+
+func TestHybridQueue_ConsumersProducers(t *testing.T) {
+	hq := newTestHybridQueue(5, 100, 0)
+	var consumed atomic.Int64
+	consumers := NewQueueConsumers[tracesRequest](hq, 3, func(_ context.Context, req tracesRequest) error {
+		consumed.Add(int64(req.traces.SpanCount()))
+		return nil
+	})
+	host := &mockHost{ext: map[component.ID]component.Component{{}: NewMockStorageExtension(nil)}}
+	require.NoError(t, consumers.Start(context.Background(), host))
+	for i := 0; i < 50; i++ {
+		require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, 2)))
+	}
+	assert.Eventually(t, func() bool { return consumed.Load() == 100 }, time.Second, time.Millisecond)
+	assert.NoError(t, consumers.Shutdown(context.Background()))
+	assert.Equal(t, 0, hq.Size())
+}
+
+func TestHybridQueue_Purge(t *testing.T) {
+	ext := NewMockStorageExtension(nil)
+	hq := createTestHybridQueue(t, ext, 2, 10, 0)
+	assert.Equal(t, "spill_over", Kind[tracesRequest](hq))
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, i)))
+	}
+	_, ok := hq.OldestItemTime()
+	assert.True(t, ok)
+
+	n, err := hq.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, 0, hq.Size())
+	_, ok = hq.OldestItemTime()
+	assert.False(t, ok)
+
+	// The queue keeps working in order after being purged.
+	for i := 6; i <= 9; i++ {
+		require.NoError(t, hq.Offer(context.Background(), newTracesRequest(1, i)))
+	}
+	assert.Equal(t, []int{6, 7, 8, 9}, consumeSpanCounts(t, hq, 4))
+	assert.NoError(t, hq.Shutdown(context.Background()))
+}
@@ -6,15 +6,20 @@ package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
 import (
 	"context"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"sync"
+	"time"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/internal/experr"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
@@ -90,6 +95,15 @@ type PersistentQueueSettings[T any] struct {
 	Marshaler        func(req T) ([]byte, error)
 	Unmarshaler      func([]byte) (T, error)
 	ExporterSettings exporter.Settings
+	// MetadataKeys is the list of client.Metadata keys stored along with the requests, and restored in the context
+	// passed to the consumers.
+	MetadataKeys []string
+	// Compression is the algorithm the requests are compressed with before being written to the storage.
+	// The algorithm is stored along with every request, so the requests compressed differently before a restart
+	// are still read.
+	Compression configcompression.Type
+	// Encryptor, if set, encrypts the requests written to the storage, once compressed.
+	Encryptor *Encryptor
 }
 
 // NewPersistentQueue creates a new queue backed by file storage; name and signal must be a unique combination that identifies the queue storage
@@ -127,11 +141,15 @@ func (pq *persistentQueue[T]) initPersistentContiguousStorage(ctx context.Contex
 
 	err := pq.client.Batch(ctx, riOp, wiOp)
 	if err == nil {
-		pq.readIndex, err = bytesToItemIndex(riOp.Value)
+		pq.writeIndex, err = bytesToItemIndex(wiOp.Value)
 	}
 
 	if err == nil {
-		pq.writeIndex, err = bytesToItemIndex(wiOp.Value)
+		pq.readIndex, err = bytesToItemIndex(riOp.Value)
+		// The read index is written by the first read only, it's not set if nothing has been consumed yet.
+		if errors.Is(err, errValueNotSet) {
+			pq.readIndex, err = 0, nil
+		}
 	}
 
 	if err != nil {
@@ -193,31 +211,42 @@ func (pq *persistentQueue[T]) restoreQueueSizeFromStorage(ctx context.Context) (
 // The function returns true when an item is consumed or false if the queue is stopped.
 func (pq *persistentQueue[T]) Consume(consumeFunc func(context.Context, T) error) bool {
 	for {
-		var (
-			req                  T
-			onProcessingFinished func(error)
-			consumed             bool
-		)
-
-		// If we are stopped we still process all the other events in the channel before, but we
-		// return fast in the `getNextItem`, so we will free the channel fast and get to the stop.
-		_, ok := pq.sizedChannel.pop(func(permanentQueueEl) int64 {
-			req, onProcessingFinished, consumed = pq.getNextItem(context.Background())
-			if !consumed {
-				return 0
-			}
-			return pq.set.Sizer.Sizeof(req)
-		})
+		consumed, ok := pq.consumeNext(consumeFunc)
 		if !ok {
 			return false
 		}
 		if consumed {
-			onProcessingFinished(consumeFunc(context.Background(), req))
 			return true
 		}
 	}
 }
 
+// consumeNext pops a single element from the sizedChannel and applies the provided function on the matching request.
+// The call blocks until there is an element available or the queue is stopped.
+// It returns consumed=false if the request could not be read from the storage, and ok=false if the queue is stopped
+// and emptied.
+func (pq *persistentQueue[T]) consumeNext(consumeFunc func(context.Context, T) error) (consumed bool, ok bool) {
+	var (
+		reqCtx               context.Context
+		req                  T
+		onProcessingFinished func(error)
+	)
+
+	// If we are stopped we still process all the other events in the channel before, but we
+	// return fast in the `getNextItem`, so we will free the channel fast and get to the stop.
+	_, ok = pq.sizedChannel.pop(func(permanentQueueEl) int64 {
+		reqCtx, req, onProcessingFinished, consumed = pq.getNextItem(context.Background())
+		if !consumed {
+			return 0
+		}
+		return pq.set.Sizer.Sizeof(req)
+	})
+	if ok && consumed {
+		onProcessingFinished(consumeFunc(reqCtx, req))
+	}
+	return consumed, ok
+}
+
 func (pq *persistentQueue[T]) Shutdown(ctx context.Context) error {
 	// If the queue is not initialized, there is nothing to shut down.
 	if pq.client == nil {
@@ -270,7 +299,7 @@ func (pq *persistentQueue[T]) putInternal(ctx context.Context, req T) error {
 		itemKey := getItemKey(pq.writeIndex)
 		newIndex := pq.writeIndex + 1
 
-		reqBuf, err := pq.set.Marshaler(req)
+		reqBuf, err := pq.marshalItem(req)
 		if err != nil {
 			return err
 		}
@@ -280,6 +309,15 @@ func (pq *persistentQueue[T]) putInternal(ctx context.Context, req T) error {
 			storage.SetOperation(writeIndexKey, itemIndexToBytes(newIndex)),
 			storage.SetOperation(itemKey, reqBuf),
 		}
+		if pq.set.Compression.IsCompressed() {
+			ops = append(ops, storage.SetOperation(getItemCompressionKey(pq.writeIndex), []byte(pq.set.Compression)))
+		}
+		if pq.set.Encryptor != nil {
+			ops = append(ops, storage.SetOperation(getItemEncryptionKey(pq.writeIndex), []byte{encryptionFormatVersion}))
+		}
+		if mdBuf := pq.marshalMetadata(ctx); mdBuf != nil {
+			ops = append(ops, storage.SetOperation(getItemMetadataKey(pq.writeIndex), mdBuf))
+		}
 		if storageErr := pq.client.Batch(ctx, ops...); storageErr != nil {
 			return storageErr
 		}
@@ -302,20 +340,69 @@ func (pq *persistentQueue[T]) putInternal(ctx context.Context, req T) error {
 	return nil
 }
 
+// OldestItemTime returns the time the oldest request still waiting in the queue was offered at.
+// The requests restored from the storage are considered offered at start.
+func (pq *persistentQueue[T]) OldestItemTime() (time.Time, bool) {
+	if pq.sizedChannel == nil {
+		return time.Time{}, false
+	}
+	return pq.sizedChannel.oldest()
+}
+
+// Purge deletes all the requests waiting in the queue from the storage.
+func (pq *persistentQueue[T]) Purge(ctx context.Context) (int, error) {
+	return pq.discard(ctx, math.MaxUint64)
+}
+
+// discard deletes up to n requests from the head of the queue from the storage without consuming them,
+// and returns the number of requests deleted. The requests currently dispatched are not affected.
+func (pq *persistentQueue[T]) discard(ctx context.Context, n uint64) (int, error) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	// If the queue is not started or already stopped, the storage cannot be used.
+	if pq.client == nil || pq.stopped {
+		return 0, nil
+	}
+	n = min(n, pq.writeIndex-pq.readIndex)
+	if n == 0 {
+		return 0, nil
+	}
+
+	newIndex := pq.readIndex + n
+	ops := make([]storage.Operation, 0, 4*n+1)
+	for index := pq.readIndex; index < newIndex; index++ {
+		ops = append(ops, storage.DeleteOperation(getItemKey(index)),
+			storage.DeleteOperation(getItemMetadataKey(index)), storage.DeleteOperation(getItemCompressionKey(index)),
+			storage.DeleteOperation(getItemEncryptionKey(index)))
+	}
+	ops = append(ops, storage.SetOperation(readIndexKey, itemIndexToBytes(newIndex)))
+	if err := pq.client.Batch(ctx, ops...); err != nil {
+		return 0, err
+	}
+	pq.readIndex = newIndex
+	// A consumer may have taken an element from the channel already, in which case it finds no request to read.
+	pq.sizedChannel.discard(int(n))
+	if err := pq.backupQueueSize(ctx); err != nil {
+		pq.logger.Error("Error writing queue size to storage", zap.Error(err))
+	}
+	return int(n), nil
+}
+
 // getNextItem pulls the next available item from the persistent storage along with a callback function that should be
 // called after the item is processed to clean up the storage. If no new item is available, returns false.
-func (pq *persistentQueue[T]) getNextItem(ctx context.Context) (T, func(error), bool) {
+// The returned context carries the client metadata stored with the item.
+func (pq *persistentQueue[T]) getNextItem(ctx context.Context) (context.Context, T, func(error), bool) {
 	pq.mu.Lock()
 	defer pq.mu.Unlock()
 
 	var request T
 
 	if pq.stopped {
-		return request, nil, false
+		return ctx, request, nil, false
 	}
 
 	if pq.readIndex == pq.writeIndex {
-		return request, nil, false
+		return ctx, request, nil, false
 	}
 
 	index := pq.readIndex
@@ -323,13 +410,33 @@ func (pq *persistentQueue[T]) getNextItem(ctx context.Context) (T, func(error),
 	pq.readIndex++
 	pq.currentlyDispatchedItems = append(pq.currentlyDispatchedItems, index)
 	getOp := storage.GetOperation(getItemKey(index))
-	err := pq.client.Batch(ctx,
+	// The compression and the encryption are read even if not configured, in case they were before a restart.
+	getCompressionOp := storage.GetOperation(getItemCompressionKey(index))
+	getEncryptionOp := storage.GetOperation(getItemEncryptionKey(index))
+	ops := []storage.Operation{
 		storage.SetOperation(readIndexKey, itemIndexToBytes(pq.readIndex)),
 		storage.SetOperation(currentlyDispatchedItemsKey, itemIndexArrayToBytes(pq.currentlyDispatchedItems)),
-		getOp)
+		getOp,
+		getCompressionOp,
+		getEncryptionOp,
+	}
+	var getMdOp storage.Operation
+	if len(pq.set.MetadataKeys) > 0 {
+		getMdOp = storage.GetOperation(getItemMetadataKey(index))
+		ops = append(ops, getMdOp)
+	}
+	err := pq.client.Batch(ctx, ops...)
 
 	if err == nil {
-		request, err = pq.set.Unmarshaler(getOp.Value)
+		request, err = pq.unmarshalItem(getOp.Value, getCompressionOp.Value, getEncryptionOp.Value)
+		if errors.Is(err, ErrDecryption) {
+			// Not debug level as the data is lost, e.g. if the key it was encrypted with is removed too early.
+			pq.logger.Error("Failed to decrypt item, dropping it", zap.Error(err))
+		}
+	}
+	reqCtx := context.Background()
+	if err == nil && getMdOp != nil {
+		reqCtx = pq.unmarshalMetadata(reqCtx, getMdOp.Value)
 	}
 
 	if err != nil {
@@ -339,13 +446,13 @@ func (pq *persistentQueue[T]) getNextItem(ctx context.Context) (T, func(error),
 			pq.logger.Error("Error deleting item from queue", zap.Error(err))
 		}
 
-		return request, nil, false
+		return ctx, request, nil, false
 	}
 
 	// Increase the reference count, so the client is not closed while the request is being processed.
 	// The client cannot be closed because we hold the lock since last we checked `stopped`.
 	pq.refClient++
-	return request, func(consumeErr error) {
+	return reqCtx, request, func(consumeErr error) {
 		// Delete the item from the persistent storage after it was processed.
 		pq.mu.Lock()
 		// Always unref client even if the consumer is shutdown because we always ref it for every valid request.
@@ -405,13 +512,23 @@ func (pq *persistentQueue[T]) retrieveAndEnqueueNotDispatchedReqs(ctx context.Co
 	pq.logger.Info("Fetching items left for dispatch by consumers", zap.Int(zapNumberOfItems,
 		len(dispatchedItems)))
 	retrieveBatch := make([]storage.Operation, len(dispatchedItems))
-	cleanupBatch := make([]storage.Operation, len(dispatchedItems))
+	retrieveMdBatch := make([]storage.Operation, len(dispatchedItems))
+	retrieveCompressionBatch := make([]storage.Operation, len(dispatchedItems))
+	retrieveEncryptionBatch := make([]storage.Operation, len(dispatchedItems))
+	cleanupBatch := make([]storage.Operation, 0, 4*len(dispatchedItems))
 	for i, it := range dispatchedItems {
 		key := getItemKey(it)
 		retrieveBatch[i] = storage.GetOperation(key)
-		cleanupBatch[i] = storage.DeleteOperation(key)
+		retrieveMdBatch[i] = storage.GetOperation(getItemMetadataKey(it))
+		retrieveCompressionBatch[i] = storage.GetOperation(getItemCompressionKey(it))
+		retrieveEncryptionBatch[i] = storage.GetOperation(getItemEncryptionKey(it))
+		cleanupBatch = append(cleanupBatch, storage.DeleteOperation(key), storage.DeleteOperation(getItemMetadataKey(it)),
+			storage.DeleteOperation(getItemCompressionKey(it)), storage.DeleteOperation(getItemEncryptionKey(it)))
+	}
+	retrieveErr := pq.client.Batch(ctx, append(append(retrieveBatch, retrieveCompressionBatch...), retrieveEncryptionBatch...)...)
+	if retrieveErr == nil && len(pq.set.MetadataKeys) > 0 {
+		retrieveErr = pq.client.Batch(ctx, retrieveMdBatch...)
 	}
-	retrieveErr := pq.client.Batch(ctx, retrieveBatch...)
 	cleanupErr := pq.client.Batch(ctx, cleanupBatch...)
 
 	if cleanupErr != nil {
@@ -424,18 +541,18 @@ func (pq *persistentQueue[T]) retrieveAndEnqueueNotDispatchedReqs(ctx context.Co
 	}
 
 	errCount := 0
-	for _, op := range retrieveBatch {
+	for i, op := range retrieveBatch {
 		if op.Value == nil {
 			pq.logger.Warn("Failed retrieving item", zap.String(zapKey, op.Key), zap.Error(errValueNotSet))
 			continue
 		}
-		req, err := pq.set.Unmarshaler(op.Value)
+		req, err := pq.unmarshalItem(op.Value, retrieveCompressionBatch[i].Value, retrieveEncryptionBatch[i].Value)
 		// If error happened or item is nil, it will be efficiently ignored
 		if err != nil {
 			pq.logger.Warn("Failed unmarshalling item", zap.String(zapKey, op.Key), zap.Error(err))
 			continue
 		}
-		if pq.putInternal(ctx, req) != nil {
+		if pq.putInternal(pq.unmarshalMetadata(ctx, retrieveMdBatch[i].Value), req) != nil {
 			errCount++
 		}
 	}
@@ -462,7 +579,12 @@ func (pq *persistentQueue[T]) itemDispatchingFinish(ctx context.Context, index u
 
 	setOp := storage.SetOperation(currentlyDispatchedItemsKey, itemIndexArrayToBytes(pq.currentlyDispatchedItems))
 	deleteOp := storage.DeleteOperation(getItemKey(index))
-	if err := pq.client.Batch(ctx, setOp, deleteOp); err != nil {
+	// The metadata, compression and encryption keys are deleted even if not configured, in case they were before
+	// a restart.
+	deleteMdOp := storage.DeleteOperation(getItemMetadataKey(index))
+	deleteCompressionOp := storage.DeleteOperation(getItemCompressionKey(index))
+	deleteEncryptionOp := storage.DeleteOperation(getItemEncryptionKey(index))
+	if err := pq.client.Batch(ctx, setOp, deleteOp, deleteMdOp, deleteCompressionOp, deleteEncryptionOp); err != nil {
 		// got an error, try to gracefully handle it
 		pq.logger.Warn("Failed updating currently dispatched items, trying to delete the item first",
 			zap.Error(err))
@@ -471,7 +593,7 @@ func (pq *persistentQueue[T]) itemDispatchingFinish(ctx context.Context, index u
 		return nil
 	}
 
-	if err := pq.client.Batch(ctx, deleteOp); err != nil {
+	if err := pq.client.Batch(ctx, deleteOp, deleteMdOp, deleteCompressionOp, deleteEncryptionOp); err != nil {
 		// Return an error here, as this indicates an issue with the underlying storage medium
 		return fmt.Errorf("failed deleting item from queue, got error from storage: %w", err)
 	}
@@ -503,6 +625,100 @@ func getItemKey(index uint64) string {
 	return strconv.FormatUint(index, 10)
 }
 
+// getItemMetadataKey returns the key of the client metadata stored along with the item at the given index.
+func getItemMetadataKey(index uint64) string {
+	return "md_" + strconv.FormatUint(index, 10)
+}
+
+// getItemCompressionKey returns the key of the compression algorithm of the item at the given index.
+func getItemCompressionKey(index uint64) string {
+	return "c_" + strconv.FormatUint(index, 10)
+}
+
+// getItemEncryptionKey returns the key of the marker stored along with the item at the given index if it's encrypted.
+func getItemEncryptionKey(index uint64) string {
+	return "e_" + strconv.FormatUint(index, 10)
+}
+
+// marshalItem marshals the request, then compresses and encrypts it if configured.
+func (pq *persistentQueue[T]) marshalItem(req T) ([]byte, error) {
+	buf, err := pq.set.Marshaler(req)
+	if err != nil {
+		return nil, err
+	}
+	if pq.set.Compression.IsCompressed() {
+		if buf, err = compress(pq.set.Compression, buf); err != nil {
+			return nil, fmt.Errorf("failed to compress the item: %w", err)
+		}
+	}
+	if pq.set.Encryptor != nil {
+		return pq.set.Encryptor.Encrypt(buf)
+	}
+	return buf, nil
+}
+
+// unmarshalItem reverses marshalItem, given the compression algorithm and the encryption marker stored with the item,
+// if any. The items are decrypted based on their marker rather than on the current configuration, so the encryption
+// can be enabled without losing the items already stored.
+func (pq *persistentQueue[T]) unmarshalItem(buf []byte, compression []byte, encryption []byte) (T, error) {
+	var err error
+	if encryption != nil {
+		if pq.set.Encryptor == nil {
+			var req T
+			return req, fmt.Errorf("%w: the item is encrypted, but the encryption is disabled", ErrDecryption)
+		}
+		if buf, err = pq.set.Encryptor.Decrypt(buf); err != nil {
+			var req T
+			return req, err
+		}
+	}
+	if compression != nil {
+		if buf, err = decompress(configcompression.Type(compression), buf); err != nil {
+			var req T
+			return req, fmt.Errorf("failed to decompress the item with %q: %w", compression, err)
+		}
+	}
+	return pq.set.Unmarshaler(buf)
+}
+
+// marshalMetadata returns the configured client metadata keys found in the context, encoded as JSON,
+// or nil if there are none.
+func (pq *persistentQueue[T]) marshalMetadata(ctx context.Context) []byte {
+	if len(pq.set.MetadataKeys) == 0 {
+		return nil
+	}
+	info := client.FromContext(ctx)
+	md := make(map[string][]string, len(pq.set.MetadataKeys))
+	for _, k := range pq.set.MetadataKeys {
+		if v := info.Metadata.Get(k); len(v) > 0 {
+			md[k] = v
+		}
+	}
+	if len(md) == 0 {
+		return nil
+	}
+	buf, err := json.Marshal(md)
+	if err != nil {
+		pq.logger.Warn("Failed to marshal the client metadata, storing the request without it", zap.Error(err))
+		return nil
+	}
+	return buf
+}
+
+// unmarshalMetadata returns a context carrying the client metadata decoded from the given buffer.
+// If the buffer is empty or cannot be decoded, the context is returned unchanged.
+func (pq *persistentQueue[T]) unmarshalMetadata(ctx context.Context, buf []byte) context.Context {
+	if len(buf) == 0 {
+		return ctx
+	}
+	var md map[string][]string
+	if err := json.Unmarshal(buf, &md); err != nil {
+		pq.logger.Warn("Failed to unmarshal the client metadata, consuming the request without it", zap.Error(err))
+		return ctx
+	}
+	return client.NewContext(ctx, client.Info{Metadata: client.NewMetadata(md)})
+}
+
 func itemIndexToBytes(value uint64) []byte {
 	return binary.LittleEndian.AppendUint64([]byte{}, value)
 }
@@ -4,9 +4,11 @@
 package queue
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"sync/atomic"
 	"syscall"
@@ -15,8 +17,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/exporter/internal/experr"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
@@ -33,6 +39,11 @@ func (tr tracesRequest) ItemsCount() int {
 	return tr.traces.SpanCount()
 }
 
+func (tr tracesRequest) BytesSize() int {
+	marshaler := &ptrace.ProtoMarshaler{}
+	return marshaler.TracesSize(tr.traces)
+}
+
 func marshalTracesRequest(tr tracesRequest) ([]byte, error) {
 	marshaler := &ptrace.ProtoMarshaler{}
 	return marshaler.MarshalTraces(tr.traces)
@@ -132,6 +143,12 @@ func TestPersistentQueue_FullCapacity(t *testing.T) {
 			capacity:       55,
 			sizeMultiplier: 10,
 		},
+		{
+			name:           "bytes_capacity",
+			sizer:          &BytesSizer[tracesRequest]{},
+			capacity:       int64(newTracesRequest(1, 10).BytesSize()*11) / 2,
+			sizeMultiplier: newTracesRequest(1, 10).BytesSize(),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -461,13 +478,13 @@ func TestPersistentQueue_CurrentlyProcessedItems(t *testing.T) {
 	requireCurrentlyDispatchedItemsEqual(t, ps, []uint64{})
 
 	// Takes index 0 in process.
-	readReq, _, found := ps.getNextItem(context.Background())
+	_, readReq, _, found := ps.getNextItem(context.Background())
 	require.True(t, found)
 	assert.Equal(t, req, readReq)
 	requireCurrentlyDispatchedItemsEqual(t, ps, []uint64{0})
 
 	// This takes item 1 to process.
-	secondReadReq, onProcessingFinished, found := ps.getNextItem(context.Background())
+	_, secondReadReq, onProcessingFinished, found := ps.getNextItem(context.Background())
 	require.True(t, found)
 	assert.Equal(t, req, secondReadReq)
 	requireCurrentlyDispatchedItemsEqual(t, ps, []uint64{0, 1})
@@ -504,6 +521,50 @@ func TestPersistentQueue_CurrentlyProcessedItems(t *testing.T) {
 	}
 }
 
+func TestPersistentQueue_ConsumersStopped(t *testing.T) {
+	req := newTracesRequest(5, 10)
+	ext := NewMockStorageExtension(nil)
+	ps := NewPersistentQueue[tracesRequest](PersistentQueueSettings[tracesRequest]{
+		Sizer:            &RequestSizer[tracesRequest]{},
+		Capacity:         1000,
+		DataType:         component.DataTypeTraces,
+		StorageID:        component.ID{},
+		Marshaler:        marshalTracesRequest,
+		Unmarshaler:      unmarshalTracesRequest,
+		ExporterSettings: exportertest.NewNopSettings(),
+	}).(*persistentQueue[tracesRequest])
+
+	consuming := make(chan struct{})
+	release := make(chan struct{})
+	consumed := &atomic.Int64{}
+	consumers := NewQueueConsumers[tracesRequest](ps, 1, func(context.Context, tracesRequest) error {
+		if consumed.Add(1) == 1 {
+			close(consuming)
+		}
+		<-release
+		return experr.NewShutdownErr(errors.New("stopped"))
+	})
+	require.NoError(t, consumers.Start(context.Background(), &mockHost{ext: map[component.ID]component.Component{{}: ext}}))
+	for i := 0; i < 5; i++ {
+		require.NoError(t, ps.Offer(context.Background(), req))
+	}
+	<-consuming
+
+	// The request taken is kept dispatched, the others are not read from the storage anymore.
+	consumers.Stop()
+	close(release)
+	assert.Never(t, func() bool { return consumed.Load() > 1 }, 50*time.Millisecond, 5*time.Millisecond)
+	require.NoError(t, consumers.Shutdown(context.Background()))
+	requireCurrentlyDispatchedItemsEqual(t, ps, []uint64{0})
+	assert.EqualValues(t, 1, ps.readIndex)
+
+	// After restart, the requests left keep their order, followed by the one being dispatched.
+	newPs := createTestPersistentQueueWithRequestsCapacity(t, ext, 1000)
+	assert.Equal(t, 5, newPs.Size())
+	assert.EqualValues(t, 1, newPs.readIndex)
+	assert.NoError(t, newPs.Shutdown(context.Background()))
+}
+
 // this test attempts to check if all the invariants are kept if the queue is recreated while
 // close to full and with some items dispatched
 func TestPersistentQueueStartWithNonDispatched(t *testing.T) {
@@ -542,7 +603,7 @@ func TestPersistentQueue_PutCloseReadClose(t *testing.T) {
 	assert.NoError(t, ps.Offer(context.Background(), req))
 	assert.Equal(t, 2, ps.Size())
 	// TODO: Remove this, after the initialization writes the readIndex.
-	_, _, _ = ps.getNextItem(context.Background())
+	_, _, _, _ = ps.getNextItem(context.Background())
 	assert.NoError(t, ps.Shutdown(context.Background()))
 
 	newPs := createTestPersistentQueueWithRequestsCapacity(t, ext, 1000)
@@ -563,6 +624,262 @@ func TestPersistentQueue_PutCloseReadClose(t *testing.T) {
 	assert.NoError(t, newPs.Shutdown(context.Background()))
 }
 
+func TestPersistentQueue_PutCloseReadCloseWithoutRead(t *testing.T) {
+	req := newTracesRequest(5, 10)
+	ext := NewMockStorageExtension(nil)
+	ps := createTestPersistentQueueWithRequestsCapacity(t, ext, 1000)
+	assert.NoError(t, ps.Offer(context.Background(), req))
+	assert.NoError(t, ps.Offer(context.Background(), req))
+	assert.NoError(t, ps.Shutdown(context.Background()))
+
+	// The read index has never been written, the items must still be restored.
+	newPs := createTestPersistentQueueWithRequestsCapacity(t, ext, 1000)
+	require.Equal(t, 2, newPs.Size())
+	assert.NoError(t, newPs.Shutdown(context.Background()))
+}
+
+func TestPersistentQueue_MetadataKeys(t *testing.T) {
+	req := newTracesRequest(1, 10)
+	ext := NewMockStorageExtension(nil)
+	newQueue := func() *persistentQueue[tracesRequest] {
+		pq := NewPersistentQueue[tracesRequest](PersistentQueueSettings[tracesRequest]{
+			Sizer:            &RequestSizer[tracesRequest]{},
+			Capacity:         1000,
+			DataType:         component.DataTypeTraces,
+			StorageID:        component.ID{},
+			Marshaler:        marshalTracesRequest,
+			Unmarshaler:      unmarshalTracesRequest,
+			ExporterSettings: exportertest.NewNopSettings(),
+			MetadataKeys:     []string{"X-Tenant", "X-Region"},
+		}).(*persistentQueue[tracesRequest])
+		require.NoError(t, pq.Start(context.Background(), &mockHost{ext: map[component.ID]component.Component{{}: ext}}))
+		return pq
+	}
+
+	ps := newQueue()
+	ctx := client.NewContext(context.Background(), client.Info{
+		Metadata: client.NewMetadata(map[string][]string{"x-tenant": {"acme"}, "Authorization": {"secret"}}),
+	})
+	require.NoError(t, ps.Offer(ctx, req))
+	require.NoError(t, ps.Offer(context.Background(), req))
+
+	// The first request is being dispatched when the queue is shut down, it must be restored with its metadata.
+	require.True(t, ps.Consume(func(ctx context.Context, _ tracesRequest) error {
+		assert.Equal(t, []string{"acme"}, client.FromContext(ctx).Metadata.Get("X-Tenant"))
+		return experr.NewShutdownErr(nil)
+	}))
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	ps = newQueue()
+	require.Equal(t, 2, ps.Size())
+	var consumed []client.Metadata
+	for i := 0; i < 2; i++ {
+		require.True(t, ps.Consume(func(ctx context.Context, _ tracesRequest) error {
+			consumed = append(consumed, client.FromContext(ctx).Metadata)
+			return nil
+		}))
+	}
+	// The restored request is put back after the request that was not dispatched.
+	assert.Empty(t, consumed[0].Get("X-Tenant"))
+	assert.Equal(t, []string{"acme"}, consumed[1].Get("X-Tenant"))
+	assert.Empty(t, consumed[1].Get("X-Region"))
+	// The keys not configured are not stored.
+	assert.Empty(t, consumed[1].Get("Authorization"))
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	// The metadata is deleted from the storage along with the requests.
+	ext.(*mockStorageExtension).st.Range(func(key, _ any) bool {
+		assert.NotContains(t, key, "md_")
+		return true
+	})
+}
+
+func TestPersistentQueue_Compression(t *testing.T) {
+	req := newTracesRequest(10, 10)
+	plain, err := marshalTracesRequest(req)
+	require.NoError(t, err)
+	ext := NewMockStorageExtension(nil)
+	newQueue := func(compression configcompression.Type) *persistentQueue[tracesRequest] {
+		pq := NewPersistentQueue[tracesRequest](PersistentQueueSettings[tracesRequest]{
+			Sizer:            &RequestSizer[tracesRequest]{},
+			Capacity:         1000,
+			DataType:         component.DataTypeTraces,
+			StorageID:        component.ID{},
+			Marshaler:        marshalTracesRequest,
+			Unmarshaler:      unmarshalTracesRequest,
+			ExporterSettings: exportertest.NewNopSettings(),
+			Compression:      compression,
+		}).(*persistentQueue[tracesRequest])
+		require.NoError(t, pq.Start(context.Background(), &mockHost{ext: map[component.ID]component.Component{{}: ext}}))
+		return pq
+	}
+
+	// The compression is changed on every restart, the requests stored before are still read.
+	ps := newQueue("")
+	require.NoError(t, ps.Offer(context.Background(), req))
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	ps = newQueue(configcompression.TypeZstd)
+	require.NoError(t, ps.Offer(context.Background(), req))
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	ps = newQueue(configcompression.TypeGzip)
+	require.NoError(t, ps.Offer(context.Background(), req))
+	// The request being dispatched on shutdown is restored with the compression it was stored with.
+	require.True(t, ps.Consume(func(context.Context, tracesRequest) error {
+		return experr.NewShutdownErr(nil)
+	}))
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	var sizes []int
+	ext.(*mockStorageExtension).st.Range(func(key, value any) bool {
+		if _, err := strconv.ParseUint(key.(string), 10, 64); err == nil {
+			sizes = append(sizes, len(value.([]byte)))
+		}
+		return true
+	})
+	require.Len(t, sizes, 3)
+	slices.Sort(sizes)
+	assert.Less(t, sizes[0], len(plain))
+	assert.Less(t, sizes[1], len(plain))
+	assert.Equal(t, len(plain), sizes[2])
+
+	ps = newQueue("none")
+	require.Equal(t, 3, ps.Size())
+	for i := 0; i < 3; i++ {
+		require.True(t, ps.Consume(func(_ context.Context, got tracesRequest) error {
+			assert.Equal(t, req, got)
+			return nil
+		}))
+	}
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	// The compression is deleted from the storage along with the requests.
+	ext.(*mockStorageExtension).st.Range(func(key, _ any) bool {
+		assert.NotContains(t, key, "c_")
+		return true
+	})
+}
+
+func TestPersistentQueue_Encryption(t *testing.T) {
+	req := newTracesRequest(1, 10)
+	plain, err := marshalTracesRequest(req)
+	require.NoError(t, err)
+	ext := NewMockStorageExtension(nil)
+	newQueue := func(key []byte, logger *zap.Logger) *persistentQueue[tracesRequest] {
+		enc, err := NewEncryptor(map[string][]byte{"k1": key}, "k1")
+		require.NoError(t, err)
+		set := exportertest.NewNopSettings()
+		set.Logger = logger
+		pq := NewPersistentQueue[tracesRequest](PersistentQueueSettings[tracesRequest]{
+			Sizer:            &RequestSizer[tracesRequest]{},
+			Capacity:         1000,
+			DataType:         component.DataTypeTraces,
+			StorageID:        component.ID{},
+			Marshaler:        marshalTracesRequest,
+			Unmarshaler:      unmarshalTracesRequest,
+			ExporterSettings: set,
+			Encryptor:        enc,
+		}).(*persistentQueue[tracesRequest])
+		require.NoError(t, pq.Start(context.Background(), &mockHost{ext: map[component.ID]component.Component{{}: ext}}))
+		return pq
+	}
+
+	ps := newQueue(bytes.Repeat([]byte{1}, 32), zap.NewNop())
+	require.NoError(t, ps.Offer(context.Background(), req))
+	require.NoError(t, ps.Offer(context.Background(), req))
+	require.True(t, ps.Consume(func(_ context.Context, got tracesRequest) error {
+		assert.Equal(t, req, got)
+		return nil
+	}))
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	// The request is not stored in clear.
+	ext.(*mockStorageExtension).st.Range(func(_, value any) bool {
+		assert.NotContains(t, string(value.([]byte)), string(plain))
+		return true
+	})
+
+	// The request can't be decrypted with another key, it's dropped and the error is logged.
+	core, observed := observer.New(zap.ErrorLevel)
+	ps = newQueue(bytes.Repeat([]byte{2}, 32), zap.New(core))
+	require.Equal(t, 1, ps.Size())
+	consumed := make(chan struct{})
+	go func() {
+		ps.Consume(func(context.Context, tracesRequest) error { return nil })
+		close(consumed)
+	}()
+	assert.Eventually(t, func() bool {
+		return observed.FilterMessage("Failed to decrypt item, dropping it").Len() == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, ps.Shutdown(context.Background()))
+	<-consumed
+}
+
+func TestPersistentQueue_EncryptionToggle(t *testing.T) {
+	req := newTracesRequest(1, 10)
+	ext := NewMockStorageExtension(nil)
+	newQueue := func(encrypted bool, logger *zap.Logger) *persistentQueue[tracesRequest] {
+		set := exportertest.NewNopSettings()
+		set.Logger = logger
+		pqSet := PersistentQueueSettings[tracesRequest]{
+			Sizer:            &RequestSizer[tracesRequest]{},
+			Capacity:         1000,
+			DataType:         component.DataTypeTraces,
+			StorageID:        component.ID{},
+			Marshaler:        marshalTracesRequest,
+			Unmarshaler:      unmarshalTracesRequest,
+			ExporterSettings: set,
+		}
+		if encrypted {
+			enc, err := NewEncryptor(map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}, "k1")
+			require.NoError(t, err)
+			pqSet.Encryptor = enc
+		}
+		pq := NewPersistentQueue[tracesRequest](pqSet).(*persistentQueue[tracesRequest])
+		require.NoError(t, pq.Start(context.Background(), &mockHost{ext: map[component.ID]component.Component{{}: ext}}))
+		return pq
+	}
+
+	ps := newQueue(false, zap.NewNop())
+	require.NoError(t, ps.Offer(context.Background(), req))
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	// The request stored before the encryption is enabled is still read.
+	ps = newQueue(true, zap.NewNop())
+	require.True(t, ps.Consume(func(_ context.Context, got tracesRequest) error {
+		assert.Equal(t, req, got)
+		return nil
+	}))
+	require.NoError(t, ps.Offer(context.Background(), req))
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	// The request stored while the encryption is enabled cannot be read once it's disabled, it's dropped rather than
+	// given encrypted to the unmarshaler.
+	core, observed := observer.New(zap.ErrorLevel)
+	ps = newQueue(false, zap.New(core))
+	require.Equal(t, 1, ps.Size())
+	consumed := make(chan struct{})
+	go func() {
+		ps.Consume(func(context.Context, tracesRequest) error {
+			assert.Fail(t, "the encrypted request must not be consumed")
+			return nil
+		})
+		close(consumed)
+	}()
+	assert.Eventually(t, func() bool {
+		return observed.FilterMessage("Failed to decrypt item, dropping it").Len() == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, ps.Shutdown(context.Background()))
+	<-consumed
+
+	// The encryption marker is deleted from the storage along with the requests.
+	ext.(*mockStorageExtension).st.Range(func(key, _ any) bool {
+		assert.NotContains(t, key, "e_")
+		return true
+	})
+}
+
 func BenchmarkPersistentQueue_TraceSpans(b *testing.B) {
 	cases := []struct {
 		numTraces        int
@@ -670,7 +987,7 @@ func TestPersistentQueue_ShutdownWhileConsuming(t *testing.T) {
 
 	assert.NoError(t, ps.Offer(context.Background(), newTracesRequest(5, 10)))
 
-	_, onProcessingFinished, ok := ps.getNextItem(context.Background())
+	_, _, onProcessingFinished, ok := ps.getNextItem(context.Background())
 	require.True(t, ok)
 	assert.False(t, ps.client.(*mockStorageClient).isClosed())
 	assert.NoError(t, ps.Shutdown(context.Background()))
@@ -978,3 +1295,54 @@ func requireCurrentlyDispatchedItemsEqual(t *testing.T, pq *persistentQueue[trac
 	defer pq.mu.Unlock()
 	assert.ElementsMatch(t, compare, pq.currentlyDispatchedItems)
 }
+
+func TestPersistentQueue_Purge(t *testing.T) {
+	ext := NewMockStorageExtension(nil)
+	ps := createTestPersistentQueueWithItemsCapacity(t, ext, 1000)
+	assert.Equal(t, "persistent", Kind[tracesRequest](ps))
+	_, ok := ps.OldestItemTime()
+	assert.False(t, ok)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, ps.Offer(context.Background(), newTracesRequest(1, 10)))
+	}
+	_, ok = ps.OldestItemTime()
+	assert.True(t, ok)
+
+	// The request being consumed is not purged.
+	var processingFinished func(error)
+	_, ok = ps.sizedChannel.pop(func(permanentQueueEl) int64 {
+		_, _, processingFinished, ok = ps.getNextItem(context.Background())
+		require.True(t, ok)
+		return 10
+	})
+	require.True(t, ok)
+
+	n, err := ps.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, 0, ps.Size())
+	assert.Equal(t, ps.writeIndex, ps.readIndex)
+	requireCurrentlyDispatchedItemsEqual(t, ps, []uint64{0})
+	for i := uint64(1); i < 5; i++ {
+		val, getErr := ps.client.Get(context.Background(), getItemKey(i))
+		require.NoError(t, getErr)
+		assert.Nil(t, val)
+	}
+	processingFinished(nil)
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	// The purged requests are not restored after restart.
+	ps = createTestPersistentQueueWithItemsCapacity(t, ext, 1000)
+	assert.Equal(t, 0, ps.Size())
+	require.NoError(t, ps.Offer(context.Background(), newTracesRequest(1, 3)))
+	assert.True(t, ps.Consume(func(_ context.Context, req tracesRequest) error {
+		assert.Equal(t, 3, req.traces.SpanCount())
+		return nil
+	}))
+	require.NoError(t, ps.Shutdown(context.Background()))
+
+	n, err = ps.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
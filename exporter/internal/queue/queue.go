@@ -8,6 +8,7 @@ package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 )
@@ -35,6 +36,60 @@ type Queue[T any] interface {
 	Capacity() int
 }
 
+// Inspectable is implemented by the queues that can report the age of their requests and be purged on demand.
+type Inspectable interface {
+	// OldestItemTime returns the time the oldest request still waiting in the queue was offered at,
+	// or false if no request is waiting.
+	OldestItemTime() (time.Time, bool)
+	// Purge removes all the requests waiting in the queue without consuming them, and returns how many were removed.
+	// The requests being consumed are not affected.
+	Purge(ctx context.Context) (int, error)
+}
+
+// Kind returns the kind of the queue as shown to the users: "memory", "persistent", "spill_over",
+// or "custom" for the queues implemented outside of this package.
+func Kind[T any](q Queue[T]) string {
+	switch q.(type) {
+	case *boundedMemoryQueue[T]:
+		return "memory"
+	case *persistentQueue[T]:
+		return "persistent"
+	case *hybridQueue[T]:
+		return "spill_over"
+	default:
+		return "custom"
+	}
+}
+
+// IsPersistent reports whether the queue keeps the requests in the persistent storage when they fail because of the
+// shutdown, so they are sent after restart: the persistent queue, and the spill over queue which writes them to it.
+func IsPersistent[T any](q Queue[T]) bool {
+	switch q.(type) {
+	case *persistentQueue[T], *hybridQueue[T]:
+		return true
+	default:
+		return false
+	}
+}
+
+// Length returns the number of requests waiting in the queue, regardless of how the queue is sized, or false for the
+// queues implemented outside of this package.
+func Length[T any](q Queue[T]) (int, bool) {
+	switch q := q.(type) {
+	case *boundedMemoryQueue[T]:
+		return q.length(), true
+	case *persistentQueue[T]:
+		if q.sizedChannel == nil {
+			return 0, true
+		}
+		return q.length(), true
+	case *hybridQueue[T]:
+		return q.mem.length(), true
+	default:
+		return 0, false
+	}
+}
+
 type itemsCounter interface {
 	ItemsCount() int
 }
@@ -57,3 +112,19 @@ type RequestSizer[T any] struct{}
 func (rs *RequestSizer[T]) Sizeof(T) int64 {
 	return 1
 }
+
+type bytesCounter interface {
+	BytesSize() int
+}
+
+// BytesSizer is a Sizer implementation that returns the size of a queue element as its serialized size in bytes.
+// Elements that don't implement the BytesSize() int method are accounted as zero bytes, so the queues sized in bytes
+// must reject them before they are offered.
+type BytesSizer[T any] struct{}
+
+func (bs *BytesSizer[T]) Sizeof(el T) int64 {
+	if bc, ok := any(el).(bytesCounter); ok {
+		return int64(bc.BytesSize())
+	}
+	return 0
+}
@@ -3,47 +3,60 @@
 
 package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
 
-import "sync/atomic"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
-// sizedChannel is a channel wrapper for sized elements with a capacity set to a total size of all the elements.
+// sizedChannel is a channel-like FIFO for sized elements with a capacity set to a total size of all the elements.
 // The channel will accept elements until the total size of the elements reaches the capacity.
+//
+// Unlike a Go channel, the buffer is not pre-allocated for the capacity. This matters when the capacity is expressed
+// in items or bytes, where it can be orders of magnitude higher than the number of elements actually queued.
 type sizedChannel[T any] struct {
 	used *atomic.Int64
 
-	// We need to store the capacity in a separate field because the capacity of the channel can be higher.
+	// We need to store the capacity in a separate field because the number of elements can be higher.
 	// It happens when we restore a persistent queue from a disk that is bigger than the pre-configured capacity.
 	cap int64
-	ch  chan T
+
+	// mu guards everything declared below.
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	els      []T
+	// pushedAt holds the time every element in els was pushed at, in the same order.
+	pushedAt []time.Time
+	stopped  bool
 }
 
 // newSizedChannel creates a sized elements channel. Each element is assigned a size by the provided sizer.
-// chanCapacity is the capacity of the underlying channel which usually should be equal to the capacity of the queue to
-// avoid blocking the producer. Optionally, the channel can be preloaded with the elements and their total size.
+// Optionally, the channel can be preloaded with the elements and their total size. The preloaded elements are
+// considered pushed at the time of the call.
 func newSizedChannel[T any](capacity int64, els []T, totalSize int64) *sizedChannel[T] {
 	used := &atomic.Int64{}
 	used.Store(totalSize)
 
-	chCap := capacity
-	if chCap < int64(len(els)) {
-		chCap = int64(len(els))
-	}
-
-	ch := make(chan T, chCap)
-	for _, el := range els {
-		ch <- el
+	now := time.Now()
+	pushedAt := make([]time.Time, len(els))
+	for i := range pushedAt {
+		pushedAt[i] = now
 	}
-
-	return &sizedChannel[T]{
-		used: used,
-		cap:  capacity,
-		ch:   ch,
+	vcq := &sizedChannel[T]{
+		used:     used,
+		cap:      capacity,
+		els:      append([]T(nil), els...),
+		pushedAt: pushedAt,
 	}
+	vcq.notEmpty = sync.NewCond(&vcq.mu)
+	return vcq
 }
 
 // push puts the element into the queue with the given sized if there is enough capacity.
 // Returns an error if the queue is full. The callback is called before the element is committed to the queue.
 // If the callback returns an error, the element is not put into the queue and the error is returned.
 // The size is the size of the element MUST be positive.
+// Calling this method on a stopped queue will panic.
 func (vcq *sizedChannel[T]) push(el T, size int64, callback func() error) error {
 	if vcq.used.Add(size) > vcq.cap {
 		vcq.used.Add(-size)
@@ -55,7 +68,15 @@ func (vcq *sizedChannel[T]) push(el T, size int64, callback func() error) error
 			return err
 		}
 	}
-	vcq.ch <- el
+
+	vcq.mu.Lock()
+	defer vcq.mu.Unlock()
+	if vcq.stopped {
+		panic("push called on a stopped queue")
+	}
+	vcq.els = append(vcq.els, el)
+	vcq.pushedAt = append(vcq.pushedAt, time.Now())
+	vcq.notEmpty.Signal()
 	return nil
 }
 
@@ -64,10 +85,22 @@ func (vcq *sizedChannel[T]) push(el T, size int64, callback func() error) error
 // The function returns true when an item is consumed or false if the queue is stopped and emptied.
 // The callback is called before the element is removed from the queue. It must return the size of the element.
 func (vcq *sizedChannel[T]) pop(callback func(T) (size int64)) (T, bool) {
-	el, ok := <-vcq.ch
-	if !ok {
+	vcq.mu.Lock()
+	for len(vcq.els) == 0 && !vcq.stopped {
+		vcq.notEmpty.Wait()
+	}
+	var el T
+	if len(vcq.els) == 0 {
+		vcq.mu.Unlock()
 		return el, false
 	}
+	el = vcq.els[0]
+	// Release the reference to the element, so it can be garbage collected once processed.
+	var zero T
+	vcq.els[0] = zero
+	vcq.els = vcq.els[1:]
+	vcq.pushedAt = vcq.pushedAt[1:]
+	vcq.mu.Unlock()
 
 	size := callback(el)
 
@@ -85,14 +118,72 @@ func (vcq *sizedChannel[T]) pop(callback func(T) (size int64)) (T, bool) {
 // It's used by the persistent queue to ensure the used value correctly reflects the reality which may not be always
 // the case in case if the queue size is restored from the disk after a crash.
 func (vcq *sizedChannel[T]) syncSize() {
-	if len(vcq.ch) == 0 {
+	vcq.mu.Lock()
+	defer vcq.mu.Unlock()
+	if len(vcq.els) == 0 {
 		vcq.used.Store(0)
 	}
 }
 
-// shutdown closes the queue channel to initiate draining of the queue.
+// shutdown stops the queue to initiate draining of the queue.
+// The elements already in the queue are still returned by pop.
 func (vcq *sizedChannel[T]) shutdown() {
-	close(vcq.ch)
+	vcq.mu.Lock()
+	defer vcq.mu.Unlock()
+	vcq.stopped = true
+	vcq.notEmpty.Broadcast()
+}
+
+// drain removes all the elements from the queue and returns them. It's intended to be called on a stopped queue
+// to move the elements that haven't been consumed yet somewhere else.
+func (vcq *sizedChannel[T]) drain() []T {
+	vcq.mu.Lock()
+	defer vcq.mu.Unlock()
+	els := vcq.els
+	vcq.els = nil
+	vcq.pushedAt = nil
+	vcq.used.Store(0)
+	return els
+}
+
+// discard removes up to n elements from the head of the queue without consuming them, and returns the number of
+// elements removed. The size of the discarded elements is unknown, so the used size is reduced proportionally to
+// the number of elements left, and reset once the queue is empty.
+func (vcq *sizedChannel[T]) discard(n int) int {
+	vcq.mu.Lock()
+	defer vcq.mu.Unlock()
+	n = min(n, len(vcq.els))
+	if n == 0 {
+		return 0
+	}
+	left := len(vcq.els) - n
+	vcq.els = append([]T(nil), vcq.els[n:]...)
+	vcq.pushedAt = append([]time.Time(nil), vcq.pushedAt[n:]...)
+	if left == 0 {
+		vcq.used.Store(0)
+		return n
+	}
+	if vcq.used.Add(-vcq.used.Load()*int64(n)/int64(left+n)) < 0 {
+		vcq.used.Store(0)
+	}
+	return n
+}
+
+// oldest returns the time the element at the head of the queue was pushed at, or false if the queue is empty.
+func (vcq *sizedChannel[T]) oldest() (time.Time, bool) {
+	vcq.mu.Lock()
+	defer vcq.mu.Unlock()
+	if len(vcq.pushedAt) == 0 {
+		return time.Time{}, false
+	}
+	return vcq.pushedAt[0], true
+}
+
+// length returns the number of elements in the queue.
+func (vcq *sizedChannel[T]) length() int {
+	vcq.mu.Lock()
+	defer vcq.mu.Unlock()
+	return len(vcq.els)
 }
 
 func (vcq *sizedChannel[T]) Size() int {
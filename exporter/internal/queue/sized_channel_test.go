@@ -6,6 +6,7 @@ package queue
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -42,3 +43,31 @@ func TestSizedCapacityChannel(t *testing.T) {
 	assert.False(t, ok)
 	assert.Equal(t, 0, el)
 }
+
+func TestSizedChannel_OldestAndDiscard(t *testing.T) {
+	q := newSizedChannel[int](10, nil, 0)
+	_, ok := q.oldest()
+	assert.False(t, ok)
+
+	before := time.Now()
+	for i := 1; i <= 4; i++ {
+		assert.NoError(t, q.push(i, 2, nil))
+	}
+	oldest, ok := q.oldest()
+	assert.True(t, ok)
+	assert.False(t, oldest.Before(before))
+
+	// The size of the discarded elements is estimated from the number of elements left.
+	assert.Equal(t, 1, q.discard(1))
+	assert.Equal(t, 6, q.Size())
+	assert.Equal(t, 3, q.length())
+
+	el, ok := q.pop(func(el int) int64 { return 2 })
+	assert.True(t, ok)
+	assert.Equal(t, 2, el)
+
+	assert.Equal(t, 2, q.discard(5))
+	assert.Equal(t, 0, q.Size())
+	_, ok = q.oldest()
+	assert.False(t, ok)
+}
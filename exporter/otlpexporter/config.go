@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
@@ -19,15 +20,53 @@ import (
 
 // Config defines configuration for OTLP exporter.
 type Config struct {
-	exporterhelper.TimeoutSettings `mapstructure:",squash"`     // squash ensures fields are correctly decoded in embedded struct.
-	QueueConfig                    exporterhelper.QueueSettings `mapstructure:"sending_queue"`
-	RetryConfig                    configretry.BackOffConfig    `mapstructure:"retry_on_failure"`
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`              // squash ensures fields are correctly decoded in embedded struct.
+	QueueConfig                    exporterhelper.QueueSettings          `mapstructure:"sending_queue"`
+	RetryConfig                    configretry.BackOffConfig             `mapstructure:"retry_on_failure"`
+	DeadLetterConfig               exporterhelper.DeadLetterSettings     `mapstructure:"dead_letter"`
+	CircuitBreakerConfig           exporterhelper.CircuitBreakerSettings `mapstructure:"circuit_breaker"`
+	RateLimitConfig                exporterhelper.RateLimitSettings      `mapstructure:"rate_limit"`
+	HedgingConfig                  exporterhelper.HedgingSettings        `mapstructure:"hedging"`
+	FailoverConfig                 FailoverConfig                        `mapstructure:"failover"`
 
 	configgrpc.ClientConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 }
 
+// FailoverConfig defines the standby endpoints the exporter fails over to when the active endpoint keeps failing.
+// All the endpoints share the other settings of the gRPC client.
+type FailoverConfig struct {
+	// Endpoints are the standby endpoints, in order of preference. The exporter fails over from the primary endpoint
+	// to the first one, then to the next one, and back to the primary endpoint after the last one.
+	Endpoints []string `mapstructure:"endpoints"`
+	// MaxFailures is the number of consecutive failed exports to the active endpoint after which the exporter
+	// fails over to the next endpoint. The data rejected by the destination is not counted.
+	MaxFailures int `mapstructure:"max_failures"`
+	// ProbeInterval is how often the primary endpoint is probed while failed over, to fail back once it accepts
+	// the data again.
+	ProbeInterval time.Duration `mapstructure:"probe_interval"`
+}
+
 func (c *Config) Validate() error {
-	endpoint := c.sanitizedEndpoint()
+	if err := validateEndpoint(sanitizeEndpoint(c.Endpoint)); err != nil {
+		return err
+	}
+	for _, endpoint := range c.FailoverConfig.Endpoints {
+		if err := validateEndpoint(sanitizeEndpoint(endpoint)); err != nil {
+			return fmt.Errorf("failover: %w", err)
+		}
+	}
+	if len(c.FailoverConfig.Endpoints) > 0 {
+		if c.FailoverConfig.MaxFailures <= 0 {
+			return errors.New("failover: max_failures must be positive")
+		}
+		if c.FailoverConfig.ProbeInterval <= 0 {
+			return errors.New("failover: probe_interval must be positive")
+		}
+	}
+	return nil
+}
+
+func validateEndpoint(endpoint string) error {
 	if endpoint == "" {
 		return errors.New(`requires a non-empty "endpoint"`)
 	}
@@ -45,16 +84,20 @@ func (c *Config) Validate() error {
 }
 
 func (c *Config) sanitizedEndpoint() string {
+	return sanitizeEndpoint(c.Endpoint)
+}
+
+func sanitizeEndpoint(endpoint string) string {
 	switch {
-	case strings.HasPrefix(c.Endpoint, "http://"):
-		return strings.TrimPrefix(c.Endpoint, "http://")
-	case strings.HasPrefix(c.Endpoint, "https://"):
-		return strings.TrimPrefix(c.Endpoint, "https://")
-	case strings.HasPrefix(c.Endpoint, "dns://"):
+	case strings.HasPrefix(endpoint, "http://"):
+		return strings.TrimPrefix(endpoint, "http://")
+	case strings.HasPrefix(endpoint, "https://"):
+		return strings.TrimPrefix(endpoint, "https://")
+	case strings.HasPrefix(endpoint, "dns://"):
 		r := regexp.MustCompile("^dns://[/]?")
-		return r.ReplaceAllString(c.Endpoint, "")
+		return r.ReplaceAllString(endpoint, "")
 	default:
-		return c.Endpoint
+		return endpoint
 	}
 }
 
@@ -53,6 +53,13 @@ func TestUnmarshalConfig(t *testing.T) {
 				NumConsumers: 2,
 				QueueSize:    10,
 			},
+			CircuitBreakerConfig: exporterhelper.NewDefaultCircuitBreakerSettings(),
+			HedgingConfig:        exporterhelper.NewDefaultHedgingSettings(),
+			FailoverConfig: FailoverConfig{
+				Endpoints:     []string{"5.6.7.8:1234", "9.10.11.12:1234"},
+				MaxFailures:   5,
+				ProbeInterval: time.Minute,
+			},
 			ClientConfig: configgrpc.ClientConfig{
 				Headers: map[string]configopaque.String{
 					"can you have a . here?": "F0000000-0000-0000-0000-000000000000",
@@ -119,6 +126,18 @@ func TestUnmarshalInvalidConfig(t *testing.T) {
 			name:     "invalid_port",
 			errorMsg: `invalid port "port"`,
 		},
+		{
+			name:     "invalid_failover_endpoint",
+			errorMsg: `failover: address standby.example.com: missing port in address`,
+		},
+		{
+			name:     "invalid_failover_max_failures",
+			errorMsg: `failover: max_failures must be positive`,
+		},
+		{
+			name:     "invalid_failover_probe_interval",
+			errorMsg: `failover: probe_interval must be positive`,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			cfg := factory.CreateDefaultConfig()
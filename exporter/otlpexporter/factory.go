@@ -5,8 +5,10 @@ package otlpexporter // import "go.opentelemetry.io/collector/exporter/otlpexpor
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentprofiles"
 	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configopaque"
@@ -14,6 +16,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/exporterprofiles"
 	"go.opentelemetry.io/collector/exporter/otlpexporter/internal/metadata"
 )
 
@@ -25,14 +28,23 @@ func NewFactory() exporter.Factory {
 		exporter.WithTraces(createTracesExporter, metadata.TracesStability),
 		exporter.WithMetrics(createMetricsExporter, metadata.MetricsStability),
 		exporter.WithLogs(createLogsExporter, metadata.LogsStability),
+		exporterprofiles.WithProfiles(createProfilesExporter, metadata.ProfilesStability),
 	)
 }
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		TimeoutSettings: exporterhelper.NewDefaultTimeoutSettings(),
-		RetryConfig:     configretry.NewDefaultBackOffConfig(),
-		QueueConfig:     exporterhelper.NewDefaultQueueSettings(),
+		TimeoutSettings:      exporterhelper.NewDefaultTimeoutSettings(),
+		RetryConfig:          configretry.NewDefaultBackOffConfig(),
+		QueueConfig:          exporterhelper.NewDefaultQueueSettings(),
+		DeadLetterConfig:     exporterhelper.NewDefaultDeadLetterSettings(),
+		CircuitBreakerConfig: exporterhelper.NewDefaultCircuitBreakerSettings(),
+		RateLimitConfig:      exporterhelper.NewDefaultRateLimitSettings(),
+		HedgingConfig:        exporterhelper.NewDefaultHedgingSettings(),
+		FailoverConfig: FailoverConfig{
+			MaxFailures:   3,
+			ProbeInterval: 30 * time.Second,
+		},
 		ClientConfig: configgrpc.ClientConfig{
 			Headers: map[string]configopaque.String{},
 			// Default to gzip compression
@@ -48,7 +60,7 @@ func createTracesExporter(
 	set exporter.Settings,
 	cfg component.Config,
 ) (exporter.Traces, error) {
-	oce := newExporter(cfg, set)
+	oce := newExporter(cfg, set, component.DataTypeTraces)
 	oCfg := cfg.(*Config)
 	return exporterhelper.NewTracesExporter(ctx, set, cfg,
 		oce.pushTraces,
@@ -56,6 +68,10 @@ func createTracesExporter(
 		exporterhelper.WithTimeout(oCfg.TimeoutSettings),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithHedging(oCfg.HedgingConfig),
 		exporterhelper.WithStart(oce.start),
 		exporterhelper.WithShutdown(oce.shutdown))
 }
@@ -65,7 +81,7 @@ func createMetricsExporter(
 	set exporter.Settings,
 	cfg component.Config,
 ) (exporter.Metrics, error) {
-	oce := newExporter(cfg, set)
+	oce := newExporter(cfg, set, component.DataTypeMetrics)
 	oCfg := cfg.(*Config)
 	return exporterhelper.NewMetricsExporter(ctx, set, cfg,
 		oce.pushMetrics,
@@ -73,6 +89,10 @@ func createMetricsExporter(
 		exporterhelper.WithTimeout(oCfg.TimeoutSettings),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithHedging(oCfg.HedgingConfig),
 		exporterhelper.WithStart(oce.start),
 		exporterhelper.WithShutdown(oce.shutdown),
 	)
@@ -83,7 +103,7 @@ func createLogsExporter(
 	set exporter.Settings,
 	cfg component.Config,
 ) (exporter.Logs, error) {
-	oce := newExporter(cfg, set)
+	oce := newExporter(cfg, set, component.DataTypeLogs)
 	oCfg := cfg.(*Config)
 	return exporterhelper.NewLogsExporter(ctx, set, cfg,
 		oce.pushLogs,
@@ -91,6 +111,32 @@ func createLogsExporter(
 		exporterhelper.WithTimeout(oCfg.TimeoutSettings),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithHedging(oCfg.HedgingConfig),
+		exporterhelper.WithStart(oce.start),
+		exporterhelper.WithShutdown(oce.shutdown),
+	)
+}
+
+func createProfilesExporter(
+	ctx context.Context,
+	set exporter.Settings,
+	cfg component.Config,
+) (exporterprofiles.Profiles, error) {
+	oce := newExporter(cfg, set, componentprofiles.DataTypeProfiles)
+	oCfg := cfg.(*Config)
+	return exporterhelper.NewProfilesExporter(ctx, set, cfg,
+		oce.pushProfiles,
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
+		exporterhelper.WithTimeout(oCfg.TimeoutSettings),
+		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithQueue(oCfg.QueueConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithHedging(oCfg.HedgingConfig),
 		exporterhelper.WithStart(oce.start),
 		exporterhelper.WithShutdown(oce.shutdown),
 	)
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpexporter // import "go.opentelemetry.io/collector/exporter/otlpexporter"
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// failover keeps track of the endpoint the data is sent to. It fails over to the next endpoint once the active one
+// failed maxFailures exports in a row, and back to the primary endpoint, the first one, when told it's healthy again.
+type failover struct {
+	endpoints   []string
+	maxFailures int
+	logger      *zap.Logger
+	// onSwitch is called with the index of the new active endpoint every time it changes.
+	onSwitch func(active int)
+
+	// mu guards everything declared below.
+	mu       sync.Mutex
+	active   int
+	failures int
+}
+
+func newFailover(endpoints []string, maxFailures int, logger *zap.Logger, onSwitch func(int)) *failover {
+	return &failover{
+		endpoints:   endpoints,
+		maxFailures: maxFailures,
+		logger:      logger,
+		onSwitch:    onSwitch,
+	}
+}
+
+// activeIndex returns the index of the endpoint the data is sent to.
+func (f *failover) activeIndex() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+// record updates the failures count of the endpoint at the given index with the result of an export to it.
+func (f *failover) record(idx int, err error) {
+	// The data rejected by the destination tells nothing about its availability.
+	if consumererror.IsPermanent(err) {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// The results of the exports started before the last switch don't matter anymore.
+	if idx != f.active {
+		return
+	}
+	if err == nil {
+		f.failures = 0
+		return
+	}
+	f.failures++
+	if f.failures < f.maxFailures {
+		return
+	}
+	next := (f.active + 1) % len(f.endpoints)
+	f.logger.Warn("Endpoint keeps failing, failing over to the next endpoint.",
+		zap.String("from", f.endpoints[f.active]), zap.String("to", f.endpoints[next]),
+		zap.Int("failures", f.failures), zap.Error(err))
+	f.switchTo(next)
+}
+
+// failBack switches back to the primary endpoint.
+func (f *failover) failBack() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.active == 0 {
+		return
+	}
+	f.logger.Info("Primary endpoint is healthy again, failing back to it.",
+		zap.String("from", f.endpoints[f.active]), zap.String("to", f.endpoints[0]))
+	f.switchTo(0)
+}
+
+// switchTo makes the endpoint at the given index the active one. Caller must hold the lock.
+func (f *failover) switchTo(idx int) {
+	f.active, f.failures = idx, 0
+	f.onSwitch(idx)
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpexporter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+func TestFailover(t *testing.T) {
+	var switches []int
+	f := newFailover([]string{"primary:4317", "standby1:4317", "standby2:4317"}, 2, zap.NewNop(), func(active int) {
+		switches = append(switches, active)
+	})
+	assert.Equal(t, 0, f.activeIndex())
+
+	errFailed := errors.New("failed")
+
+	// A success resets the failures count.
+	f.record(0, errFailed)
+	f.record(0, nil)
+	f.record(0, errFailed)
+	assert.Equal(t, 0, f.activeIndex())
+
+	// The data rejected by the destination is not counted.
+	f.record(0, consumererror.NewPermanent(errFailed))
+	assert.Equal(t, 0, f.activeIndex())
+
+	f.record(0, errFailed)
+	assert.Equal(t, 1, f.activeIndex())
+
+	// The results of the exports to the previous endpoint are ignored.
+	f.record(0, errFailed)
+	f.record(0, errFailed)
+	assert.Equal(t, 1, f.activeIndex())
+
+	f.record(1, errFailed)
+	f.record(1, errFailed)
+	assert.Equal(t, 2, f.activeIndex())
+
+	// Fails over to the primary endpoint after the last one.
+	f.record(2, errFailed)
+	f.record(2, errFailed)
+	assert.Equal(t, 0, f.activeIndex())
+
+	// Failing back to the active primary endpoint is a no-op.
+	f.failBack()
+	assert.Equal(t, 0, f.activeIndex())
+
+	f.record(0, errFailed)
+	f.record(0, errFailed)
+	assert.Equal(t, 1, f.activeIndex())
+	f.failBack()
+	assert.Equal(t, 0, f.activeIndex())
+
+	assert.Equal(t, []int{1, 2, 0, 1, 0}, switches)
+}
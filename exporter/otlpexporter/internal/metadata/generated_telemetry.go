@@ -0,0 +1,82 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+)
+
+func Meter(settings component.TelemetrySettings) metric.Meter {
+	return settings.MeterProvider.Meter("go.opentelemetry.io/collector/exporter/otlpexporter")
+}
+
+func Tracer(settings component.TelemetrySettings) trace.Tracer {
+	return settings.TracerProvider.Tracer("go.opentelemetry.io/collector/exporter/otlpexporter")
+}
+
+// TelemetryBuilder provides an interface for components to report telemetry
+// as defined in metadata and user config.
+type TelemetryBuilder struct {
+	meter                        metric.Meter
+	ExporterOtlpActiveEndpoint   metric.Int64ObservableGauge
+	ExporterOtlpEndpointSwitches metric.Int64Counter
+	level                        configtelemetry.Level
+}
+
+// telemetryBuilderOption applies changes to default builder.
+type telemetryBuilderOption func(*TelemetryBuilder)
+
+// WithLevel sets the current telemetry level for the component.
+func WithLevel(lvl configtelemetry.Level) telemetryBuilderOption {
+	return func(builder *TelemetryBuilder) {
+		builder.level = lvl
+	}
+}
+
+// InitExporterOtlpActiveEndpoint configures the ExporterOtlpActiveEndpoint metric.
+func (builder *TelemetryBuilder) InitExporterOtlpActiveEndpoint(cb func() int64, opts ...metric.ObserveOption) error {
+	var err error
+	builder.ExporterOtlpActiveEndpoint, err = builder.meter.Int64ObservableGauge(
+		"otelcol_exporter_otlp_active_endpoint",
+		metric.WithDescription("Index of the endpoint the data is sent to, 0 being the primary endpoint and the next ones the failover endpoints in order, reported when failover endpoints are configured"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = builder.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(builder.ExporterOtlpActiveEndpoint, cb(), opts...)
+		return nil
+	}, builder.ExporterOtlpActiveEndpoint)
+	return err
+}
+
+// NewTelemetryBuilder provides a struct with methods to update all internal telemetry
+// for a component
+func NewTelemetryBuilder(settings component.TelemetrySettings, options ...telemetryBuilderOption) (*TelemetryBuilder, error) {
+	builder := TelemetryBuilder{level: configtelemetry.LevelBasic}
+	for _, op := range options {
+		op(&builder)
+	}
+	var err, errs error
+	if builder.level >= configtelemetry.LevelBasic {
+		builder.meter = Meter(settings)
+	} else {
+		builder.meter = noop.Meter{}
+	}
+	builder.ExporterOtlpEndpointSwitches, err = builder.meter.Int64Counter(
+		"otelcol_exporter_otlp_endpoint_switches",
+		metric.WithDescription("Number of times the exporter switched the endpoint the data is sent to, either failing over or failing back"),
+		metric.WithUnit("{switches}"),
+	)
+	errs = errors.Join(errs, err)
+	return &builder, errs
+}
@@ -5,10 +5,15 @@ package otlpexporter // import "go.opentelemetry.io/collector/exporter/otlpexpor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
@@ -17,13 +22,18 @@ import (
 	"google.golang.org/grpc/status"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentprofiles"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	internalmetadata "go.opentelemetry.io/collector/exporter/otlpexporter/internal/metadata"
+	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/pprofile/pprofileotlp"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 )
@@ -31,39 +41,64 @@ import (
 type baseExporter struct {
 	// Input configuration.
 	config *Config
+	signal component.DataType
 
-	// gRPC clients and connection.
-	traceExporter  ptraceotlp.GRPCClient
-	metricExporter pmetricotlp.GRPCClient
-	logExporter    plogotlp.GRPCClient
-	clientConn     *grpc.ClientConn
-	metadata       metadata.MD
-	callOptions    []grpc.CallOption
+	// gRPC clients and connections, one per endpoint, starting with the primary endpoint.
+	clients     []*endpointClient
+	metadata    metadata.MD
+	callOptions []grpc.CallOption
 
-	settings component.TelemetrySettings
+	// failover selects the endpoint the data is sent to, it's set if failover endpoints are configured.
+	failover *failover
+	stopCh   chan struct{}
+	probeWG  sync.WaitGroup
+
+	id               component.ID
+	settings         component.TelemetrySettings
+	telemetryBuilder *internalmetadata.TelemetryBuilder
 
 	// Default user-agent header.
 	userAgent string
 }
 
-func newExporter(cfg component.Config, set exporter.Settings) *baseExporter {
+// endpointClient holds the gRPC connection and clients to one of the endpoints.
+type endpointClient struct {
+	endpoint        string
+	clientConn      *grpc.ClientConn
+	traceExporter   ptraceotlp.GRPCClient
+	metricExporter  pmetricotlp.GRPCClient
+	logExporter     plogotlp.GRPCClient
+	profileExporter pprofileotlp.GRPCClient
+}
+
+func newExporter(cfg component.Config, set exporter.Settings, signal component.DataType) *baseExporter {
 	oCfg := cfg.(*Config)
 
 	userAgent := fmt.Sprintf("%s/%s (%s/%s)",
 		set.BuildInfo.Description, set.BuildInfo.Version, runtime.GOOS, runtime.GOARCH)
 
-	return &baseExporter{config: oCfg, settings: set.TelemetrySettings, userAgent: userAgent}
+	return &baseExporter{config: oCfg, signal: signal, id: set.ID, settings: set.TelemetrySettings, userAgent: userAgent}
 }
 
 // start actually creates the gRPC connection. The client construction is deferred till this point as this
 // is the only place we get hold of Extensions which are required to construct auth round tripper.
 func (e *baseExporter) start(ctx context.Context, host component.Host) (err error) {
-	if e.clientConn, err = e.config.ClientConfig.ToClientConn(ctx, host, e.settings, grpc.WithUserAgent(e.userAgent)); err != nil {
-		return err
+	for _, endpoint := range append([]string{e.config.Endpoint}, e.config.FailoverConfig.Endpoints...) {
+		clientConfig := e.config.ClientConfig
+		clientConfig.Endpoint = endpoint
+		clientConn, err := clientConfig.ToClientConn(ctx, host, e.settings, grpc.WithUserAgent(e.userAgent))
+		if err != nil {
+			return err
+		}
+		e.clients = append(e.clients, &endpointClient{
+			endpoint:        endpoint,
+			clientConn:      clientConn,
+			traceExporter:   ptraceotlp.NewGRPCClient(clientConn),
+			metricExporter:  pmetricotlp.NewGRPCClient(clientConn),
+			logExporter:     plogotlp.NewGRPCClient(clientConn),
+			profileExporter: pprofileotlp.NewGRPCClient(clientConn),
+		})
 	}
-	e.traceExporter = ptraceotlp.NewGRPCClient(e.clientConn)
-	e.metricExporter = pmetricotlp.NewGRPCClient(e.clientConn)
-	e.logExporter = plogotlp.NewGRPCClient(e.clientConn)
 	headers := map[string]string{}
 	for k, v := range e.config.ClientConfig.Headers {
 		headers[k] = string(v)
@@ -73,20 +108,129 @@ func (e *baseExporter) start(ctx context.Context, host component.Host) (err erro
 		grpc.WaitForReady(e.config.ClientConfig.WaitForReady),
 	}
 
-	return
+	if len(e.clients) > 1 {
+		return e.startFailover()
+	}
+	return nil
 }
 
-func (e *baseExporter) shutdown(context.Context) error {
-	if e.clientConn != nil {
-		return e.clientConn.Close()
+// startFailover starts tracking the endpoint the data is sent to, and probing the primary endpoint while failed over.
+func (e *baseExporter) startFailover() error {
+	var err error
+	if e.telemetryBuilder, err = internalmetadata.NewTelemetryBuilder(e.settings); err != nil {
+		return err
+	}
+	attrs := attribute.NewSet(attribute.String(obsmetrics.ExporterKey, e.id.String()),
+		attribute.String(obsmetrics.DataTypeKey, e.signal.String()))
+	endpoints := make([]string, len(e.clients))
+	for i, c := range e.clients {
+		endpoints[i] = c.endpoint
+	}
+	e.failover = newFailover(endpoints, e.config.FailoverConfig.MaxFailures, e.settings.Logger, func(active int) {
+		e.telemetryBuilder.ExporterOtlpEndpointSwitches.Add(context.Background(), 1, metric.WithAttributeSet(attrs),
+			metric.WithAttributes(attribute.String("endpoint", endpoints[active])))
+	})
+	if err = e.telemetryBuilder.InitExporterOtlpActiveEndpoint(func() int64 { return int64(e.failover.activeIndex()) },
+		metric.WithAttributeSet(attrs)); err != nil {
+		return err
 	}
+
+	e.stopCh = make(chan struct{})
+	e.probeWG.Add(1)
+	go func() {
+		defer e.probeWG.Done()
+		e.probePrimary()
+	}()
 	return nil
 }
 
+// probePrimary periodically sends an empty request to the primary endpoint while failed over,
+// and fails back to it once the request succeeds.
+func (e *baseExporter) probePrimary() {
+	ticker := time.NewTicker(e.config.FailoverConfig.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stopCh:
+			return
+		case <-ticker.C:
+		}
+		if e.failover.activeIndex() == 0 {
+			continue
+		}
+		// The probe must not outlive the next one.
+		ctx, cancel := context.WithTimeout(context.Background(), e.probeTimeout())
+		err := e.probe(ctx, e.clients[0])
+		cancel()
+		if err != nil {
+			e.settings.Logger.Debug("Primary endpoint is still failing.", zap.String("endpoint", e.clients[0].endpoint), zap.Error(err))
+			continue
+		}
+		e.failover.failBack()
+	}
+}
+
+// probeTimeout returns the timeout of a probe: the timeout of the requests, bounded by the probe interval.
+func (e *baseExporter) probeTimeout() time.Duration {
+	if e.config.Timeout > 0 {
+		return min(e.config.Timeout, e.config.FailoverConfig.ProbeInterval)
+	}
+	return e.config.FailoverConfig.ProbeInterval
+}
+
+// probe sends an empty request of the exporter signal to the endpoint.
+func (e *baseExporter) probe(ctx context.Context, c *endpointClient) error {
+	var err error
+	switch e.signal {
+	case component.DataTypeTraces:
+		_, err = c.traceExporter.Export(e.enhanceContext(ctx), ptraceotlp.NewExportRequest(), e.callOptions...)
+	case component.DataTypeMetrics:
+		_, err = c.metricExporter.Export(e.enhanceContext(ctx), pmetricotlp.NewExportRequest(), e.callOptions...)
+	case component.DataTypeLogs:
+		_, err = c.logExporter.Export(e.enhanceContext(ctx), plogotlp.NewExportRequest(), e.callOptions...)
+	case componentprofiles.DataTypeProfiles:
+		_, err = c.profileExporter.Export(e.enhanceContext(ctx), pprofileotlp.NewExportRequest(), e.callOptions...)
+	}
+	return processError(err)
+}
+
+func (e *baseExporter) shutdown(context.Context) error {
+	if e.stopCh != nil {
+		close(e.stopCh)
+		e.probeWG.Wait()
+	}
+	var errs error
+	for _, c := range e.clients {
+		errs = multierr.Append(errs, c.clientConn.Close())
+	}
+	return errs
+}
+
+// activeClient returns the client of the endpoint the data is sent to, along with its index.
+// The duplicates sent by the hedging go to the endpoints following the active one, if any.
+func (e *baseExporter) activeClient(ctx context.Context) (int, *endpointClient) {
+	if e.failover == nil {
+		return 0, e.clients[0]
+	}
+	idx := (e.failover.activeIndex() + exporterhelper.HedgeAttempt(ctx)) % len(e.clients)
+	return idx, e.clients[idx]
+}
+
+// recordResult records the result of an export to the endpoint at the given index, if failover endpoints are configured.
+// The exports cancelled because a duplicate sent by the hedging succeeded first are not counted.
+func (e *baseExporter) recordResult(ctx context.Context, idx int, err error) {
+	if e.failover != nil && !errors.Is(ctx.Err(), context.Canceled) {
+		e.failover.record(idx, err)
+	}
+}
+
 func (e *baseExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
 	req := ptraceotlp.NewExportRequestFromTraces(td)
-	resp, respErr := e.traceExporter.Export(e.enhanceContext(ctx), req, e.callOptions...)
-	if err := processError(respErr); err != nil {
+	idx, c := e.activeClient(ctx)
+	resp, respErr := c.traceExporter.Export(e.enhanceContext(ctx), req, e.callOptions...)
+	err := processError(respErr)
+	e.recordResult(ctx, idx, err)
+	if err != nil {
 		return err
 	}
 	partialSuccess := resp.PartialSuccess()
@@ -101,8 +245,11 @@ func (e *baseExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
 
 func (e *baseExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) error {
 	req := pmetricotlp.NewExportRequestFromMetrics(md)
-	resp, respErr := e.metricExporter.Export(e.enhanceContext(ctx), req, e.callOptions...)
-	if err := processError(respErr); err != nil {
+	idx, c := e.activeClient(ctx)
+	resp, respErr := c.metricExporter.Export(e.enhanceContext(ctx), req, e.callOptions...)
+	err := processError(respErr)
+	e.recordResult(ctx, idx, err)
+	if err != nil {
 		return err
 	}
 	partialSuccess := resp.PartialSuccess()
@@ -117,8 +264,11 @@ func (e *baseExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) erro
 
 func (e *baseExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
 	req := plogotlp.NewExportRequestFromLogs(ld)
-	resp, respErr := e.logExporter.Export(e.enhanceContext(ctx), req, e.callOptions...)
-	if err := processError(respErr); err != nil {
+	idx, c := e.activeClient(ctx)
+	resp, respErr := c.logExporter.Export(e.enhanceContext(ctx), req, e.callOptions...)
+	err := processError(respErr)
+	e.recordResult(ctx, idx, err)
+	if err != nil {
 		return err
 	}
 	partialSuccess := resp.PartialSuccess()
@@ -131,6 +281,25 @@ func (e *baseExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
 	return nil
 }
 
+func (e *baseExporter) pushProfiles(ctx context.Context, pd pprofile.Profiles) error {
+	req := pprofileotlp.NewExportRequestFromProfiles(pd)
+	idx, c := e.activeClient(ctx)
+	resp, respErr := c.profileExporter.Export(e.enhanceContext(ctx), req, e.callOptions...)
+	err := processError(respErr)
+	e.recordResult(ctx, idx, err)
+	if err != nil {
+		return err
+	}
+	partialSuccess := resp.PartialSuccess()
+	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedProfiles() == 0) {
+		e.settings.Logger.Warn("Partial success response",
+			zap.String("message", resp.PartialSuccess().ErrorMessage()),
+			zap.Int64("dropped_profiles", resp.PartialSuccess().RejectedProfiles()),
+		)
+	}
+	return nil
+}
+
 func (e *baseExporter) enhanceContext(ctx context.Context) context.Context {
 	if e.metadata.Len() > 0 {
 		return metadata.NewOutgoingContext(ctx, e.metadata)
@@ -15,6 +15,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -30,11 +31,14 @@ import (
 	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/pprofile/pprofileotlp"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"go.opentelemetry.io/collector/pdata/testdata"
@@ -174,6 +178,55 @@ func otlpLogsReceiverOnGRPCServer(ln net.Listener) *mockLogsReceiver {
 	return rcv
 }
 
+type mockProfilesReceiver struct {
+	pprofileotlp.UnimplementedGRPCServer
+	mockReceiver
+	exportResponse func() pprofileotlp.ExportResponse
+	lastRequest    pprofile.Profiles
+}
+
+func (r *mockProfilesReceiver) Export(ctx context.Context, req pprofileotlp.ExportRequest) (pprofileotlp.ExportResponse, error) {
+	r.requestCount.Add(int32(1))
+	pd := req.Profiles()
+	r.totalItems.Add(int32(pd.SampleCount()))
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.lastRequest = pd
+	r.metadata, _ = metadata.FromIncomingContext(ctx)
+	return r.exportResponse(), r.exportError
+}
+
+func (r *mockProfilesReceiver) getLastRequest() pprofile.Profiles {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.lastRequest
+}
+
+func (r *mockProfilesReceiver) setExportResponse(fn func() pprofileotlp.ExportResponse) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.exportResponse = fn
+}
+
+func otlpProfilesReceiverOnGRPCServer(ln net.Listener) *mockProfilesReceiver {
+	rcv := &mockProfilesReceiver{
+		mockReceiver: mockReceiver{
+			srv:          grpc.NewServer(),
+			requestCount: &atomic.Int32{},
+			totalItems:   &atomic.Int32{},
+		},
+		exportResponse: pprofileotlp.NewExportResponse,
+	}
+
+	// Now run it as a gRPC server
+	pprofileotlp.RegisterGRPCServer(rcv.srv, rcv)
+	go func() {
+		_ = rcv.srv.Serve(ln)
+	}()
+
+	return rcv
+}
+
 type mockMetricsReceiver struct {
 	pmetricotlp.UnimplementedGRPCServer
 	mockReceiver
@@ -667,6 +720,158 @@ func TestSendTracesOnResourceExhaustion(t *testing.T) {
 	}, 10*time.Second, 5*time.Millisecond, "Should retry if RetryInfo is included into status details by the server.")
 }
 
+func TestSendTracesFailover(t *testing.T) {
+	primaryLn, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err)
+	primary, _ := otlpTracesReceiverOnGRPCServer(primaryLn, false)
+	primary.setExportError(status.Error(codes.Unavailable, "unavailable"))
+	defer primary.srv.GracefulStop()
+
+	standbyLn, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err)
+	standby, _ := otlpTracesReceiverOnGRPCServer(standbyLn, false)
+	defer standby.srv.GracefulStop()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	// Disable queuing and retries to ensure that every call to ConsumeTraces is a single export.
+	cfg.QueueConfig.Enabled = false
+	cfg.RetryConfig.Enabled = false
+	cfg.ClientConfig = configgrpc.ClientConfig{
+		Endpoint: primaryLn.Addr().String(),
+		TLSSetting: configtls.ClientConfig{
+			Insecure: true,
+		},
+	}
+	cfg.FailoverConfig = FailoverConfig{
+		Endpoints:     []string{standbyLn.Addr().String()},
+		MaxFailures:   2,
+		ProbeInterval: 50 * time.Millisecond,
+	}
+
+	tt := setupTestTelemetry()
+	defer func() {
+		assert.NoError(t, tt.Shutdown(context.Background()))
+	}()
+	activeEndpoint := func() int64 {
+		var md metricdata.ResourceMetrics
+		require.NoError(t, tt.reader.Collect(context.Background(), &md))
+		gauge := tt.getMetric("otelcol_exporter_otlp_active_endpoint", md).Data.(metricdata.Gauge[int64])
+		require.Len(t, gauge.DataPoints, 1)
+		return gauge.DataPoints[0].Value
+	}
+
+	exp, err := factory.CreateTracesExporter(context.Background(), tt.NewSettings(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, exp)
+
+	defer func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	}()
+
+	host := componenttest.NewNopHost()
+	require.NoError(t, exp.Start(context.Background(), host))
+	assert.EqualValues(t, 0, activeEndpoint())
+
+	// Fails over to the standby endpoint after the primary one failed twice.
+	td := testdata.GenerateTraces(2)
+	assert.Error(t, exp.ConsumeTraces(context.Background(), td))
+	assert.Error(t, exp.ConsumeTraces(context.Background(), td))
+	assert.EqualValues(t, 2, primary.requestCount.Load())
+	assert.EqualValues(t, 1, activeEndpoint())
+
+	assert.NoError(t, exp.ConsumeTraces(context.Background(), td))
+	assert.EqualValues(t, 1, standby.requestCount.Load())
+	assert.EqualValues(t, 2, standby.totalItems.Load())
+
+	// Fails back to the primary endpoint once it's healthy again.
+	primary.setExportError(nil)
+	assert.Eventually(t, func() bool {
+		return activeEndpoint() == 0
+	}, 10*time.Second, 5*time.Millisecond)
+
+	assert.NoError(t, exp.ConsumeTraces(context.Background(), td))
+	assert.EqualValues(t, 1, standby.requestCount.Load())
+	assert.EqualValues(t, td, primary.getLastRequest())
+}
+
+func TestProbeTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		expected time.Duration
+	}{
+		{name: "no_timeout", timeout: 0, expected: time.Second},
+		{name: "shorter_timeout", timeout: 100 * time.Millisecond, expected: 100 * time.Millisecond},
+		{name: "longer_timeout", timeout: 5 * time.Second, expected: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.Timeout = tt.timeout
+			cfg.FailoverConfig.ProbeInterval = time.Second
+			e := &baseExporter{config: cfg}
+			assert.Equal(t, tt.expected, e.probeTimeout())
+		})
+	}
+}
+
+func TestSendTracesHedgingToFailoverEndpoint(t *testing.T) {
+	primaryLn, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err)
+	primary, _ := otlpTracesReceiverOnGRPCServer(primaryLn, false)
+	primary.setExportResponse(func() ptraceotlp.ExportResponse {
+		time.Sleep(time.Second)
+		return ptraceotlp.NewExportResponse()
+	})
+	defer primary.srv.GracefulStop()
+
+	standbyLn, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err)
+	standby, _ := otlpTracesReceiverOnGRPCServer(standbyLn, false)
+	defer standby.srv.GracefulStop()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.QueueConfig.Enabled = false
+	cfg.ClientConfig = configgrpc.ClientConfig{
+		Endpoint: primaryLn.Addr().String(),
+		TLSSetting: configtls.ClientConfig{
+			Insecure: true,
+		},
+	}
+	cfg.FailoverConfig.Endpoints = []string{standbyLn.Addr().String()}
+	cfg.FailoverConfig.MaxFailures = 1
+	cfg.HedgingConfig = exporterhelper.HedgingSettings{
+		Enabled:   true,
+		Delay:     50 * time.Millisecond,
+		MaxHedges: 1,
+	}
+
+	exp, err := factory.CreateTracesExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, exp)
+
+	defer func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	}()
+
+	host := componenttest.NewNopHost()
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	// The duplicates sent to the standby endpoint succeed before the slow primary endpoint responds.
+	// The cancelled attempts to the primary endpoint don't count as failures, so it stays the active one.
+	td := testdata.GenerateTraces(2)
+	for i := 1; i <= 2; i++ {
+		start := time.Now()
+		assert.NoError(t, exp.ConsumeTraces(context.Background(), td))
+		assert.Less(t, time.Since(start), time.Second)
+		assert.EqualValues(t, i, primary.requestCount.Load())
+		assert.EqualValues(t, i, standby.requestCount.Load())
+	}
+	assert.EqualValues(t, td, standby.getLastRequest())
+}
+
 func startServerAndMakeRequest(t *testing.T, exp exporter.Traces, td ptrace.Traces, ln net.Listener) {
 	rcv, _ := otlpTracesReceiverOnGRPCServer(ln, false)
 	defer rcv.srv.GracefulStop()
@@ -795,3 +1000,89 @@ func TestSendLogData(t *testing.T) {
 	assert.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
 	assert.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
 }
+
+func TestSendProfiles(t *testing.T) {
+	// Start an OTLP-compatible receiver.
+	ln, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
+	rcv := otlpProfilesReceiverOnGRPCServer(ln)
+	// Also closes the connection.
+	defer rcv.srv.GracefulStop()
+
+	// Start an OTLP exporter and point to the receiver.
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	// Disable queuing to ensure that we execute the request when calling ConsumeProfiles
+	// otherwise we will not see any errors.
+	cfg.QueueConfig.Enabled = false
+	cfg.ClientConfig = configgrpc.ClientConfig{
+		Endpoint: ln.Addr().String(),
+		TLSSetting: configtls.ClientConfig{
+			Insecure: true,
+		},
+	}
+	set := exportertest.NewNopSettings()
+	set.BuildInfo.Description = "Collector"
+	set.BuildInfo.Version = "1.2.3test"
+
+	// For testing the "Partial success" warning.
+	logger, observed := observer.New(zap.DebugLevel)
+	set.TelemetrySettings.Logger = zap.New(logger)
+
+	exp, err := factory.CreateProfilesExporter(context.Background(), set, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, exp)
+	defer func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	}()
+
+	host := componenttest.NewNopHost()
+
+	assert.NoError(t, exp.Start(context.Background(), host))
+
+	// Ensure that initially there is no data in the receiver.
+	assert.EqualValues(t, 0, rcv.requestCount.Load())
+
+	// A request with 2 profiles.
+	pd := testdata.GenerateProfiles(2)
+
+	err = exp.ConsumeProfiles(context.Background(), pd)
+	assert.NoError(t, err)
+
+	// Wait until it is received.
+	assert.Eventually(t, func() bool {
+		return rcv.requestCount.Load() > 0
+	}, 10*time.Second, 5*time.Millisecond)
+
+	// Verify received profiles.
+	assert.EqualValues(t, 1, rcv.requestCount.Load())
+	assert.EqualValues(t, 2, rcv.totalItems.Load())
+	assert.EqualValues(t, pd, rcv.getLastRequest())
+
+	md := rcv.getMetadata()
+	require.Equal(t, len(md.Get("User-Agent")), 1)
+	require.Contains(t, md.Get("User-Agent")[0], "Collector/1.2.3test")
+
+	st := status.New(codes.InvalidArgument, "Invalid argument")
+	rcv.setExportError(st.Err())
+
+	err = exp.ConsumeProfiles(context.Background(), testdata.GenerateProfiles(2))
+	assert.Error(t, err)
+
+	rcv.setExportError(nil)
+
+	// Return partial success
+	rcv.setExportResponse(func() pprofileotlp.ExportResponse {
+		response := pprofileotlp.NewExportResponse()
+		partialSuccess := response.PartialSuccess()
+		partialSuccess.SetErrorMessage("Some profiles were not ingested")
+		partialSuccess.SetRejectedProfiles(1)
+
+		return response
+	})
+
+	err = exp.ConsumeProfiles(context.Background(), testdata.GenerateProfiles(2))
+	assert.NoError(t, err)
+	assert.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
+	assert.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
+}
@@ -45,9 +45,13 @@ func (e *EncodingType) UnmarshalText(text []byte) error {
 
 // Config defines configuration for OTLP/HTTP exporter.
 type Config struct {
-	confighttp.ClientConfig `mapstructure:",squash"`     // squash ensures fields are correctly decoded in embedded struct.
-	QueueConfig             exporterhelper.QueueSettings `mapstructure:"sending_queue"`
-	RetryConfig             configretry.BackOffConfig    `mapstructure:"retry_on_failure"`
+	confighttp.ClientConfig `mapstructure:",squash"`              // squash ensures fields are correctly decoded in embedded struct.
+	QueueConfig             exporterhelper.QueueSettings          `mapstructure:"sending_queue"`
+	RetryConfig             configretry.BackOffConfig             `mapstructure:"retry_on_failure"`
+	DeadLetterConfig        exporterhelper.DeadLetterSettings     `mapstructure:"dead_letter"`
+	CircuitBreakerConfig    exporterhelper.CircuitBreakerSettings `mapstructure:"circuit_breaker"`
+	RateLimitConfig         exporterhelper.RateLimitSettings      `mapstructure:"rate_limit"`
+	HedgingConfig           exporterhelper.HedgingSettings        `mapstructure:"hedging"`
 
 	// The URL to send traces to. If omitted the Endpoint + "/v1/traces" will be used.
 	TracesEndpoint string `mapstructure:"traces_endpoint"`
@@ -58,6 +62,9 @@ type Config struct {
 	// The URL to send logs to. If omitted the Endpoint + "/v1/logs" will be used.
 	LogsEndpoint string `mapstructure:"logs_endpoint"`
 
+	// The URL to send profiles to. If omitted the Endpoint + "/v1development/profiles" will be used.
+	ProfilesEndpoint string `mapstructure:"profiles_endpoint"`
+
 	// The encoding to export telemetry (default: "proto")
 	Encoding EncodingType `mapstructure:"encoding"`
 }
@@ -66,7 +73,7 @@ var _ component.Config = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
-	if cfg.Endpoint == "" && cfg.TracesEndpoint == "" && cfg.MetricsEndpoint == "" && cfg.LogsEndpoint == "" {
+	if cfg.Endpoint == "" && cfg.TracesEndpoint == "" && cfg.MetricsEndpoint == "" && cfg.LogsEndpoint == "" && cfg.ProfilesEndpoint == "" {
 		return errors.New("at least one endpoint must be specified")
 	}
 	return nil
@@ -51,7 +51,9 @@ func TestUnmarshalConfig(t *testing.T) {
 				NumConsumers: 2,
 				QueueSize:    10,
 			},
-			Encoding: EncodingProto,
+			CircuitBreakerConfig: exporterhelper.NewDefaultCircuitBreakerSettings(),
+			HedgingConfig:        exporterhelper.NewDefaultHedgingSettings(),
+			Encoding:             EncodingProto,
 			ClientConfig: confighttp.ClientConfig{
 				Headers: map[string]configopaque.String{
 					"can you have a . here?": "F0000000-0000-0000-0000-000000000000",
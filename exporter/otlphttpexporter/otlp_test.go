@@ -33,6 +33,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/pprofile/pprofileotlp"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 )
@@ -496,6 +498,47 @@ func TestPartialSuccess_logs(t *testing.T) {
 	require.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
 }
 
+func TestPartialSuccess_profiles(t *testing.T) {
+	srv := createBackend("/v1development/profiles", func(writer http.ResponseWriter, _ *http.Request) {
+		response := pprofileotlp.NewExportResponse()
+		partial := response.PartialSuccess()
+		partial.SetErrorMessage("hello")
+		partial.SetRejectedProfiles(1)
+		b, err := response.MarshalProto()
+		require.NoError(t, err)
+		writer.Header().Set("Content-Type", "application/x-protobuf")
+		_, err = writer.Write(b)
+		require.NoError(t, err)
+	})
+	defer srv.Close()
+
+	cfg := &Config{
+		Encoding:     EncodingProto,
+		ClientConfig: confighttp.ClientConfig{Endpoint: srv.URL},
+	}
+	set := exportertest.NewNopSettings()
+
+	logger, observed := observer.New(zap.DebugLevel)
+	set.TelemetrySettings.Logger = zap.New(logger)
+
+	exp, err := createProfilesExporter(context.Background(), set, cfg)
+	require.NoError(t, err)
+
+	// start the exporter
+	err = exp.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, exp.Shutdown(context.Background()))
+	})
+
+	// generate data
+	profiles := pprofile.NewProfiles()
+	err = exp.ConsumeProfiles(context.Background(), profiles)
+	require.NoError(t, err)
+	require.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
+	require.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
+}
+
 func TestPartialResponse_missingHeaderButHasBody(t *testing.T) {
 	cfg := createDefaultConfig()
 	set := exportertest.NewNopSettings()
@@ -0,0 +1,342 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension // import "go.opentelemetry.io/collector/extension/filestorageextension"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+var defaultBucket = []byte(`default`)
+
+// swapFile replaces the database file with the compacted file, it is overridden in tests.
+var swapFile = moveFile
+
+const (
+	elapsedKey       = "elapsed"
+	directoryKey     = "directory"
+	tempDirectoryKey = "tempDirectory"
+
+	oneMiB = 1048576
+)
+
+var errClientClosed = errors.New("storage client is closed")
+
+// fileStorageClient is a storage.Client backed by a single bbolt database file.
+// Every Batch is executed in a single write transaction, so a crash never leaves a batch partially applied.
+type fileStorageClient struct {
+	logger *zap.Logger
+
+	// compactionMutex guards the db from being swapped while it's being used.
+	compactionMutex sync.RWMutex
+	db              *bbolt.DB
+	closed          bool
+
+	compactionCfg *CompactionConfig
+	openTimeout   time.Duration
+	fsync         bool
+
+	cancel       context.CancelFunc
+	compactionWG sync.WaitGroup
+}
+
+var _ storage.Client = (*fileStorageClient)(nil)
+
+func bboltOptions(timeout time.Duration, fsync bool) *bbolt.Options {
+	return &bbolt.Options{
+		Timeout:        timeout,
+		NoSync:         !fsync,
+		NoFreelistSync: true,
+		FreelistType:   bbolt.FreelistMapType,
+	}
+}
+
+func newClient(logger *zap.Logger, filePath string, timeout time.Duration, compactionCfg *CompactionConfig, fsync bool) (*fileStorageClient, error) {
+	db, err := bbolt.Open(filePath, 0600, bboltOptions(timeout, fsync))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage file %q: %w", filePath, err)
+	}
+
+	initBucket := func(tx *bbolt.Tx) error {
+		_, err = tx.CreateBucketIfNotExists(defaultBucket)
+		return err
+	}
+	if err = db.Update(initBucket); err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+
+	if compactionCfg == nil {
+		compactionCfg = &CompactionConfig{}
+	}
+	client := &fileStorageClient{
+		logger:        logger,
+		db:            db,
+		compactionCfg: compactionCfg,
+		openTimeout:   timeout,
+		fsync:         fsync,
+	}
+	if compactionCfg.OnRebound {
+		client.startCompactionLoop()
+	}
+	return client, nil
+}
+
+// Get will retrieve data from storage that corresponds to the specified key
+func (c *fileStorageClient) Get(ctx context.Context, key string) ([]byte, error) {
+	op := storage.GetOperation(key)
+	if err := c.Batch(ctx, op); err != nil {
+		return nil, err
+	}
+	return op.Value, nil
+}
+
+// Set will store data. The data can be retrieved using the same key
+func (c *fileStorageClient) Set(ctx context.Context, key string, value []byte) error {
+	return c.Batch(ctx, storage.SetOperation(key, value))
+}
+
+// Delete will delete data associated with the specified key
+func (c *fileStorageClient) Delete(ctx context.Context, key string) error {
+	return c.Batch(ctx, storage.DeleteOperation(key))
+}
+
+// Batch executes the specified operations in order, in a single transaction. Get operation results are updated in place
+func (c *fileStorageClient) Batch(_ context.Context, ops ...storage.Operation) error {
+	c.compactionMutex.RLock()
+	defer c.compactionMutex.RUnlock()
+
+	if c.closed {
+		return errClientClosed
+	}
+
+	batch := func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(defaultBucket)
+		if bucket == nil {
+			return errors.New("storage not initialized")
+		}
+
+		var err error
+		for _, op := range ops {
+			switch op.Type {
+			case storage.Get:
+				// The value returned by bbolt is only valid for the life of the transaction.
+				if value := bucket.Get([]byte(op.Key)); value != nil {
+					op.Value = append([]byte(nil), value...)
+				} else {
+					op.Value = nil
+				}
+			case storage.Set:
+				err = bucket.Put([]byte(op.Key), op.Value)
+			case storage.Delete:
+				err = bucket.Delete([]byte(op.Key))
+			default:
+				return errors.New("wrong operation type")
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return c.db.Update(batch)
+}
+
+// Close will close the database
+func (c *fileStorageClient) Close(context.Context) error {
+	if c.cancel != nil {
+		c.cancel()
+		c.compactionWG.Wait()
+	}
+
+	c.compactionMutex.Lock()
+	defer c.compactionMutex.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.db.Close()
+}
+
+// Compact rewrites the database into a temporary file located in compactionDirectory and atomically swaps it with
+// the original file. The file is never left in a partially compacted state: until the rename succeeds, the original
+// file is untouched.
+func (c *fileStorageClient) Compact(compactionDirectory string, timeout time.Duration, maxTransactionSize int64) error {
+	c.compactionMutex.Lock()
+	defer c.compactionMutex.Unlock()
+
+	if c.closed {
+		return errClientClosed
+	}
+
+	file, err := os.CreateTemp(compactionDirectory, "tempdb")
+	if err != nil {
+		return err
+	}
+	tempPath := file.Name()
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	// Always sync the compacted file, the original one is removed once it's swapped.
+	compactedDB, err := bbolt.Open(tempPath, 0600, bboltOptions(timeout, true))
+	if err != nil {
+		return errors.Join(err, os.Remove(tempPath))
+	}
+
+	compactionStart := time.Now()
+	dbPath := c.db.Path()
+	c.logger.Debug("Starting compaction",
+		zap.String(directoryKey, dbPath),
+		zap.String(tempDirectoryKey, tempPath))
+
+	if err = bbolt.Compact(compactedDB, c.db, maxTransactionSize); err != nil {
+		return errors.Join(err, compactedDB.Close(), os.Remove(tempPath))
+	}
+	if err = compactedDB.Close(); err != nil {
+		return errors.Join(err, os.Remove(tempPath))
+	}
+
+	// The original database must be closed before it can be replaced, on Windows in particular.
+	if err = c.db.Close(); err != nil {
+		// The database cannot be used once its close started, whether it succeeded or not.
+		c.closed = true
+		return errors.Join(err, os.Remove(tempPath))
+	}
+
+	moveErr := swapFile(tempPath, dbPath)
+	if moveErr != nil {
+		// Keep serving from the original file.
+		moveErr = fmt.Errorf("failed to swap the compacted file %q: %w", tempPath, moveErr)
+		if err = os.Remove(tempPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			moveErr = errors.Join(moveErr, err)
+		}
+	}
+
+	if c.db, err = bbolt.Open(dbPath, 0600, bboltOptions(c.openTimeout, c.fsync)); err != nil {
+		c.closed = true
+		return errors.Join(moveErr, fmt.Errorf("failed to reopen storage file %q after compaction: %w", dbPath, err))
+	}
+	if moveErr != nil {
+		return moveErr
+	}
+
+	c.logger.Info("Finished compaction",
+		zap.String(directoryKey, dbPath),
+		zap.Duration(elapsedKey, time.Since(compactionStart)))
+
+	return nil
+}
+
+// startCompactionLoop runs a goroutine checking periodically if the database needs to be compacted.
+func (c *fileStorageClient) startCompactionLoop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.compactionWG.Add(1)
+	go func() {
+		defer c.compactionWG.Done()
+		ticker := time.NewTicker(c.compactionCfg.CheckInterval)
+		defer ticker.Stop()
+
+		// reboundNeeded is set once the allocated file size crosses the needed threshold.
+		reboundNeeded := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				totalSize, dataSize, err := c.getDBSize()
+				if err != nil {
+					c.logger.Error("Failed to read the storage size", zap.Error(err))
+					continue
+				}
+				if totalSize > c.compactionCfg.ReboundNeededThresholdMiB*oneMiB {
+					reboundNeeded = true
+				}
+				if !reboundNeeded || dataSize > c.compactionCfg.ReboundTriggerThresholdMiB*oneMiB {
+					continue
+				}
+				if err = c.Compact(c.compactionCfg.Directory, c.openTimeout, c.compactionCfg.MaxTransactionSize); err != nil {
+					c.logger.Error("Online compaction failed", zap.Error(err))
+					continue
+				}
+				reboundNeeded = false
+			}
+		}
+	}()
+}
+
+// getDBSize returns the allocated size of the database file and the size used by the live data.
+func (c *fileStorageClient) getDBSize() (totalSize int64, dataSize int64, err error) {
+	c.compactionMutex.RLock()
+	defer c.compactionMutex.RUnlock()
+
+	if c.closed {
+		return 0, 0, errClientClosed
+	}
+
+	var pageSize int64
+	err = c.db.View(func(tx *bbolt.Tx) error {
+		totalSize = tx.Size()
+		pageSize = int64(tx.DB().Info().PageSize)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	stats := c.db.Stats()
+	dataSize = totalSize - int64(stats.FreePageN+stats.PendingPageN)*pageSize
+	return totalSize, dataSize, nil
+}
+
+// moveFile renames src to dst. If they are located on different file systems, src is first copied next to dst,
+// so the final swap is still an atomic rename.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	tmpDst := filepath.Join(filepath.Dir(dst), filepath.Base(src))
+	if err := copyFile(src, tmpDst); err != nil {
+		return errors.Join(err, os.Remove(tmpDst))
+	}
+	if err := os.Rename(tmpDst, dst); err != nil {
+		return errors.Join(err, os.Remove(tmpDst))
+	}
+	return os.Remove(src)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(filepath.Clean(dst), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		return errors.Join(err, out.Close())
+	}
+	if err = out.Sync(); err != nil {
+		return errors.Join(err, out.Close())
+	}
+	return out.Close()
+}
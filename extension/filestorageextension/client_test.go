@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+func newTestClient(t *testing.T, dir string, compactionCfg *CompactionConfig) *fileStorageClient {
+	client, err := newClient(zap.NewNop(), filepath.Join(dir, "test"), time.Second, compactionCfg, false)
+	require.NoError(t, err)
+	return client
+}
+
+func TestClientOperations(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, t.TempDir(), nil)
+	t.Cleanup(func() { require.NoError(t, client.Close(ctx)) })
+
+	val, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+	val, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+
+	require.NoError(t, client.Delete(ctx, "key"))
+	val, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	// Deleting a missing key is a no-op.
+	require.NoError(t, client.Delete(ctx, "key"))
+}
+
+func TestClientBatch(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, t.TempDir(), nil)
+	t.Cleanup(func() { require.NoError(t, client.Close(ctx)) })
+
+	require.NoError(t, client.Batch(ctx,
+		storage.SetOperation("a", []byte("1")),
+		storage.SetOperation("b", []byte("2")),
+		storage.DeleteOperation("a"),
+	))
+
+	getA := storage.GetOperation("a")
+	getB := storage.GetOperation("b")
+	require.NoError(t, client.Batch(ctx, getA, getB))
+	assert.Nil(t, getA.Value)
+	assert.Equal(t, []byte("2"), getB.Value)
+}
+
+func TestClientClosed(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, t.TempDir(), nil)
+	require.NoError(t, client.Close(ctx))
+	// Closing twice is allowed.
+	require.NoError(t, client.Close(ctx))
+
+	_, err := client.Get(ctx, "key")
+	assert.ErrorIs(t, err, errClientClosed)
+	assert.ErrorIs(t, client.Set(ctx, "key", []byte("value")), errClientClosed)
+}
+
+func TestClientCompaction(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	client := newTestClient(t, dir, nil)
+	t.Cleanup(func() { require.NoError(t, client.Close(ctx)) })
+
+	value := make([]byte, 4096)
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key%d", i), value))
+	}
+	for i := 0; i < 999; i++ {
+		require.NoError(t, client.Delete(ctx, fmt.Sprintf("key%d", i)))
+	}
+
+	before, err := os.Stat(filepath.Join(dir, "test"))
+	require.NoError(t, err)
+
+	require.NoError(t, client.Compact(dir, time.Second, defaultMaxTransactionSize))
+
+	after, err := os.Stat(filepath.Join(dir, "test"))
+	require.NoError(t, err)
+	assert.Less(t, after.Size(), before.Size())
+
+	val, err := client.Get(ctx, "key999")
+	require.NoError(t, err)
+	assert.Equal(t, value, val)
+
+	// No temporary file is left behind.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestClientCompactionSwapError(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	client := newTestClient(t, dir, nil)
+	t.Cleanup(func() { require.NoError(t, client.Close(ctx)) })
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+
+	swapErr := errors.New("swap failed")
+	swapFile = func(string, string) error { return swapErr }
+	t.Cleanup(func() { swapFile = moveFile })
+
+	compactionDir := t.TempDir()
+	require.ErrorIs(t, client.Compact(compactionDir, time.Second, defaultMaxTransactionSize), swapErr)
+
+	// The temporary file is removed, and the original file keeps being served.
+	entries, err := os.ReadDir(compactionDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	val, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+}
+
+func TestClientCompactionOnRebound(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	client := newTestClient(t, dir, &CompactionConfig{
+		OnRebound:                  true,
+		Directory:                  dir,
+		ReboundNeededThresholdMiB:  1,
+		ReboundTriggerThresholdMiB: 1,
+		MaxTransactionSize:         defaultMaxTransactionSize,
+		CheckInterval:              10 * time.Millisecond,
+	})
+	t.Cleanup(func() { require.NoError(t, client.Close(ctx)) })
+
+	value := make([]byte, 4096)
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key%d", i), value))
+	}
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, client.Delete(ctx, fmt.Sprintf("key%d", i)))
+	}
+
+	assert.Eventually(t, func() bool {
+		totalSize, _, err := client.getDBSize()
+		return err == nil && totalSize < oneMiB
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension // import "go.opentelemetry.io/collector/extension/filestorageextension"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines configuration for the file storage extension.
+type Config struct {
+	// Directory is the directory in which the storage files are created.
+	// Every client gets its own file, named after the component kind, ID and storage name.
+	Directory string `mapstructure:"directory"`
+
+	// Timeout is the maximum time to wait for a file lock. A zero value means waiting indefinitely.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Compaction configures how and when the storage files are compacted.
+	Compaction *CompactionConfig `mapstructure:"compaction"`
+
+	// FSync specifies whether every write transaction is synced to disk before returning.
+	// Disabling it improves throughput at the risk of losing the latest writes on a machine crash.
+	// A process crash never corrupts the storage files, regardless of this setting.
+	FSync bool `mapstructure:"fsync"`
+
+	// CreateDirectory specifies whether the directory should be created on start if it doesn't exist yet.
+	CreateDirectory bool `mapstructure:"create_directory"`
+}
+
+// CompactionConfig defines configuration for the compaction of the storage files.
+// Compaction rewrites a storage file to release the disk space used by deleted entries.
+type CompactionConfig struct {
+	// OnStart specifies whether the file is compacted when a client is created.
+	OnStart bool `mapstructure:"on_start"`
+	// OnRebound specifies whether the file is compacted online, once its used size has dropped well below
+	// its allocated size, e.g. after a long backlog in a persistent queue has been drained.
+	OnRebound bool `mapstructure:"on_rebound"`
+	// Directory is the directory used to write the temporary compacted file.
+	// It must be located on the same file system as the storage directory for the swap to be atomic.
+	Directory string `mapstructure:"directory"`
+	// ReboundNeededThresholdMiB is the allocated file size, in MiB, above which an online compaction is considered.
+	ReboundNeededThresholdMiB int64 `mapstructure:"rebound_needed_threshold_mib"`
+	// ReboundTriggerThresholdMiB is the used data size, in MiB, below which an online compaction is triggered
+	// once ReboundNeededThresholdMiB has been exceeded.
+	ReboundTriggerThresholdMiB int64 `mapstructure:"rebound_trigger_threshold_mib"`
+	// MaxTransactionSize is the maximum number of items copied in a single transaction during compaction.
+	MaxTransactionSize int64 `mapstructure:"max_transaction_size"`
+	// CheckInterval is the interval at which the conditions for the online compaction are checked.
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the extension configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Directory == "" {
+		return errors.New("directory must not be empty")
+	}
+	if !cfg.CreateDirectory {
+		if err := checkDirectory(cfg.Directory); err != nil {
+			return err
+		}
+	}
+	if cfg.Timeout < 0 {
+		return errors.New("timeout must be non-negative")
+	}
+
+	if cfg.Compaction == nil {
+		return nil
+	}
+	if cfg.Compaction.OnStart || cfg.Compaction.OnRebound {
+		if cfg.Compaction.Directory == "" {
+			return errors.New("compaction directory must not be empty when compaction is enabled")
+		}
+		// The compaction directory is allowed to be created together with the storage directory.
+		if !cfg.CreateDirectory || cfg.Compaction.Directory != cfg.Directory {
+			if err := checkDirectory(cfg.Compaction.Directory); err != nil {
+				return fmt.Errorf("compaction: %w", err)
+			}
+		}
+	}
+	if cfg.Compaction.MaxTransactionSize < 0 {
+		return errors.New("compaction max_transaction_size must be non-negative")
+	}
+	if cfg.Compaction.OnRebound {
+		if cfg.Compaction.CheckInterval <= 0 {
+			return errors.New("compaction check_interval must be positive when on_rebound is enabled")
+		}
+		if cfg.Compaction.ReboundNeededThresholdMiB < 0 || cfg.Compaction.ReboundTriggerThresholdMiB < 0 {
+			return errors.New("compaction rebound thresholds must be non-negative")
+		}
+		if cfg.Compaction.ReboundTriggerThresholdMiB > cfg.Compaction.ReboundNeededThresholdMiB {
+			return errors.New("compaction rebound_trigger_threshold_mib must not be greater than rebound_needed_threshold_mib")
+		}
+	}
+	return nil
+}
+
+func checkDirectory(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("directory must exist: %w", err)
+		}
+		return fmt.Errorf("cannot access directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%q is not a directory", dir)
+	}
+	return nil
+}
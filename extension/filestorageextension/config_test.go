@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/extension/filestorageextension/internal/metadata"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, confmap.New().Unmarshal(&cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+}
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id       component.ID
+		expected component.Config
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: NewFactory().CreateDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "all_settings"),
+			expected: &Config{
+				Directory:       "/var/lib/otelcol/mydir",
+				Timeout:         2 * time.Second,
+				FSync:           true,
+				CreateDirectory: true,
+				Compaction: &CompactionConfig{
+					OnStart:                    true,
+					OnRebound:                  true,
+					Directory:                  "/tmp/",
+					ReboundNeededThresholdMiB:  128,
+					ReboundTriggerThresholdMiB: 16,
+					MaxTransactionSize:         2048,
+					CheckInterval:              10 * time.Second,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig()
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		mutate  func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			mutate: func(*Config) {},
+		},
+		{
+			name:    "empty directory",
+			mutate:  func(cfg *Config) { cfg.Directory = "" },
+			wantErr: "directory must not be empty",
+		},
+		{
+			name:    "missing directory",
+			mutate:  func(cfg *Config) { cfg.Directory = filepath.Join(dir, "missing") },
+			wantErr: "directory must exist",
+		},
+		{
+			name: "missing directory created on start",
+			mutate: func(cfg *Config) {
+				cfg.Directory = filepath.Join(dir, "missing")
+				cfg.CreateDirectory = true
+			},
+		},
+		{
+			name:    "negative timeout",
+			mutate:  func(cfg *Config) { cfg.Timeout = -time.Second },
+			wantErr: "timeout must be non-negative",
+		},
+		{
+			name: "missing compaction directory",
+			mutate: func(cfg *Config) {
+				cfg.Compaction.OnStart = true
+				cfg.Compaction.Directory = filepath.Join(dir, "missing")
+			},
+			wantErr: "compaction: directory must exist",
+		},
+		{
+			name: "zero check interval",
+			mutate: func(cfg *Config) {
+				cfg.Compaction.OnRebound = true
+				cfg.Compaction.CheckInterval = 0
+			},
+			wantErr: "compaction check_interval must be positive when on_rebound is enabled",
+		},
+		{
+			name: "inverted rebound thresholds",
+			mutate: func(cfg *Config) {
+				cfg.Compaction.OnRebound = true
+				cfg.Compaction.ReboundTriggerThresholdMiB = 200
+			},
+			wantErr: "compaction rebound_trigger_threshold_mib must not be greater than rebound_needed_threshold_mib",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Directory = dir
+			cfg.Compaction.Directory = dir
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension // import "go.opentelemetry.io/collector/extension/filestorageextension"
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+// localFileStorage is a storage.Extension that stores the data of every client in a separate file on local disk.
+type localFileStorage struct {
+	cfg    *Config
+	logger *zap.Logger
+}
+
+var _ storage.Extension = (*localFileStorage)(nil)
+
+func newLocalFileStorage(cfg *Config, logger *zap.Logger) *localFileStorage {
+	return &localFileStorage{
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Start creates the storage directories if configured to do so.
+func (lfs *localFileStorage) Start(context.Context, component.Host) error {
+	if !lfs.cfg.CreateDirectory {
+		return nil
+	}
+	if err := os.MkdirAll(lfs.cfg.Directory, 0750); err != nil {
+		return fmt.Errorf("failed to create directory %q: %w", lfs.cfg.Directory, err)
+	}
+	if lfs.cfg.Compaction != nil && lfs.cfg.Compaction.Directory != "" {
+		if err := os.MkdirAll(lfs.cfg.Compaction.Directory, 0750); err != nil {
+			return fmt.Errorf("failed to create compaction directory %q: %w", lfs.cfg.Compaction.Directory, err)
+		}
+	}
+	return nil
+}
+
+// Shutdown does nothing, it's the responsibility of each component to close the clients it requested.
+func (lfs *localFileStorage) Shutdown(context.Context) error {
+	return nil
+}
+
+// GetClient returns a storage client for an individual component.
+// Clients are namespaced by the component kind, ID and the storage name, so that each of them uses a separate file.
+func (lfs *localFileStorage) GetClient(_ context.Context, kind component.Kind, id component.ID, storageName string) (storage.Client, error) {
+	absoluteName := filepath.Join(lfs.cfg.Directory, clientFileName(kind, id, storageName))
+	client, err := newClient(lfs.logger, absoluteName, lfs.cfg.Timeout, lfs.cfg.Compaction, lfs.cfg.FSync)
+	if err != nil {
+		return nil, err
+	}
+
+	if lfs.cfg.Compaction != nil && lfs.cfg.Compaction.OnStart {
+		compactionErr := client.Compact(lfs.cfg.Compaction.Directory, lfs.cfg.Timeout, lfs.cfg.Compaction.MaxTransactionSize)
+		if compactionErr != nil {
+			lfs.logger.Error("Compaction on start failed", zap.String("file", absoluteName), zap.Error(compactionErr))
+		}
+	}
+
+	return client, nil
+}
+
+// clientFileName builds the file name used by a client, e.g. "exporter_otlp_backend_traces". Each part is
+// sanitized before they are joined with "_", which sanitize escapes, so that the parts cannot run into each other.
+func clientFileName(kind component.Kind, id component.ID, storageName string) string {
+	parts := []string{strings.ToLower(kind.String()), sanitize(id.Type().String())}
+	if id.Name() != "" {
+		parts = append(parts, sanitize(id.Name()))
+	}
+	if storageName != "" {
+		parts = append(parts, sanitize(storageName))
+	}
+	return strings.Join(parts, "_")
+}
+
+// sanitize replaces the characters that are not safe to use in file names across platforms, and the "_" separating
+// the parts of the file names, by a "~" followed by their hexadecimal code, keeping names of different components
+// distinct.
+func sanitize(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if isSafeFileNameRune(r) {
+			b.WriteRune(r)
+			continue
+		}
+		fmt.Fprintf(&b, "~%04X", r)
+	}
+	return b.String()
+}
+
+func isSafeFileNameRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		r == '-' || r == '.'
+}
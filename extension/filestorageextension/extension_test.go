@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+func newTestExtension(t *testing.T, mutate func(cfg *Config)) storage.Extension {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Directory = t.TempDir()
+	cfg.Compaction.Directory = cfg.Directory
+	if mutate != nil {
+		mutate(cfg)
+	}
+	ext := newLocalFileStorage(cfg, zap.NewNop())
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ext.Shutdown(context.Background())) })
+	return ext
+}
+
+func TestExtensionNamespacing(t *testing.T) {
+	ctx := context.Background()
+	ext := newTestExtension(t, nil)
+
+	otlp := component.MustNewType("otlp")
+	clients := []storage.Client{}
+	for _, args := range []struct {
+		kind        component.Kind
+		id          component.ID
+		storageName string
+	}{
+		{component.KindExporter, component.NewID(otlp), "traces"},
+		{component.KindExporter, component.NewID(otlp), "logs"},
+		{component.KindExporter, component.NewIDWithName(otlp, "backend"), "traces"},
+		{component.KindReceiver, component.NewID(otlp), "traces"},
+	} {
+		client, err := ext.GetClient(ctx, args.kind, args.id, args.storageName)
+		require.NoError(t, err)
+		clients = append(clients, client)
+	}
+
+	for i, client := range clients {
+		require.NoError(t, client.Set(ctx, "key", []byte{byte(i)}))
+	}
+	for i, client := range clients {
+		val, err := client.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.Equal(t, []byte{byte(i)}, val)
+		require.NoError(t, client.Close(ctx))
+	}
+}
+
+func TestExtensionPersistsAcrossClients(t *testing.T) {
+	ctx := context.Background()
+	ext := newTestExtension(t, nil)
+	id := component.NewID(component.MustNewType("otlp"))
+
+	client, err := ext.GetClient(ctx, component.KindExporter, id, "traces")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+	require.NoError(t, client.Close(ctx))
+
+	client, err = ext.GetClient(ctx, component.KindExporter, id, "traces")
+	require.NoError(t, err)
+	val, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+	require.NoError(t, client.Close(ctx))
+}
+
+func TestExtensionCreateDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "storage")
+	ext := newTestExtension(t, func(cfg *Config) {
+		cfg.Directory = dir
+		cfg.Compaction.Directory = dir
+		cfg.CreateDirectory = true
+	})
+
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	client, err := ext.GetClient(context.Background(), component.KindExporter, component.NewID(component.MustNewType("otlp")), "")
+	require.NoError(t, err)
+	require.NoError(t, client.Close(context.Background()))
+	assert.FileExists(t, filepath.Join(dir, "exporter_otlp"))
+}
+
+func TestExtensionCompactionOnStart(t *testing.T) {
+	ctx := context.Background()
+	ext := newTestExtension(t, func(cfg *Config) {
+		cfg.Compaction.OnStart = true
+	})
+	id := component.NewID(component.MustNewType("otlp"))
+
+	client, err := ext.GetClient(ctx, component.KindExporter, id, "traces")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+	require.NoError(t, client.Close(ctx))
+
+	client, err = ext.GetClient(ctx, component.KindExporter, id, "traces")
+	require.NoError(t, err)
+	val, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+	require.NoError(t, client.Close(ctx))
+}
+
+func TestClientFileName(t *testing.T) {
+	otlp := component.MustNewType("otlp")
+	assert.Equal(t, "exporter_otlp", clientFileName(component.KindExporter, component.NewID(otlp), ""))
+	assert.Equal(t, "exporter_otlp_backend_traces",
+		clientFileName(component.KindExporter, component.NewIDWithName(otlp, "backend"), "traces"))
+	assert.Equal(t, "receiver_otlp_a~002Fb_logs",
+		clientFileName(component.KindReceiver, component.NewIDWithName(otlp, "a/b"), "logs"))
+	// The "_" of the names is escaped, so that it cannot be confused with the separator.
+	assert.Equal(t, "exporter_otlp_backend~005Ftraces",
+		clientFileName(component.KindExporter, component.NewIDWithName(otlp, "backend_traces"), ""))
+	assert.NotEqual(t, clientFileName(component.KindExporter, component.NewIDWithName(otlp, "backend_traces"), ""),
+		clientFileName(component.KindExporter, component.NewIDWithName(otlp, "backend"), "traces"))
+}
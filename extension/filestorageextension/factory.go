@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+package filestorageextension // import "go.opentelemetry.io/collector/extension/filestorageextension"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/filestorageextension/internal/metadata"
+)
+
+const (
+	// defaultMaxTransactionSize is the default maximum number of items copied in a single compaction transaction.
+	defaultMaxTransactionSize int64 = 65536
+	// defaultReboundNeededThresholdMiB is the default allocated file size, in MiB, above which online compaction
+	// is considered.
+	defaultReboundNeededThresholdMiB = 100
+	// defaultReboundTriggerThresholdMiB is the default used data size, in MiB, below which online compaction runs.
+	defaultReboundTriggerThresholdMiB = 10
+	defaultCompactionInterval         = 5 * time.Second
+	defaultTimeout                    = time.Second
+)
+
+// NewFactory creates a factory for the file storage extension.
+func NewFactory() extension.Factory {
+	return extension.NewFactory(metadata.Type, createDefaultConfig, createExtension, metadata.ExtensionStability)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Directory: defaultDirectory(),
+		Compaction: &CompactionConfig{
+			Directory:                  defaultDirectory(),
+			ReboundNeededThresholdMiB:  defaultReboundNeededThresholdMiB,
+			ReboundTriggerThresholdMiB: defaultReboundTriggerThresholdMiB,
+			MaxTransactionSize:         defaultMaxTransactionSize,
+			CheckInterval:              defaultCompactionInterval,
+		},
+		Timeout: defaultTimeout,
+		FSync:   false,
+	}
+}
+
+func createExtension(_ context.Context, set extension.Settings, cfg component.Config) (extension.Extension, error) {
+	return newLocalFileStorage(cfg.(*Config), set.TelemetrySettings.Logger), nil
+}
@@ -13,6 +13,10 @@ import (
 // Config has the configuration for the extension enabling the zPages extension.
 type Config struct {
 	confighttp.ServerConfig `mapstructure:",squash"`
+
+	// QueueAdminActions allows the sending queues of the exporters to be paused, resumed and purged from the queuez
+	// page. Anyone able to reach the endpoint can then drop the queued data, so it's disabled by default.
+	QueueAdminActions bool `mapstructure:"queue_admin_actions"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -56,15 +56,7 @@ func (zpe *zpagesExtension) Start(ctx context.Context, host component.Host) erro
 		zpe.telemetry.Logger.Warn("zPages span processor registration is not available")
 	}
 
-	hostZPages, ok := host.(interface {
-		RegisterZPages(mux *http.ServeMux, pathPrefix string)
-	})
-	if ok {
-		hostZPages.RegisterZPages(zPagesMux, "/debug")
-		zpe.telemetry.Logger.Info("Registered Host's zPages")
-	} else {
-		zpe.telemetry.Logger.Warn("Host's zPages not available")
-	}
+	zpe.registerHostZPages(host, zPagesMux)
 
 	// Start the listener here so we can have earlier failure if port is
 	// already in use.
@@ -90,6 +82,31 @@ func (zpe *zpagesExtension) Start(ctx context.Context, host component.Host) erro
 	return nil
 }
 
+// registerHostZPages registers the zPages of the host, with the queue admin actions if enabled.
+func (zpe *zpagesExtension) registerHostZPages(host component.Host, zPagesMux *http.ServeMux) {
+	if zpe.config.QueueAdminActions {
+		hostZPages, ok := host.(interface {
+			RegisterZPagesWithQueueAdminActions(mux *http.ServeMux, pathPrefix string)
+		})
+		if ok {
+			hostZPages.RegisterZPagesWithQueueAdminActions(zPagesMux, "/debug")
+			zpe.telemetry.Logger.Info("Registered Host's zPages with the queue admin actions")
+			return
+		}
+		zpe.telemetry.Logger.Warn("Host's zPages queue admin actions not available")
+	}
+
+	hostZPages, ok := host.(interface {
+		RegisterZPages(mux *http.ServeMux, pathPrefix string)
+	})
+	if ok {
+		hostZPages.RegisterZPages(zPagesMux, "/debug")
+		zpe.telemetry.Logger.Info("Registered Host's zPages")
+	} else {
+		zpe.telemetry.Logger.Warn("Host's zPages not available")
+	}
+}
+
 func (zpe *zpagesExtension) Shutdown(context.Context) error {
 	if zpe.server == nil {
 		return nil
@@ -31,6 +31,22 @@ func newZPagesHost() *zpagesHost {
 
 func (*zpagesHost) RegisterZPages(*http.ServeMux, string) {}
 
+// queueAdminHost records whether the zPages are registered with the queue admin actions.
+type queueAdminHost struct {
+	component.Host
+	registered   bool
+	adminActions bool
+}
+
+func (h *queueAdminHost) RegisterZPages(*http.ServeMux, string) {
+	h.registered = true
+}
+
+func (h *queueAdminHost) RegisterZPagesWithQueueAdminActions(*http.ServeMux, string) {
+	h.registered = true
+	h.adminActions = true
+}
+
 var _ registerableTracerProvider = (*registerableProvider)(nil)
 var _ registerableTracerProvider = sdktrace.NewTracerProvider()
 
@@ -49,7 +65,7 @@ func newZpagesTelemetrySettings() component.TelemetrySettings {
 
 func TestZPagesExtensionUsage(t *testing.T) {
 	cfg := &Config{
-		confighttp.ServerConfig{
+		ServerConfig: confighttp.ServerConfig{
 			Endpoint: testutil.GetAvailableLocalAddress(t),
 		},
 	}
@@ -74,9 +90,27 @@ func TestZPagesExtensionUsage(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+func TestZPagesExtensionQueueAdminActions(t *testing.T) {
+	for _, adminActions := range []bool{false, true} {
+		cfg := &Config{
+			ServerConfig: confighttp.ServerConfig{
+				Endpoint: testutil.GetAvailableLocalAddress(t),
+			},
+			QueueAdminActions: adminActions,
+		}
+		zpagesExt := newServer(cfg, newZpagesTelemetrySettings())
+		host := &queueAdminHost{Host: componenttest.NewNopHost()}
+		require.NoError(t, zpagesExt.Start(context.Background(), host))
+		require.NoError(t, zpagesExt.Shutdown(context.Background()))
+
+		require.True(t, host.registered)
+		require.Equal(t, adminActions, host.adminActions)
+	}
+}
+
 func TestZPagesExtensionBadAuthExtension(t *testing.T) {
 	cfg := &Config{
-		confighttp.ServerConfig{
+		ServerConfig: confighttp.ServerConfig{
 			Endpoint: "localhost:0",
 			Auth: &confighttp.AuthConfig{
 				Authentication: configauth.Authentication{
@@ -96,7 +130,7 @@ func TestZPagesExtensionPortAlreadyInUse(t *testing.T) {
 	defer ln.Close()
 
 	cfg := &Config{
-		confighttp.ServerConfig{
+		ServerConfig: confighttp.ServerConfig{
 			Endpoint: endpoint,
 		},
 	}
@@ -108,7 +142,7 @@ func TestZPagesExtensionPortAlreadyInUse(t *testing.T) {
 
 func TestZPagesMultipleStarts(t *testing.T) {
 	cfg := &Config{
-		confighttp.ServerConfig{
+		ServerConfig: confighttp.ServerConfig{
 			Endpoint: testutil.GetAvailableLocalAddress(t),
 		},
 	}
@@ -125,7 +159,7 @@ func TestZPagesMultipleStarts(t *testing.T) {
 
 func TestZPagesMultipleShutdowns(t *testing.T) {
 	cfg := &Config{
-		confighttp.ServerConfig{
+		ServerConfig: confighttp.ServerConfig{
 			Endpoint: testutil.GetAvailableLocalAddress(t),
 		},
 	}
@@ -140,7 +174,7 @@ func TestZPagesMultipleShutdowns(t *testing.T) {
 
 func TestZPagesShutdownWithoutStart(t *testing.T) {
 	cfg := &Config{
-		confighttp.ServerConfig{
+		ServerConfig: confighttp.ServerConfig{
 			Endpoint: testutil.GetAvailableLocalAddress(t),
 		},
 	}
@@ -30,6 +30,11 @@ const (
 	FailedToSendLogRecordsKey = "send_failed_log_records"
 	// FailedToEnqueueLogRecordsKey used to track logs that failed to be enqueued by exporters.
 	FailedToEnqueueLogRecordsKey = "enqueue_failed_log_records"
+
+	// SentSamplesKey used to track profile samples sent by exporters.
+	SentSamplesKey = "sent_samples"
+	// FailedToSendSamplesKey used to track profile samples that failed to be sent by exporters.
+	FailedToSendSamplesKey = "send_failed_samples"
 )
 
 var (
@@ -38,4 +43,5 @@ var (
 	ExportTraceDataOperationSuffix = SpanNameSep + "traces"
 	ExportMetricsOperationSuffix   = SpanNameSep + "metrics"
 	ExportLogsOperationSuffix      = SpanNameSep + "logs"
+	ExportProfilesOperationSuffix  = SpanNameSep + "profiles"
 )
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"reflect"
 	"sync/atomic"
 	"syscall"
 
@@ -88,6 +89,10 @@ type CollectorSettings struct {
 //   Collector can be shutdown if parser gets a shutdown error.
 // - Run runs runAndWaitForShutdownEvent and waits for a shutdown event.
 //   SIGINT and SIGTERM, errors, and (*Collector).Shutdown can trigger the shutdown events.
+// - Upon config changes and SIGHUP, reloadConfiguration re-resolves, validates and dry-runs the config. If it is
+//   invalid, the current config keeps running. Otherwise the changed pipelines are reloaded, or the service is
+//   restarted if the extensions or the telemetry changed. If the config fails to start, the last known-good config
+//   is restored.
 // - Upon shutdown, pipelines are notified, then pipelines and extensions are shut down.
 // - Users can call (*Collector).Shutdown anytime to shut down the collector.
 
@@ -97,6 +102,8 @@ type Collector struct {
 
 	configProvider ConfigProvider
 
+	// cfg is the configuration the service is running with.
+	cfg           *Config
 	serviceConfig *service.Config
 	service       *service.Service
 	state         *atomic.Int32
@@ -187,41 +194,50 @@ func (col *Collector) Shutdown() {
 func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
 	col.setCollectorState(StateStarting)
 
+	factories, cfg, err := col.loadConfiguration(ctx)
+	if err != nil {
+		return err
+	}
+	return col.startService(ctx, factories, cfg)
+}
+
+// loadConfiguration resolves the config through the config provider, and validates it.
+func (col *Collector) loadConfiguration(ctx context.Context) (Factories, *Config, error) {
 	factories, err := col.set.Factories()
 	if err != nil {
-		return fmt.Errorf("failed to initialize factories: %w", err)
+		return Factories{}, nil, fmt.Errorf("failed to initialize factories: %w", err)
 	}
 	cfg, err := col.configProvider.Get(ctx, factories)
 	if err != nil {
-		return fmt.Errorf("failed to get config: %w", err)
+		return Factories{}, nil, fmt.Errorf("failed to get config: %w", err)
 	}
 
 	if err = cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+		return Factories{}, nil, fmt.Errorf("invalid configuration: %w", err)
 	}
+	return factories, cfg, nil
+}
 
-	col.serviceConfig = &cfg.Service
-
-	conf := confmap.New()
-
-	if err = conf.Marshal(cfg); err != nil {
-		return fmt.Errorf("could not marshal configuration: %w", err)
+// startService creates the graph of the given config, and starts the components. If all the steps succeeds it
+// sets the col.service with the service currently running, and the col.cfg with the given config.
+func (col *Collector) startService(ctx context.Context, factories Factories, cfg *Config) error {
+	set, err := col.serviceSettings(factories, cfg)
+	if err != nil {
+		return err
 	}
 
-	col.service, err = service.New(ctx, service.Settings{
-		BuildInfo:         col.set.BuildInfo,
-		CollectorConf:     conf,
-		Receivers:         receiver.NewBuilder(cfg.Receivers, factories.Receivers),
-		Processors:        processor.NewBuilder(cfg.Processors, factories.Processors),
-		Exporters:         exporter.NewBuilder(cfg.Exporters, factories.Exporters),
-		Connectors:        connector.NewBuilder(cfg.Connectors, factories.Connectors),
-		Extensions:        extension.NewBuilder(cfg.Extensions, factories.Extensions),
-		AsyncErrorChannel: col.asyncErrorChannel,
-		LoggingOptions:    col.set.LoggingOptions,
-	}, cfg.Service)
+	// The logger of the service may read the col.serviceConfig while it is built, so it is set beforehand, and
+	// restored if the service fails to start.
+	lastServiceConfig := col.serviceConfig
+	col.serviceConfig = &cfg.Service
+
+	// The running service is kept on errors, to roll back to it.
+	srv, err := service.New(ctx, set, cfg.Service)
 	if err != nil {
+		col.serviceConfig = lastServiceConfig
 		return err
 	}
+	col.service = srv
 	if col.updateConfigProviderLogger != nil {
 		col.updateConfigProviderLogger(col.service.Logger().Core())
 	}
@@ -240,28 +256,98 @@ func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
 	}
 
 	if err = col.service.Start(ctx); err != nil {
+		col.serviceConfig = lastServiceConfig
 		return multierr.Combine(err, col.service.Shutdown(ctx))
 	}
+	col.cfg = cfg
 	col.setCollectorState(StateRunning)
 
 	return nil
 }
 
+// serviceSettings returns the settings of the service for the given configuration.
+func (col *Collector) serviceSettings(factories Factories, cfg *Config) (service.Settings, error) {
+	conf := confmap.New()
+	if err := conf.Marshal(cfg); err != nil {
+		return service.Settings{}, fmt.Errorf("could not marshal configuration: %w", err)
+	}
+
+	return service.Settings{
+		BuildInfo:         col.set.BuildInfo,
+		CollectorConf:     conf,
+		Receivers:         receiver.NewBuilder(cfg.Receivers, factories.Receivers),
+		Processors:        processor.NewBuilder(cfg.Processors, factories.Processors),
+		Exporters:         exporter.NewBuilder(cfg.Exporters, factories.Exporters),
+		Connectors:        connector.NewBuilder(cfg.Connectors, factories.Connectors),
+		Extensions:        extension.NewBuilder(cfg.Extensions, factories.Extensions),
+		AsyncErrorChannel: col.asyncErrorChannel,
+		LoggingOptions:    col.set.LoggingOptions,
+	}, nil
+}
+
+// reloadConfiguration resolves, validates and dry-runs the updated configuration before applying it. If it is
+// invalid, the service keeps running with the current configuration. If only the pipelines and their components
+// changed, the service reloads the changed components only, the others keep running, and the changed components
+// are rolled back if the new ones fail to start. Otherwise the service is restarted, and rolled back to the last
+// known-good configuration if the updated configuration fails to start. In both cases the failure is reported.
 func (col *Collector) reloadConfiguration(ctx context.Context) error {
+	factories, cfg, err := col.loadConfiguration(ctx)
+	if err != nil {
+		col.service.ReportReloadFailure(ctx, err)
+		return nil
+	}
+	set, err := col.serviceSettings(factories, cfg)
+	if err != nil {
+		col.service.ReportReloadFailure(ctx, err)
+		return nil
+	}
+	if err = service.Validate(ctx, set, cfg.Service); err != nil {
+		col.service.ReportReloadFailure(ctx, err)
+		return nil
+	}
+
+	if col.pipelinesOnlyChanged(cfg) {
+		col.service.Logger().Info("Config updated, reload pipelines")
+		if err = col.service.Reload(ctx, set, cfg.Service); err != nil {
+			// The pipelines roll back the components which changed, the others kept running.
+			col.service.ReportReloadFailure(ctx, err)
+			return nil
+		}
+		col.cfg = cfg
+		col.serviceConfig = &cfg.Service
+		return nil
+	}
+
 	col.service.Logger().Warn("Config updated, restart service")
+	lastCfg := col.cfg
 	col.setCollectorState(StateClosing)
 
-	if err := col.service.Shutdown(ctx); err != nil {
+	if err = col.service.Shutdown(ctx); err != nil {
 		return fmt.Errorf("failed to shutdown the retiring config: %w", err)
 	}
 
-	if err := col.setupConfigurationComponents(ctx); err != nil {
-		return fmt.Errorf("failed to setup configuration components: %w", err)
+	col.setCollectorState(StateStarting)
+	if err = col.startService(ctx, factories, cfg); err == nil {
+		return nil
 	}
 
+	col.service.Logger().Warn("Failed to start the updated config, roll back to the last known-good config", zap.Error(err))
+	if rollbackErr := col.startService(ctx, factories, lastCfg); rollbackErr != nil {
+		return fmt.Errorf("failed to setup configuration components: %w", errors.Join(err, rollbackErr))
+	}
+	col.service.ReportReloadFailure(ctx, err)
 	return nil
 }
 
+// pipelinesOnlyChanged reports whether the extensions and the telemetry of the updated configuration are the same
+// as the running ones, so that the pipelines can be reloaded without restarting the service.
+func (col *Collector) pipelinesOnlyChanged(cfg *Config) bool {
+	return col.cfg != nil &&
+		reflect.DeepEqual(col.cfg.Extensions, cfg.Extensions) &&
+		reflect.DeepEqual(col.cfg.Service.Extensions, cfg.Service.Extensions) &&
+		reflect.DeepEqual(col.cfg.Service.Telemetry, cfg.Service.Telemetry)
+}
+
 func (col *Collector) DryRun(ctx context.Context) error {
 	factories, err := col.set.Factories()
 	if err != nil {
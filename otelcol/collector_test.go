@@ -21,8 +21,10 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/extension/extensiontest"
 	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/collector/receiver"
 )
 
 func TestStateString(t *testing.T) {
@@ -115,6 +117,191 @@ func TestCollectorStateAfterConfigChange(t *testing.T) {
 	assert.Equal(t, StateClosed, col.GetState())
 }
 
+func TestCollectorReloadConfiguration(t *testing.T) {
+	tests := []struct {
+		name            string
+		updatedConfig   string
+		restartsService bool
+		pipelines       int
+	}{
+		{
+			name:            "pipelines changed",
+			updatedConfig:   "otelcol-nop-pipelines.yaml",
+			restartsService: false,
+			pipelines:       2,
+		},
+		{
+			name:            "telemetry and extensions changed",
+			updatedConfig:   "otelcol-nometrics.yaml",
+			restartsService: true,
+			pipelines:       1,
+		},
+		{
+			name:            "invalid config",
+			updatedConfig:   "otelcol-invalid.yaml",
+			restartsService: false,
+			pipelines:       3,
+		},
+		{
+			name:            "components cannot be created",
+			updatedConfig:   "otelcol-uncreatable-receiver.yaml",
+			restartsService: false,
+			pipelines:       3,
+		},
+		{
+			name:            "pipelines fail to start",
+			updatedConfig:   "otelcol-failing-receiver.yaml",
+			restartsService: false,
+			pipelines:       3,
+		},
+		{
+			name:            "service fails to start",
+			updatedConfig:   "otelcol-failing-receiver-telemetry.yaml",
+			restartsService: true,
+			pipelines:       3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col, err := NewCollector(CollectorSettings{
+				BuildInfo:              component.NewDefaultBuildInfo(),
+				Factories:              failingFactories,
+				ConfigProviderSettings: newDefaultConfigProviderSettings(t, []string{filepath.Join("testdata", "otelcol-nop.yaml")}),
+			})
+			require.NoError(t, err)
+			require.NoError(t, col.setupConfigurationComponents(context.Background()))
+			srv := col.service
+
+			col.configProvider, err = NewConfigProvider(newDefaultConfigProviderSettings(t, []string{filepath.Join("testdata", tt.updatedConfig)}))
+			require.NoError(t, err)
+			require.NoError(t, col.reloadConfiguration(context.Background()))
+
+			assert.Equal(t, StateRunning, col.GetState())
+			assert.Equal(t, tt.restartsService, srv != col.service)
+			assert.Len(t, col.serviceConfig.Pipelines, tt.pipelines)
+			require.NoError(t, col.shutdown(context.Background()))
+		})
+	}
+}
+
+func TestCollectorReloadFailureStatus(t *testing.T) {
+	unresolvableProviderSettings := ConfigProviderSettings{
+		ResolverSettings: confmap.ResolverSettings{
+			URIs: []string{"file:unresolvable.yaml"},
+			ProviderFactories: []confmap.ProviderFactory{
+				newFakeProvider("file", func(context.Context, string, confmap.WatcherFunc) (*confmap.Retrieved, error) {
+					return nil, errors.New("cannot retrieve the config")
+				}),
+			},
+		},
+	}
+	tests := []struct {
+		name                    string
+		updatedProviderSettings ConfigProviderSettings
+	}{
+		{
+			name:                    "config cannot be resolved",
+			updatedProviderSettings: unresolvableProviderSettings,
+		},
+		{
+			name:                    "invalid config",
+			updatedProviderSettings: newDefaultConfigProviderSettings(t, []string{filepath.Join("testdata", "otelcol-invalid.yaml")}),
+		},
+		{
+			name:                    "components cannot be created",
+			updatedProviderSettings: newDefaultConfigProviderSettings(t, []string{filepath.Join("testdata", "otelcol-uncreatable-receiver.yaml")}),
+		},
+		{
+			name:                    "service fails to start",
+			updatedProviderSettings: newDefaultConfigProviderSettings(t, []string{filepath.Join("testdata", "otelcol-failing-receiver.yaml")}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factories, err := failingFactories()
+			require.NoError(t, err)
+			unhealthyProcessorFactory := processortest.NewUnhealthyProcessorFactory()
+			factories.Processors[unhealthyProcessorFactory.Type()] = unhealthyProcessorFactory
+
+			// Keep track of the status changes of the configuration, which is not a component.
+			var statuses []component.Status
+			var mux sync.Mutex
+			onStatusChanged := func(source *component.InstanceID, event *component.StatusEvent) {
+				if source.ID != component.MustNewID("config") {
+					return
+				}
+				mux.Lock()
+				defer mux.Unlock()
+				statuses = append(statuses, event.Status())
+				if event.Status() == component.StatusRecoverableError {
+					assert.Error(t, event.Err())
+				}
+			}
+			factory := extensiontest.NewStatusWatcherExtensionFactory(onStatusChanged)
+			factories.Extensions[factory.Type()] = factory
+
+			providerSettings := newDefaultConfigProviderSettings(t, []string{filepath.Join("testdata", "otelcol-statuswatcher.yaml")})
+			col, err := NewCollector(CollectorSettings{
+				BuildInfo:              component.NewDefaultBuildInfo(),
+				Factories:              func() (Factories, error) { return factories, nil },
+				ConfigProviderSettings: providerSettings,
+			})
+			require.NoError(t, err)
+			require.NoError(t, col.setupConfigurationComponents(context.Background()))
+
+			col.configProvider, err = NewConfigProvider(tt.updatedProviderSettings)
+			require.NoError(t, err)
+			require.NoError(t, col.reloadConfiguration(context.Background()))
+			assert.Equal(t, StateRunning, col.GetState())
+
+			// The next successful reload reports the configuration OK.
+			col.configProvider, err = NewConfigProvider(providerSettings)
+			require.NoError(t, err)
+			require.NoError(t, col.reloadConfiguration(context.Background()))
+
+			mux.Lock()
+			assert.Equal(t, []component.Status{
+				component.StatusStarting,
+				component.StatusRecoverableError,
+				component.StatusOK,
+			}, statuses)
+			mux.Unlock()
+			require.NoError(t, col.shutdown(context.Background()))
+		})
+	}
+}
+
+// failingFactories returns the nop factories, and the factories of receivers failing to be created or to start.
+func failingFactories() (Factories, error) {
+	factories, err := nopFactories()
+	if err != nil {
+		return Factories{}, err
+	}
+	failingType := component.MustNewType("failing")
+	factories.Receivers[failingType] = receiver.NewFactory(failingType,
+		func() component.Config { return &struct{}{} },
+		receiver.WithTraces(func(context.Context, receiver.Settings, component.Config, consumer.Traces) (receiver.Traces, error) {
+			return &failingReceiver{}, nil
+		}, component.StabilityLevelDevelopment))
+	uncreatableType := component.MustNewType("uncreatable")
+	factories.Receivers[uncreatableType] = receiver.NewFactory(uncreatableType,
+		func() component.Config { return &struct{}{} },
+		receiver.WithTraces(func(context.Context, receiver.Settings, component.Config, consumer.Traces) (receiver.Traces, error) {
+			return nil, errors.New("cannot create receiver")
+		}, component.StabilityLevelDevelopment))
+	return factories, nil
+}
+
+type failingReceiver struct {
+	component.ShutdownFunc
+}
+
+func (failingReceiver) Start(context.Context, component.Host) error {
+	return errors.New("cannot start receiver")
+}
+
 func TestCollectorReportError(t *testing.T) {
 	col, err := NewCollector(CollectorSettings{
 		BuildInfo:              component.NewDefaultBuildInfo(),
@@ -250,6 +437,46 @@ func TestCollectorSendSignal(t *testing.T) {
 	assert.Equal(t, StateClosed, col.GetState())
 }
 
+func TestCollectorSendSignalReloadConfig(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	nopCfg, err := os.ReadFile(filepath.Join("testdata", "otelcol-nop.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cfgPath, nopCfg, 0600))
+
+	col, err := NewCollector(CollectorSettings{
+		BuildInfo:              component.NewDefaultBuildInfo(),
+		Factories:              nopFactories,
+		ConfigProviderSettings: newDefaultConfigProviderSettings(t, []string{cfgPath}),
+	})
+	require.NoError(t, err)
+
+	wg := startCollector(context.Background(), t, col)
+
+	assert.Eventually(t, func() bool {
+		return StateRunning == col.GetState()
+	}, 2*time.Second, 200*time.Millisecond)
+
+	// The updated config is invalid, the current one keeps running.
+	invalidCfg, err := os.ReadFile(filepath.Join("testdata", "otelcol-invalid.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cfgPath, invalidCfg, 0600))
+	col.signalsChannel <- syscall.SIGHUP
+
+	assert.Never(t, func() bool {
+		return StateRunning != col.GetState()
+	}, 1*time.Second, 100*time.Millisecond)
+
+	pipelinesCfg, err := os.ReadFile(filepath.Join("testdata", "otelcol-nop-pipelines.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cfgPath, pipelinesCfg, 0600))
+	col.signalsChannel <- syscall.SIGHUP
+	col.signalsChannel <- syscall.SIGTERM
+
+	wg.Wait()
+	assert.Equal(t, StateClosed, col.GetState())
+	assert.Len(t, col.serviceConfig.Pipelines, 2)
+}
+
 func TestCollectorFailedShutdown(t *testing.T) {
 	t.Skip("This test was using telemetry shutdown failure, switch to use a component that errors on shutdown.")
 
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol // import "go.opentelemetry.io/collector/otelcol"
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// redactedValue is the value configopaque.String values are marshaled to.
+const redactedValue = "[REDACTED]"
+
+// newPrintConfigSubCommand constructs a new print-config sub command using the given CollectorSettings.
+func newPrintConfigSubCommand(set CollectorSettings, flagSet *flag.FlagSet) *cobra.Command {
+	var format string
+	var withDefaults bool
+	printConfigCmd := &cobra.Command{
+		Use:   "print-config",
+		Short: "Outputs the resolved config without running the collector",
+		Long: `Outputs the configuration resolved from all the config URIs and --set flags, after the expansions and
+the converters ran. The values of the opaque settings, such as passwords and tokens, are redacted.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := updateSettingsUsingFlags(&set, flagSet); err != nil {
+				return err
+			}
+			factories, err := set.Factories()
+			if err != nil {
+				return fmt.Errorf("failed to initialize factories: %w", err)
+			}
+			resolver, err := confmap.NewResolver(set.ConfigProviderSettings.ResolverSettings)
+			if err != nil {
+				return err
+			}
+			conf, err := resolver.Resolve(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("cannot resolve the configuration: %w", err)
+			}
+			if err = resolver.Shutdown(cmd.Context()); err != nil {
+				return err
+			}
+
+			resolved, err := printedConfig(conf, factories, withDefaults)
+			if err != nil {
+				return err
+			}
+
+			var data []byte
+			switch format {
+			case "yaml":
+				data, err = yaml.Marshal(resolved)
+			case "json":
+				data, err = json.MarshalIndent(resolved, "", "  ")
+				data = append(data, '\n')
+			default:
+				return fmt.Errorf("unsupported format %q, must be one of: yaml, json", format)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(data))
+			return nil
+		},
+	}
+	printConfigCmd.Flags().StringVar(&format, "format", "yaml", "Output format of the config: yaml or json.")
+	printConfigCmd.Flags().BoolVar(&withDefaults, "with-defaults", false, "Include the default config of the components.")
+	printConfigCmd.Flags().AddGoFlagSet(flagSet)
+	return printConfigCmd
+}
+
+// printedConfig returns the resolved configuration to print, with the opaque values redacted. The configuration is
+// unmarshaled to the configs of the components, and marshaled back, so that the values of the configopaque.String
+// settings are known. If withDefaults is set, the resolved configuration is merged over the marshaled configs, which
+// include the defaults of the components.
+func printedConfig(conf *confmap.Conf, factories Factories, withDefaults bool) (map[string]any, error) {
+	cfg, err := unmarshal(conf, factories)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal the configuration: %w", err)
+	}
+	typed := confmap.New()
+	if err = typed.Marshal(cfg.config()); err != nil {
+		return nil, fmt.Errorf("could not marshal configuration: %w", err)
+	}
+
+	typedMap := typed.ToStringMap()
+	resolved := redact(conf.ToStringMap(), typedMap).(map[string]any)
+	if !withDefaults {
+		return resolved, nil
+	}
+	return mergeDefaults(typedMap, resolved).(map[string]any), nil
+}
+
+// redact replaces the values of the resolved configuration whose marshaled value is redacted.
+func redact(resolved, marshaled any) any {
+	switch r := resolved.(type) {
+	case map[string]any:
+		m, _ := marshaled.(map[string]any)
+		redacted := make(map[string]any, len(r))
+		for k, v := range r {
+			redacted[k] = redact(v, m[k])
+		}
+		return redacted
+	case []any:
+		m, _ := marshaled.([]any)
+		redacted := make([]any, len(r))
+		for i, v := range r {
+			var mv any
+			if i < len(m) {
+				mv = m[i]
+			}
+			redacted[i] = redact(v, mv)
+		}
+		return redacted
+	}
+	if marshaled == redactedValue {
+		return redactedValue
+	}
+	return resolved
+}
+
+// mergeDefaults merges the resolved configuration over the defaults. The empty values of the resolved
+// configuration, such as a component configured without settings, keep the defaults.
+func mergeDefaults(defaults, resolved any) any {
+	if resolved == nil {
+		return defaults
+	}
+	r, ok := resolved.(map[string]any)
+	if !ok {
+		return resolved
+	}
+	d, ok := defaults.(map[string]any)
+	if !ok {
+		return resolved
+	}
+	merged := make(map[string]any, len(d)+len(r))
+	for k, v := range d {
+		merged[k] = v
+	}
+	for k, v := range r {
+		merged[k] = mergeDefaults(d[k], v)
+	}
+	return merged
+}
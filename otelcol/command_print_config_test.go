@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+type secretReceiverConfig struct {
+	Endpoint string                         `mapstructure:"endpoint"`
+	Token    configopaque.String            `mapstructure:"token"`
+	Headers  map[string]configopaque.String `mapstructure:"headers"`
+	Timeout  string                         `mapstructure:"timeout"`
+}
+
+// secretFactories returns the nop factories, and a "secret" receiver whose config has opaque settings.
+func secretFactories() (Factories, error) {
+	factories, err := nopFactories()
+	if err != nil {
+		return Factories{}, err
+	}
+	secretFactory := receiver.NewFactory(component.MustNewType("secret"), func() component.Config {
+		return &secretReceiverConfig{Endpoint: "localhost:1234", Timeout: "5s"}
+	})
+	factories.Receivers[secretFactory.Type()] = secretFactory
+	return factories, nil
+}
+
+// executePrintConfig executes the print-config sub command with the args, and returns its output.
+func executePrintConfig(t *testing.T, args ...string) ([]byte, error) {
+	filePath := filepath.Join("testdata", "otelcol-print-config.yaml")
+	fileProvider := newFakeProvider("file", func(_ context.Context, _ string, _ confmap.WatcherFunc) (*confmap.Retrieved, error) {
+		return confmap.NewRetrieved(newConfFromFile(t, filePath))
+	})
+	cmd := newPrintConfigSubCommand(CollectorSettings{Factories: secretFactories, ConfigProviderSettings: ConfigProviderSettings{
+		ResolverSettings: confmap.ResolverSettings{
+			URIs:              []string{filePath},
+			ProviderFactories: []confmap.ProviderFactory{fileProvider},
+			DefaultScheme:     "file",
+		},
+	}}, flags(featuregate.GlobalRegistry()))
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.Bytes(), err
+}
+
+// executePrintConfigYAML executes the print-config sub command with the args, and returns its parsed YAML output.
+func executePrintConfigYAML(t *testing.T, args ...string) map[string]any {
+	out, err := executePrintConfig(t, args...)
+	require.NoError(t, err)
+
+	var printed map[string]any
+	require.NoError(t, yaml.Unmarshal(out, &printed))
+	return printed
+}
+
+func TestPrintConfigSubCommandNoConfig(t *testing.T) {
+	cmd := newPrintConfigSubCommand(CollectorSettings{Factories: nopFactories}, flags(featuregate.GlobalRegistry()))
+	err := cmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "at least one config flag must be provided")
+}
+
+func TestPrintConfigSubCommand(t *testing.T) {
+	printed := executePrintConfigYAML(t)
+	assert.Equal(t, map[string]any{
+		"receivers": map[string]any{
+			"secret": map[string]any{
+				"endpoint": "localhost:4317",
+				"token":    "[REDACTED]",
+				"headers":  map[string]any{"authorization": "[REDACTED]"},
+			},
+			"nop": nil,
+		},
+		"exporters": map[string]any{"nop": nil},
+		"service": map[string]any{
+			"pipelines": map[string]any{
+				"traces": map[string]any{
+					"receivers": []any{"secret", "nop"},
+					"exporters": []any{"nop"},
+				},
+			},
+		},
+	}, printed)
+}
+
+func TestPrintConfigSubCommandJSON(t *testing.T) {
+	out, err := executePrintConfig(t, "--format", "json")
+	require.NoError(t, err)
+
+	var printed map[string]any
+	require.NoError(t, json.Unmarshal(out, &printed))
+	assert.Equal(t, map[string]any{
+		"endpoint": "localhost:4317",
+		"token":    "[REDACTED]",
+		"headers":  map[string]any{"authorization": "[REDACTED]"},
+	}, printed["receivers"].(map[string]any)["secret"])
+}
+
+func TestPrintConfigSubCommandWithDefaults(t *testing.T) {
+	printed := executePrintConfigYAML(t, "--with-defaults")
+	assert.Equal(t, map[string]any{
+		"endpoint": "localhost:4317",
+		"token":    "[REDACTED]",
+		"headers":  map[string]any{"authorization": "[REDACTED]"},
+		"timeout":  "5s",
+	}, printed["receivers"].(map[string]any)["secret"])
+	// The defaults of the service are included.
+	assert.Contains(t, printed["service"].(map[string]any), "telemetry")
+}
+
+func TestPrintConfigSubCommandInvalidFormat(t *testing.T) {
+	_, err := executePrintConfig(t, "--format", "toml")
+	require.EqualError(t, err, `unsupported format "toml", must be one of: yaml, json`)
+}
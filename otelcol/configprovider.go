@@ -99,8 +99,6 @@ func NewConfigProvider(set ConfigProviderSettings) (ConfigProvider, error) {
 
 func (cm *configProvider) Get(ctx context.Context, factories Factories) (*Config, error) {
 	conf, err := cm.mapResolver.Resolve(ctx)
-	fmt.Println("factories", factories)
-	fmt.Println("conf", conf)
 	if err != nil {
 		return nil, fmt.Errorf("cannot resolve the configuration: %w", err)
 	}
@@ -128,17 +126,8 @@ func (cm *configProvider) Get(ctx context.Context, factories Factories) (*Config
 
 		return nil, err
 	}
-	fmt.Println("cfg", cfg)
-	fmt.Println("cfg.Receivers", cfg.Receivers.Configs())
-
-	return &Config{
-		Receivers:  cfg.Receivers.Configs(),
-		Processors: cfg.Processors.Configs(),
-		Exporters:  cfg.Exporters.Configs(),
-		Connectors: cfg.Connectors.Configs(),
-		Extensions: cfg.Extensions.Configs(),
-		Service:    cfg.Service,
-	}, nil
+
+	return cfg.config(), nil
 }
 
 func (cm *configProvider) Watch() <-chan error {
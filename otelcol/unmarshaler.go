@@ -46,3 +46,15 @@ func unmarshal(v *confmap.Conf, factories Factories) (*configSettings, error) {
 
 	return cfg, v.Unmarshal(&cfg)
 }
+
+// config returns the Config of the configSettings.
+func (cfg *configSettings) config() *Config {
+	return &Config{
+		Receivers:  cfg.Receivers.Configs(),
+		Processors: cfg.Processors.Configs(),
+		Exporters:  cfg.Exporters.Configs(),
+		Connectors: cfg.Connectors.Configs(),
+		Extensions: cfg.Extensions.Configs(),
+		Service:    cfg.Service,
+	}
+}
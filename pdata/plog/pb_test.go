@@ -32,6 +32,27 @@ func TestProtoSizer(t *testing.T) {
 
 }
 
+func TestProtoSizerElements(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	ld := NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	sl := rl.ScopeLogs().AppendEmpty()
+
+	// Each element is a length-delimited field of its parent, taking one byte for the tag and one for the length.
+	slSize := marshaler.ScopeLogsSize(sl)
+	lr := sl.LogRecords().AppendEmpty()
+	lr.SetSeverityText("error")
+	assert.Equal(t, slSize+2+marshaler.LogRecordSize(lr), marshaler.ScopeLogsSize(sl))
+
+	rlSize := marshaler.ResourceLogsSize(rl)
+	sl = rl.ScopeLogs().AppendEmpty()
+	assert.Equal(t, rlSize+2+marshaler.ScopeLogsSize(sl), marshaler.ResourceLogsSize(rl))
+
+	ldSize := marshaler.LogsSize(ld)
+	rl = ld.ResourceLogs().AppendEmpty()
+	assert.Equal(t, ldSize+2+marshaler.ResourceLogsSize(rl), marshaler.LogsSize(ld))
+}
+
 func TestProtoSizerEmptyLogs(t *testing.T) {
 	sizer := &ProtoMarshaler{}
 	assert.Equal(t, 0, sizer.LogsSize(NewLogs()))
@@ -31,6 +31,56 @@ func TestProtoSizer(t *testing.T) {
 	assert.Equal(t, len(bytes), size)
 }
 
+func TestProtoSizerElements(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	md := NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	sm := rm.ScopeMetrics().AppendEmpty()
+	m := sm.Metrics().AppendEmpty()
+	m.SetName("foo")
+
+	// Each element is a length-delimited field of its parent, taking one byte for the tag and one for the length.
+	// The data points are held by the gauge, itself a field of the metric.
+	mSize := marshaler.MetricSize(m)
+	ndp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	ndp.SetIntValue(1)
+	assert.Equal(t, mSize+2+2+marshaler.NumberDataPointSize(ndp), marshaler.MetricSize(m))
+
+	mSize = marshaler.MetricSize(m)
+	ndp = m.Gauge().DataPoints().AppendEmpty()
+	assert.Equal(t, mSize+2+marshaler.NumberDataPointSize(ndp), marshaler.MetricSize(m))
+
+	m = sm.Metrics().AppendEmpty()
+	mSize = marshaler.MetricSize(m)
+	hdp := m.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetCount(1)
+	assert.Equal(t, mSize+2+2+marshaler.HistogramDataPointSize(hdp), marshaler.MetricSize(m))
+
+	m = sm.Metrics().AppendEmpty()
+	mSize = marshaler.MetricSize(m)
+	ehdp := m.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	ehdp.SetCount(1)
+	assert.Equal(t, mSize+2+2+marshaler.ExponentialHistogramDataPointSize(ehdp), marshaler.MetricSize(m))
+
+	m = sm.Metrics().AppendEmpty()
+	mSize = marshaler.MetricSize(m)
+	sdp := m.SetEmptySummary().DataPoints().AppendEmpty()
+	sdp.SetCount(1)
+	assert.Equal(t, mSize+2+2+marshaler.SummaryDataPointSize(sdp), marshaler.MetricSize(m))
+
+	smSize := marshaler.ScopeMetricsSize(sm)
+	m = sm.Metrics().AppendEmpty()
+	assert.Equal(t, smSize+2+marshaler.MetricSize(m), marshaler.ScopeMetricsSize(sm))
+
+	rmSize := marshaler.ResourceMetricsSize(rm)
+	sm = rm.ScopeMetrics().AppendEmpty()
+	assert.Equal(t, rmSize+2+marshaler.ScopeMetricsSize(sm), marshaler.ResourceMetricsSize(rm))
+
+	mdSize := marshaler.MetricsSize(md)
+	rm = md.ResourceMetrics().AppendEmpty()
+	assert.Equal(t, mdSize+2+marshaler.ResourceMetricsSize(rm), marshaler.MetricsSize(md))
+}
+
 func TestProtoSizerEmptyMetrics(t *testing.T) {
 	sizer := &ProtoMarshaler{}
 	assert.Equal(t, 0, sizer.MetricsSize(NewMetrics()))
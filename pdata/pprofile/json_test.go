@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pprofile
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Marshaler = (*JSONMarshaler)(nil)
+var _ Unmarshaler = (*JSONUnmarshaler)(nil)
+
+func TestProfilesJSON(t *testing.T) {
+	pd := NewProfiles()
+	fillTestResourceProfilesSlice(pd.ResourceProfiles())
+
+	encoder := &JSONMarshaler{}
+	jsonBuf, err := encoder.MarshalProfiles(pd)
+	require.NoError(t, err)
+	decoder := &JSONUnmarshaler{}
+	got, err := decoder.UnmarshalProfiles(jsonBuf)
+	require.NoError(t, err)
+	assert.EqualValues(t, pd, got)
+}
+
+var profilesJSON = `{"resourceProfiles":[{"resource":{"attributes":[{"key":"host.name","value":{"stringValue":"testHost"}}]},"scopeProfiles":[{"scope":{"name":"name","version":"version"},"profiles":[{"profileId":"AQIDBAUGBwgJCgsMDQ4PEA==","startTimeUnixNano":"1684617382541971000","profile":{"sampleType":[{"type":"1","unit":"2","aggregationTemporality":1}],"sample":[{"locationsLength":"1","value":["10"]}],"location":[{"mappingIndex":"0","line":[{"functionIndex":"0","line":"42"}]}],"function":[{"name":"3"}],"stringTable":["","cpu","nanoseconds","main"],"periodType":{}}}],"schemaUrl":"scope_schema"}],"schemaUrl":"resource_schema"}]}`
+
+func TestJSONUnmarshal(t *testing.T) {
+	decoder := &JSONUnmarshaler{}
+	pd, err := decoder.UnmarshalProfiles([]byte(profilesJSON))
+	require.NoError(t, err)
+	assert.Equal(t, 1, pd.SampleCount())
+
+	rp := pd.ResourceProfiles().At(0)
+	assert.Equal(t, "resource_schema", rp.SchemaUrl())
+	sp := rp.ScopeProfiles().At(0)
+	assert.Equal(t, "scope_schema", sp.SchemaUrl())
+	assert.Equal(t, "name", sp.Scope().Name())
+	pc := sp.Profiles().At(0)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, pc.ProfileID().AsRaw())
+	profile := pc.Profile()
+	assert.EqualValues(t, 1, profile.SampleType().At(0).AggregationTemporality())
+	assert.Equal(t, []int64{10}, profile.Sample().At(0).Value().AsRaw())
+	assert.Equal(t, int64(42), profile.Location().At(0).Line().At(0).Line())
+	assert.Equal(t, "main", profile.StringTable().At(int(profile.Function().At(0).Name())))
+}
+
+func TestJSONUnmarshalInvalid(t *testing.T) {
+	jsonStr := `{"resourceProfiles": [{"scopeProfiles": [{"profiles": [{"profileId": "!!"}]}]}]}`
+	decoder := &JSONUnmarshaler{}
+	_, err := decoder.UnmarshalProfiles([]byte(jsonStr))
+	assert.Error(t, err)
+}
+
+func TestUnmarshalJsoniterProfileUnknownField(t *testing.T) {
+	jsonStr := `{"extra":"", "profile": {"extra": "", "sample": [{"extra": ""}], "mapping": [{"extra": ""}], "location": [{"extra": "", "line": [{"extra": ""}]}], "function": [{"extra": ""}], "attributeUnits": [{"extra": ""}], "linkTable": [{"extra": ""}], "periodType": {"extra": ""}}}`
+	iter := jsoniter.ConfigFastest.BorrowIterator([]byte(jsonStr))
+	defer jsoniter.ConfigFastest.ReturnIterator(iter)
+	val := NewProfileContainer()
+	val.unmarshalJsoniter(iter)
+	require.NoError(t, iter.Error)
+	assert.Equal(t, 1, val.Profile().Sample().Len())
+	assert.Equal(t, 1, val.Profile().Location().At(0).Line().Len())
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pprofile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtoProfilesUnmarshalerError(t *testing.T) {
+	p := &ProtoUnmarshaler{}
+	_, err := p.UnmarshalProfiles([]byte("+$%"))
+	assert.Error(t, err)
+}
+
+func TestProtoSizer(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	pd := NewProfiles()
+	pd.ResourceProfiles().AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty().Profile().Sample().AppendEmpty().Value().Append(1)
+
+	size := marshaler.ProfilesSize(pd)
+
+	bytes, err := marshaler.MarshalProfiles(pd)
+	require.NoError(t, err)
+	assert.Equal(t, len(bytes), size)
+}
+
+func TestProtoSizerElements(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	pd := NewProfiles()
+	rp := pd.ResourceProfiles().AppendEmpty()
+	sp := rp.ScopeProfiles().AppendEmpty()
+
+	// Each element is a length-delimited field of its parent, taking one byte for the tag and one for the length.
+	spSize := marshaler.ScopeProfilesSize(sp)
+	pc := sp.Profiles().AppendEmpty()
+	pc.SetDroppedAttributesCount(1)
+	assert.Equal(t, spSize+2+marshaler.ProfileContainerSize(pc), marshaler.ScopeProfilesSize(sp))
+
+	rpSize := marshaler.ResourceProfilesSize(rp)
+	sp = rp.ScopeProfiles().AppendEmpty()
+	assert.Equal(t, rpSize+2+marshaler.ScopeProfilesSize(sp), marshaler.ResourceProfilesSize(rp))
+
+	pdSize := marshaler.ProfilesSize(pd)
+	rp = pd.ResourceProfiles().AppendEmpty()
+	assert.Equal(t, pdSize+2+marshaler.ResourceProfilesSize(rp), marshaler.ProfilesSize(pd))
+}
+
+func TestProtoSizerEmptyProfiles(t *testing.T) {
+	sizer := &ProtoMarshaler{}
+	assert.Equal(t, 0, sizer.ProfilesSize(NewProfiles()))
+}
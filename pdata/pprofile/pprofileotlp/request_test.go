@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pprofileotlp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ json.Unmarshaler = ExportRequest{}
+var _ json.Marshaler = ExportRequest{}
+
+var profilesRequestJSON = []byte(`
+	{
+		"resourceProfiles": [
+		{
+			"resource": {},
+			"scopeProfiles": [
+				{
+					"scope": {},
+					"profiles": [
+						{
+							"profileId": "AQIDBAUGBwgJCgsMDQ4PEA==",
+							"profile": {
+								"sample": [
+									{
+										"value": ["1"]
+									}
+								],
+								"stringTable": ["", "samples"],
+								"periodType": {}
+							}
+						}
+					]
+				}
+			]
+		}
+		]
+	}`)
+
+func TestRequestToPData(t *testing.T) {
+	tr := NewExportRequest()
+	assert.Equal(t, tr.Profiles().SampleCount(), 0)
+	tr.Profiles().ResourceProfiles().AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty().Profile().Sample().AppendEmpty()
+	assert.Equal(t, tr.Profiles().SampleCount(), 1)
+}
+
+func TestRequestJSON(t *testing.T) {
+	pr := NewExportRequest()
+	assert.NoError(t, pr.UnmarshalJSON(profilesRequestJSON))
+	profile := pr.Profiles().ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0).Profile()
+	assert.Equal(t, []int64{1}, profile.Sample().At(0).Value().AsRaw())
+	assert.Equal(t, []string{"", "samples"}, profile.StringTable().AsRaw())
+
+	got, err := pr.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join(strings.Fields(string(profilesRequestJSON)), ""), string(got))
+}
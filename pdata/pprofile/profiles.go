@@ -40,6 +40,23 @@ func (ms Profiles) CopyTo(dest Profiles) {
 	ms.ResourceProfiles().CopyTo(dest.ResourceProfiles())
 }
 
+// SampleCount calculates the total number of samples.
+func (ms Profiles) SampleCount() int {
+	sampleCount := 0
+	rps := ms.ResourceProfiles()
+	for i := 0; i < rps.Len(); i++ {
+		rp := rps.At(i)
+		sps := rp.ScopeProfiles()
+		for j := 0; j < sps.Len(); j++ {
+			pcs := sps.At(j).Profiles()
+			for k := 0; k < pcs.Len(); k++ {
+				sampleCount += pcs.At(k).Profile().Sample().Len()
+			}
+		}
+	}
+	return sampleCount
+}
+
 // ResourceProfiles returns the ResourceProfilesSlice associated with this Profiles.
 func (ms Profiles) ResourceProfiles() ResourceProfilesSlice {
 	return newResourceProfilesSlice(&ms.getOrig().ResourceProfiles, internal.GetProfilesState(internal.Profiles(ms)))
@@ -22,6 +22,28 @@ func TestReadOnlyProfilesInvalidUsage(t *testing.T) {
 	assert.Panics(t, func() { res.Attributes().PutStr("k2", "v2") })
 }
 
+func TestSampleCount(t *testing.T) {
+	profiles := NewProfiles()
+	assert.EqualValues(t, 0, profiles.SampleCount())
+
+	rp := profiles.ResourceProfiles().AppendEmpty()
+	assert.EqualValues(t, 0, profiles.SampleCount())
+
+	sp := rp.ScopeProfiles().AppendEmpty()
+	assert.EqualValues(t, 0, profiles.SampleCount())
+
+	sp.Profiles().AppendEmpty().Profile().Sample().AppendEmpty()
+	assert.EqualValues(t, 1, profiles.SampleCount())
+
+	rps := profiles.ResourceProfiles()
+	rps.AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty()
+	samples := rps.AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty().Profile().Sample()
+	for i := 0; i < 5; i++ {
+		samples.AppendEmpty()
+	}
+	assert.EqualValues(t, 6, profiles.SampleCount())
+}
+
 func BenchmarkProfilesUsage(b *testing.B) {
 	profiles := NewProfiles()
 	fillTestResourceProfilesSlice(profiles.ResourceProfiles())
@@ -32,6 +32,27 @@ func TestProtoSizer(t *testing.T) {
 	assert.Equal(t, len(bytes), size)
 }
 
+func TestProtoSizerElements(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	td := NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	ss := rs.ScopeSpans().AppendEmpty()
+
+	// Each element is a length-delimited field of its parent, taking one byte for the tag and one for the length.
+	ssSize := marshaler.ScopeSpansSize(ss)
+	span := ss.Spans().AppendEmpty()
+	span.SetName("foo")
+	assert.Equal(t, ssSize+2+marshaler.SpanSize(span), marshaler.ScopeSpansSize(ss))
+
+	rsSize := marshaler.ResourceSpansSize(rs)
+	ss = rs.ScopeSpans().AppendEmpty()
+	assert.Equal(t, rsSize+2+marshaler.ScopeSpansSize(ss), marshaler.ResourceSpansSize(rs))
+
+	tdSize := marshaler.TracesSize(td)
+	rs = td.ResourceSpans().AppendEmpty()
+	assert.Equal(t, tdSize+2+marshaler.ResourceSpansSize(rs), marshaler.TracesSize(td))
+}
+
 func TestProtoSizerEmptyTraces(t *testing.T) {
 	sizer := &ProtoMarshaler{}
 	assert.Equal(t, 0, sizer.TracesSize(NewTraces()))
@@ -37,10 +37,12 @@ func fillProfileOne(profile pprofile.ProfileContainer) {
 	profile.SetStartTime(profileStartTimestamp)
 	profile.SetEndTime(profileEndTimestamp)
 	profile.SetDroppedAttributesCount(1)
+	profile.Profile().Sample().AppendEmpty().Value().Append(4)
 }
 
 func fillProfileTwo(profile pprofile.ProfileContainer) {
 	profile.ProfileID().FromRaw([]byte("profileB"))
 	profile.SetStartTime(profileStartTimestamp)
 	profile.SetEndTime(profileEndTimestamp)
+	profile.Profile().Sample().AppendEmpty().Value().Append(9)
 }
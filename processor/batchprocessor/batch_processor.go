@@ -20,8 +20,10 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
 )
@@ -108,6 +110,7 @@ type batch interface {
 var _ consumer.Traces = (*batchProcessor)(nil)
 var _ consumer.Metrics = (*batchProcessor)(nil)
 var _ consumer.Logs = (*batchProcessor)(nil)
+var _ consumerprofiles.Profiles = (*batchProcessor)(nil)
 
 // newBatchProcessor returns a new batch processor component.
 func newBatchProcessor(set processor.Settings, cfg *Config, batchFunc func() batch) (*batchProcessor, error) {
@@ -356,6 +359,11 @@ func (bp *batchProcessor) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	return bp.batcher.consume(ctx, ld)
 }
 
+// ConsumeProfiles implements ProfilesProcessor
+func (bp *batchProcessor) ConsumeProfiles(ctx context.Context, pd pprofile.Profiles) error {
+	return bp.batcher.consume(ctx, pd)
+}
+
 // newBatchTracesProcessor creates a new batch processor that batches traces by size or with timeout
 func newBatchTracesProcessor(set processor.Settings, next consumer.Traces, cfg *Config) (*batchProcessor, error) {
 	return newBatchProcessor(set, cfg, func() batch { return newBatchTraces(next) })
@@ -371,6 +379,11 @@ func newBatchLogsProcessor(set processor.Settings, next consumer.Logs, cfg *Conf
 	return newBatchProcessor(set, cfg, func() batch { return newBatchLogs(next) })
 }
 
+// newBatchProfilesProcessor creates a new batch processor that batches profiles by size or with timeout
+func newBatchProfilesProcessor(set processor.Settings, next consumerprofiles.Profiles, cfg *Config) (*batchProcessor, error) {
+	return newBatchProcessor(set, cfg, func() batch { return newBatchProfiles(next) })
+}
+
 type batchTraces struct {
 	nextConsumer consumer.Traces
 	traceData    ptrace.Traces
@@ -510,3 +523,53 @@ func (bl *batchLogs) add(item any) {
 	bl.logCount += newLogsCount
 	ld.ResourceLogs().MoveAndAppendTo(bl.logData.ResourceLogs())
 }
+
+type batchProfiles struct {
+	nextConsumer consumerprofiles.Profiles
+	profileData  pprofile.Profiles
+	sampleCount  int
+	sizer        pprofile.Sizer
+}
+
+func newBatchProfiles(nextConsumer consumerprofiles.Profiles) *batchProfiles {
+	return &batchProfiles{nextConsumer: nextConsumer, profileData: pprofile.NewProfiles(), sizer: &pprofile.ProtoMarshaler{}}
+}
+
+func (bp *batchProfiles) export(ctx context.Context, sendBatchMaxSize int, returnBytes bool) (int, int, error) {
+	var req pprofile.Profiles
+	var sent int
+	var bytes int
+
+	if sendBatchMaxSize > 0 && bp.sampleCount > sendBatchMaxSize {
+		req = splitProfiles(sendBatchMaxSize, bp.profileData)
+		bp.sampleCount -= sendBatchMaxSize
+		sent = sendBatchMaxSize
+	} else {
+		req = bp.profileData
+		sent = bp.sampleCount
+		bp.profileData = pprofile.NewProfiles()
+		bp.sampleCount = 0
+	}
+	if returnBytes {
+		bytes = bp.sizer.ProfilesSize(req)
+	}
+	return sent, bytes, bp.nextConsumer.ConsumeProfiles(ctx, req)
+}
+
+func (bp *batchProfiles) itemCount() int {
+	// The profiles without samples, which may only carry their original payload, count as one item so they are sent.
+	if bp.sampleCount == 0 && bp.profileData.ResourceProfiles().Len() > 0 {
+		return 1
+	}
+	return bp.sampleCount
+}
+
+func (bp *batchProfiles) add(item any) {
+	pd := item.(pprofile.Profiles)
+
+	if pd.ResourceProfiles().Len() == 0 {
+		return
+	}
+	bp.sampleCount += pd.SampleCount()
+	pd.ResourceProfiles().MoveAndAppendTo(bp.profileData.ResourceProfiles())
+}
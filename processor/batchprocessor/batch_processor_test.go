@@ -24,6 +24,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/testdata"
 	"go.opentelemetry.io/collector/processor/processortest"
@@ -1152,6 +1153,127 @@ func logsReceivedBySeverityText(lds []plog.Logs) map[string]plog.LogRecord {
 	return logsReceivedBySeverityText
 }
 
+func TestBatchProfileProcessor_ReceivingData(t *testing.T) {
+	// Instantiate the batch processor with low config values to test data
+	// gets sent through the processor.
+	cfg := Config{
+		Timeout:       200 * time.Millisecond,
+		SendBatchSize: 50,
+	}
+
+	requestCount := 100
+	profilesPerRequest := 5
+	sink := new(consumertest.ProfilesSink)
+
+	creationSet := processortest.NewNopSettings()
+	creationSet.MetricsLevel = configtelemetry.LevelDetailed
+	batcher, err := newBatchProfilesProcessor(creationSet, sink, &cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		pd := testdata.GenerateProfiles(profilesPerRequest)
+		profiles := pd.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles()
+		for profileIndex := 0; profileIndex < profilesPerRequest; profileIndex++ {
+			profiles.At(profileIndex).ProfileID().FromRaw(getTestProfileID(requestNum, profileIndex))
+		}
+		assert.NoError(t, batcher.ConsumeProfiles(context.Background(), pd))
+	}
+
+	// Added to test case with empty resources sent.
+	pd := pprofile.NewProfiles()
+	assert.NoError(t, batcher.ConsumeProfiles(context.Background(), pd))
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*profilesPerRequest, profilesSampleCount(sink.AllProfiles()))
+	receivedIDs := map[string]bool{}
+	for _, pd := range sink.AllProfiles() {
+		for i := 0; i < pd.ResourceProfiles().Len(); i++ {
+			profiles := pd.ResourceProfiles().At(i).ScopeProfiles().At(0).Profiles()
+			for j := 0; j < profiles.Len(); j++ {
+				receivedIDs[string(profiles.At(j).ProfileID().AsRaw())] = true
+			}
+		}
+	}
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		for profileIndex := 0; profileIndex < profilesPerRequest; profileIndex++ {
+			require.True(t, receivedIDs[string(getTestProfileID(requestNum, profileIndex))])
+		}
+	}
+}
+
+func TestBatchProfileProcessor_BatchSize(t *testing.T) {
+	cfg := Config{
+		Timeout:          3 * time.Second,
+		SendBatchSize:    50,
+		SendBatchMaxSize: 40,
+	}
+
+	requestCount := 20
+	profilesPerRequest := 7
+	sink := new(consumertest.ProfilesSink)
+
+	creationSet := processortest.NewNopSettings()
+	creationSet.MetricsLevel = configtelemetry.LevelDetailed
+	batcher, err := newBatchProfilesProcessor(creationSet, sink, &cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		assert.NoError(t, batcher.ConsumeProfiles(context.Background(), testdata.GenerateProfiles(profilesPerRequest)))
+	}
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*profilesPerRequest, profilesSampleCount(sink.AllProfiles()))
+	for _, pd := range sink.AllProfiles() {
+		assert.LessOrEqual(t, pd.SampleCount(), int(cfg.SendBatchMaxSize))
+	}
+}
+
+func TestBatchProfileProcessor_ProfilesWithoutSamples(t *testing.T) {
+	cfg := Config{
+		Timeout:       10 * time.Millisecond,
+		SendBatchSize: 50,
+	}
+	sink := new(consumertest.ProfilesSink)
+	batcher, err := newBatchProfilesProcessor(processortest.NewNopSettings(), sink, &cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	// A profile only carrying its original payload has no samples, it's sent once the timeout expires.
+	pd := pprofile.NewProfiles()
+	pd.ResourceProfiles().AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty().ProfileID().FromRaw(getTestProfileID(0, 0))
+	assert.NoError(t, batcher.ConsumeProfiles(context.Background(), pd))
+	assert.Eventually(t, func() bool { return len(sink.AllProfiles()) == 1 }, time.Second, 5*time.Millisecond)
+
+	// It's sent along with the profiles with samples of its batch.
+	pd = testdata.GenerateProfiles(3)
+	pd.ResourceProfiles().AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty().ProfileID().FromRaw(getTestProfileID(1, 0))
+	assert.NoError(t, batcher.ConsumeProfiles(context.Background(), pd))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Len(t, sink.AllProfiles(), 2)
+	assert.Equal(t, 0, sink.AllProfiles()[0].SampleCount())
+	assert.Equal(t, getTestProfileID(0, 0), sink.AllProfiles()[0].ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0).ProfileID().AsRaw())
+	last := sink.AllProfiles()[1]
+	assert.Equal(t, 3, last.SampleCount())
+	assert.Equal(t, getTestProfileID(1, 0), last.ResourceProfiles().At(1).ScopeProfiles().At(0).Profiles().At(0).ProfileID().AsRaw())
+}
+
+func getTestProfileID(requestNum, index int) []byte {
+	return []byte(fmt.Sprintf("test-profile-%d-%d", requestNum, index))
+}
+
+func profilesSampleCount(pds []pprofile.Profiles) int {
+	count := 0
+	for _, pd := range pds {
+		count += pd.SampleCount()
+	}
+	return count
+}
+
 func TestShutdown(t *testing.T) {
 	factory := NewFactory()
 	processortest.VerifyShutdown(t, factory, factory.CreateDefaultConfig())
@@ -11,8 +11,10 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/batchprocessor/internal/metadata"
+	"go.opentelemetry.io/collector/processor/processorprofiles"
 )
 
 const (
@@ -32,7 +34,8 @@ func NewFactory() processor.Factory {
 		createDefaultConfig,
 		processor.WithTraces(createTraces, metadata.TracesStability),
 		processor.WithMetrics(createMetrics, metadata.MetricsStability),
-		processor.WithLogs(createLogs, metadata.LogsStability))
+		processor.WithLogs(createLogs, metadata.LogsStability),
+		processorprofiles.WithProfiles(createProfiles, metadata.ProfilesStability))
 }
 
 func createDefaultConfig() component.Config {
@@ -69,3 +72,12 @@ func createLogs(
 ) (processor.Logs, error) {
 	return newBatchLogsProcessor(set, nextConsumer, cfg.(*Config))
 }
+
+func createProfiles(
+	_ context.Context,
+	set processor.Settings,
+	cfg component.Config,
+	nextConsumer consumerprofiles.Profiles,
+) (processorprofiles.Profiles, error) {
+	return newBatchProfilesProcessor(set, nextConsumer, cfg.(*Config))
+}
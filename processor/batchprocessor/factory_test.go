@@ -40,4 +40,9 @@ func TestCreateProcessor(t *testing.T) {
 	assert.NotNil(t, lp)
 	assert.NoError(t, err, "cannot create logs processor")
 	assert.NoError(t, lp.Shutdown(context.Background()))
+
+	pp, err := factory.CreateProfilesProcessor(context.Background(), creationSet, cfg, nil)
+	assert.NotNil(t, pp)
+	assert.NoError(t, err, "cannot create profiles processor")
+	assert.NoError(t, pp.Shutdown(context.Background()))
 }
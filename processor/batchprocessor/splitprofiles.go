@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package batchprocessor // import "go.opentelemetry.io/collector/processor/batchprocessor"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pprofile"
+)
+
+// splitProfiles removes samples from the input data and returns a new data of the specified size.
+func splitProfiles(size int, src pprofile.Profiles) pprofile.Profiles {
+	if src.SampleCount() <= size {
+		return src
+	}
+	totalCopiedSamples := 0
+	dest := pprofile.NewProfiles()
+
+	src.ResourceProfiles().RemoveIf(func(srcRp pprofile.ResourceProfiles) bool {
+		// If we are done skip everything else.
+		if totalCopiedSamples == size {
+			return false
+		}
+
+		// If it fully fits
+		srcRpSC := resourceProfilesSC(srcRp)
+		if (totalCopiedSamples + srcRpSC) <= size {
+			totalCopiedSamples += srcRpSC
+			srcRp.MoveTo(dest.ResourceProfiles().AppendEmpty())
+			return true
+		}
+
+		destRp := dest.ResourceProfiles().AppendEmpty()
+		srcRp.Resource().CopyTo(destRp.Resource())
+		destRp.SetSchemaUrl(srcRp.SchemaUrl())
+		srcRp.ScopeProfiles().RemoveIf(func(srcSp pprofile.ScopeProfiles) bool {
+			// If we are done skip everything else.
+			if totalCopiedSamples == size {
+				return false
+			}
+
+			// If possible to move all profiles do that.
+			srcSpSC := scopeProfilesSC(srcSp)
+			if size >= srcSpSC+totalCopiedSamples {
+				totalCopiedSamples += srcSpSC
+				srcSp.MoveTo(destRp.ScopeProfiles().AppendEmpty())
+				return true
+			}
+
+			destSp := destRp.ScopeProfiles().AppendEmpty()
+			srcSp.Scope().CopyTo(destSp.Scope())
+			destSp.SetSchemaUrl(srcSp.SchemaUrl())
+			srcSp.Profiles().RemoveIf(func(srcPc pprofile.ProfileContainer) bool {
+				// If we are done skip everything else.
+				if totalCopiedSamples == size {
+					return false
+				}
+
+				srcPcSC := srcPc.Profile().Sample().Len()
+				if size >= srcPcSC+totalCopiedSamples {
+					totalCopiedSamples += srcPcSC
+					srcPc.MoveTo(destSp.Profiles().AppendEmpty())
+					return true
+				}
+
+				// The samples refer to the tables of their profile by index, so a split profile
+				// keeps all of them and only its samples are divided.
+				splitSamples(size-totalCopiedSamples, srcPc, destSp.Profiles().AppendEmpty())
+				totalCopiedSamples = size
+				return false
+			})
+			return srcSp.Profiles().Len() == 0
+		})
+		return srcRp.ScopeProfiles().Len() == 0
+	})
+
+	return dest
+}
+
+// splitSamples copies the source profile to the destination, and moves the first size samples
+// from the source to the destination.
+func splitSamples(size int, srcPc pprofile.ProfileContainer, destPc pprofile.ProfileContainer) {
+	// Set the samples aside to not copy them.
+	srcSamples := pprofile.NewSampleSlice()
+	srcPc.Profile().Sample().MoveAndAppendTo(srcSamples)
+	srcPc.CopyTo(destPc)
+	srcSamples.MoveAndAppendTo(srcPc.Profile().Sample())
+
+	i := 0
+	srcPc.Profile().Sample().RemoveIf(func(srcSample pprofile.Sample) bool {
+		if i == size {
+			return false
+		}
+		srcSample.MoveTo(destPc.Profile().Sample().AppendEmpty())
+		i++
+		return true
+	})
+}
+
+// resourceProfilesSC calculates the total number of samples in the pprofile.ResourceProfiles.
+func resourceProfilesSC(rp pprofile.ResourceProfiles) (count int) {
+	for k := 0; k < rp.ScopeProfiles().Len(); k++ {
+		count += scopeProfilesSC(rp.ScopeProfiles().At(k))
+	}
+	return
+}
+
+// scopeProfilesSC calculates the total number of samples in the pprofile.ScopeProfiles.
+func scopeProfilesSC(sp pprofile.ScopeProfiles) (count int) {
+	for k := 0; k < sp.Profiles().Len(); k++ {
+		count += sp.Profiles().At(k).Profile().Sample().Len()
+	}
+	return
+}
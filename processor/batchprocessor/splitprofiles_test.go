@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package batchprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/testdata"
+)
+
+func TestSplitProfiles_noop(t *testing.T) {
+	pd := testdata.GenerateProfiles(20)
+	splitSize := 40
+	split := splitProfiles(splitSize, pd)
+	assert.Equal(t, pd, split)
+
+	i := 0
+	pd.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().RemoveIf(func(pprofile.ProfileContainer) bool {
+		i++
+		return i > 5
+	})
+	assert.EqualValues(t, pd, split)
+}
+
+func TestSplitProfiles(t *testing.T) {
+	pd := testdata.GenerateProfiles(20)
+	profiles := pd.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles()
+	for i := 0; i < profiles.Len(); i++ {
+		profiles.At(i).ProfileID().FromRaw(getTestProfileID(0, i))
+	}
+
+	splitSize := 5
+	split := splitProfiles(splitSize, pd)
+	assert.Equal(t, splitSize, split.SampleCount())
+	assert.Equal(t, 15, pd.SampleCount())
+	assert.EqualValues(t, getTestProfileID(0, 0), split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0).ProfileID().AsRaw())
+	assert.EqualValues(t, getTestProfileID(0, 4), split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(4).ProfileID().AsRaw())
+
+	split = splitProfiles(splitSize, pd)
+	assert.Equal(t, 10, pd.SampleCount())
+	assert.EqualValues(t, getTestProfileID(0, 5), split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0).ProfileID().AsRaw())
+	assert.EqualValues(t, getTestProfileID(0, 9), split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(4).ProfileID().AsRaw())
+
+	split = splitProfiles(splitSize, pd)
+	assert.Equal(t, 5, pd.SampleCount())
+	assert.EqualValues(t, getTestProfileID(0, 10), split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0).ProfileID().AsRaw())
+	assert.EqualValues(t, getTestProfileID(0, 14), split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(4).ProfileID().AsRaw())
+
+	split = splitProfiles(splitSize, pd)
+	assert.Equal(t, 5, pd.SampleCount())
+	assert.EqualValues(t, getTestProfileID(0, 15), split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0).ProfileID().AsRaw())
+	assert.EqualValues(t, getTestProfileID(0, 19), split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(4).ProfileID().AsRaw())
+}
+
+func TestSplitProfilesMultipleResourceProfiles(t *testing.T) {
+	pd := testdata.GenerateProfiles(20)
+	profiles := pd.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles()
+	for i := 0; i < profiles.Len(); i++ {
+		profiles.At(i).ProfileID().FromRaw(getTestProfileID(0, i))
+	}
+	// add second index to resource profiles
+	testdata.GenerateProfiles(20).
+		ResourceProfiles().At(0).CopyTo(pd.ResourceProfiles().AppendEmpty())
+	profiles = pd.ResourceProfiles().At(1).ScopeProfiles().At(0).Profiles()
+	for i := 0; i < profiles.Len(); i++ {
+		profiles.At(i).ProfileID().FromRaw(getTestProfileID(1, i))
+	}
+
+	splitSize := 5
+	split := splitProfiles(splitSize, pd)
+	assert.Equal(t, splitSize, split.SampleCount())
+	assert.Equal(t, 35, pd.SampleCount())
+	assert.EqualValues(t, getTestProfileID(0, 0), split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0).ProfileID().AsRaw())
+	assert.EqualValues(t, getTestProfileID(0, 4), split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(4).ProfileID().AsRaw())
+}
+
+func TestSplitProfilesMultipleResourceProfiles_split_size_greater_than_sample_size(t *testing.T) {
+	pd := testdata.GenerateProfiles(20)
+	// add second index to resource profiles
+	testdata.GenerateProfiles(20).
+		ResourceProfiles().At(0).CopyTo(pd.ResourceProfiles().AppendEmpty())
+
+	splitSize := 25
+	split := splitProfiles(splitSize, pd)
+	assert.Equal(t, splitSize, split.SampleCount())
+	assert.Equal(t, 40-splitSize, pd.SampleCount())
+	assert.Equal(t, 1, pd.ResourceProfiles().Len())
+	assert.Equal(t, 2, split.ResourceProfiles().Len())
+	assert.Equal(t, 20, split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().Len())
+	assert.Equal(t, 5, split.ResourceProfiles().At(1).ScopeProfiles().At(0).Profiles().Len())
+}
+
+func TestSplitProfilesSamples(t *testing.T) {
+	pd := testdata.GenerateProfiles(1)
+	pc := pd.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0)
+	pc.Profile().StringTable().Append("", "cpu", "nanoseconds")
+	for i := 1; i < 10; i++ {
+		pc.Profile().Sample().AppendEmpty().Value().Append(int64(i))
+	}
+
+	split := splitProfiles(4, pd)
+	assert.Equal(t, 4, split.SampleCount())
+	assert.Equal(t, 6, pd.SampleCount())
+
+	// The split profile keeps its tables, and only its samples are divided.
+	splitPc := split.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().At(0)
+	assert.Equal(t, pc.ProfileID().AsRaw(), splitPc.ProfileID().AsRaw())
+	assert.Equal(t, pc.Profile().StringTable().AsRaw(), splitPc.Profile().StringTable().AsRaw())
+	assert.Equal(t, []int64{4}, splitPc.Profile().Sample().At(0).Value().AsRaw())
+	assert.Equal(t, []int64{3}, splitPc.Profile().Sample().At(3).Value().AsRaw())
+	assert.Equal(t, []int64{4}, pc.Profile().Sample().At(0).Value().AsRaw())
+}
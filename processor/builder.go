@@ -85,6 +85,11 @@ func (b *Builder) Factory(componentType component.Type) component.Factory {
 	return b.factories[componentType]
 }
 
+// Config returns the configuration of the component, or nil if it is not configured.
+func (b *Builder) Config(componentID component.ID) component.Config {
+	return b.cfgs[componentID]
+}
+
 // logStabilityLevel logs the stability level of a component. The log level is set to info for
 // undefined, unmaintained, deprecated and development. The log level is set to debug
 // for alpha, beta and stable.
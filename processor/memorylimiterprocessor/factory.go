@@ -11,9 +11,11 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/memorylimiterprocessor/internal/metadata"
 	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.opentelemetry.io/collector/processor/processorprofiles"
 )
 
 var processorCapabilities = consumer.Capabilities{MutatesData: false}
@@ -35,7 +37,8 @@ func NewFactory() processor.Factory {
 		createDefaultConfig,
 		processor.WithTraces(f.createTracesProcessor, metadata.TracesStability),
 		processor.WithMetrics(f.createMetricsProcessor, metadata.MetricsStability),
-		processor.WithLogs(f.createLogsProcessor, metadata.LogsStability))
+		processor.WithLogs(f.createLogsProcessor, metadata.LogsStability),
+		processorprofiles.WithProfiles(f.createProfilesProcessor, metadata.ProfilesStability))
 }
 
 // CreateDefaultConfig creates the default configuration for processor. Notice
@@ -95,6 +98,19 @@ func (f *factory) createLogsProcessor(
 		processorhelper.WithShutdown(memLimiter.shutdown))
 }
 
+func (f *factory) createProfilesProcessor(
+	_ context.Context,
+	set processor.Settings,
+	cfg component.Config,
+	nextConsumer consumerprofiles.Profiles,
+) (processorprofiles.Profiles, error) {
+	memLimiter, err := f.getMemoryLimiter(set, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newProfilesProcessor(memLimiter, nextConsumer)
+}
+
 // getMemoryLimiter checks if we have a cached memoryLimiter with a specific config,
 // otherwise initialize and add one to the store.
 func (f *factory) getMemoryLimiter(set processor.Settings, cfg component.Config) (*memoryLimiterProcessor, error) {
@@ -7,9 +7,12 @@ import (
 	"context"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
 	"go.opentelemetry.io/collector/internal/memorylimiter"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/processorhelper"
@@ -103,3 +106,36 @@ func (p *memoryLimiterProcessor) processLogs(ctx context.Context, ld plog.Logs)
 	p.obsrep.LogsAccepted(ctx, numRecords)
 	return ld, nil
 }
+
+func (p *memoryLimiterProcessor) processProfiles(_ context.Context, pd pprofile.Profiles) (pprofile.Profiles, error) {
+	// The profiles are not recorded by the obsreport yet.
+	if p.memlimiter.MustRefuse() {
+		return pd, memorylimiter.ErrDataRefused
+	}
+	return pd, nil
+}
+
+// profilesProcessor is the profiles processor, built without the processorhelper which doesn't support the profiles yet.
+type profilesProcessor struct {
+	component.StartFunc
+	component.ShutdownFunc
+	consumerprofiles.Profiles
+}
+
+func newProfilesProcessor(memLimiter *memoryLimiterProcessor, nextConsumer consumerprofiles.Profiles) (*profilesProcessor, error) {
+	pc, err := consumerprofiles.NewProfiles(func(ctx context.Context, pd pprofile.Profiles) error {
+		pd, err := memLimiter.processProfiles(ctx, pd)
+		if err != nil {
+			return err
+		}
+		return nextConsumer.ConsumeProfiles(ctx, pd)
+	}, consumer.WithCapabilities(processorCapabilities))
+	if err != nil {
+		return nil, err
+	}
+	return &profilesProcessor{
+		StartFunc:    memLimiter.start,
+		ShutdownFunc: memLimiter.shutdown,
+		Profiles:     pc,
+	}, nil
+}
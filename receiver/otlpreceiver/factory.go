@@ -11,10 +11,12 @@ import (
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
 	"go.opentelemetry.io/collector/internal/localhostgate"
 	"go.opentelemetry.io/collector/internal/sharedcomponent"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/metadata"
+	"go.opentelemetry.io/collector/receiver/receiverprofiles"
 )
 
 const (
@@ -24,6 +26,8 @@ const (
 	defaultTracesURLPath  = "/v1/traces"
 	defaultMetricsURLPath = "/v1/metrics"
 	defaultLogsURLPath    = "/v1/logs"
+	// The profiles signal is still in development, so its path isn't configurable yet.
+	defaultProfilesURLPath = "/v1development/profiles"
 )
 
 // NewFactory creates a new OTLP receiver factory.
@@ -34,6 +38,7 @@ func NewFactory() receiver.Factory {
 		receiver.WithTraces(createTraces, metadata.TracesStability),
 		receiver.WithMetrics(createMetrics, metadata.MetricsStability),
 		receiver.WithLogs(createLog, metadata.LogsStability),
+		receiverprofiles.WithProfiles(createProfiles, metadata.ProfilesStability),
 	)
 }
 
@@ -130,6 +135,29 @@ func createLog(
 	return r, nil
 }
 
+// createProfiles creates a profiles receiver based on provided config.
+func createProfiles(
+	_ context.Context,
+	set receiver.Settings,
+	cfg component.Config,
+	nextConsumer consumerprofiles.Profiles,
+) (receiverprofiles.Profiles, error) {
+	oCfg := cfg.(*Config)
+	r, err := receivers.LoadOrStore(
+		oCfg,
+		func() (*otlpReceiver, error) {
+			return newOtlpReceiver(oCfg, &set)
+		},
+		&set.TelemetrySettings,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Unwrap().registerProfilesConsumer(nextConsumer)
+	return r, nil
+}
+
 // This is the map of already created OTLP receivers for particular configurations.
 // We maintain this map because the Factory is asked trace and metric receivers separately
 // when it gets CreateTracesReceiver() and CreateMetricsReceiver() but they must not
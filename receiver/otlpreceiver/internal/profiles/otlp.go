@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package profiles // import "go.opentelemetry.io/collector/receiver/otlpreceiver/internal/profiles"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
+	"go.opentelemetry.io/collector/pdata/pprofile/pprofileotlp"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/errors"
+)
+
+// Receiver is the type used to handle profiles from OpenTelemetry exporters.
+type Receiver struct {
+	pprofileotlp.UnimplementedGRPCServer
+	nextConsumer consumerprofiles.Profiles
+}
+
+// New creates a new Receiver reference.
+func New(nextConsumer consumerprofiles.Profiles) *Receiver {
+	return &Receiver{
+		nextConsumer: nextConsumer,
+	}
+}
+
+// Export implements the service Export profiles func.
+func (r *Receiver) Export(ctx context.Context, req pprofileotlp.ExportRequest) (pprofileotlp.ExportResponse, error) {
+	pd := req.Profiles()
+	// The receiverhelper.ObsReport doesn't support the profiles yet, so the data is passed through without being counted.
+	// The profiles without samples are still passed on, they may carry their original payload only.
+	if pd.ResourceProfiles().Len() == 0 {
+		return pprofileotlp.NewExportResponse(), nil
+	}
+
+	// Use appropriate status codes for permanent/non-permanent errors, see the logs receiver.
+	if err := r.nextConsumer.ConsumeProfiles(ctx, pd); err != nil {
+		return pprofileotlp.NewExportResponse(), errors.GetStatusFromError(err)
+	}
+
+	return pprofileotlp.NewExportResponse(), nil
+}
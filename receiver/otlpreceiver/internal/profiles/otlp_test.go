@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package profiles
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/pprofile/pprofileotlp"
+	"go.opentelemetry.io/collector/pdata/testdata"
+)
+
+func TestExport(t *testing.T) {
+	pd := testdata.GenerateProfiles(2)
+	req := pprofileotlp.NewExportRequestFromProfiles(pd)
+
+	profileSink := new(consumertest.ProfilesSink)
+	profileClient := makeProfileServiceClient(t, profileSink)
+	resp, err := profileClient.Export(context.Background(), req)
+	require.NoError(t, err, "Failed to export profile: %v", err)
+	require.NotNil(t, resp, "The response is missing")
+
+	pds := profileSink.AllProfiles()
+	require.Len(t, pds, 1)
+	assert.EqualValues(t, pd, pds[0])
+}
+
+func TestExport_EmptyRequest(t *testing.T) {
+	profileSink := new(consumertest.ProfilesSink)
+	profileClient := makeProfileServiceClient(t, profileSink)
+	resp, err := profileClient.Export(context.Background(), pprofileotlp.NewExportRequest())
+	assert.NoError(t, err, "Failed to export profile: %v", err)
+	assert.NotNil(t, resp, "The response is missing")
+	assert.Empty(t, profileSink.AllProfiles())
+}
+
+func TestExport_ProfileWithoutSamples(t *testing.T) {
+	pd := pprofile.NewProfiles()
+	pc := pd.ResourceProfiles().AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty()
+	pc.ProfileID().FromRaw([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	pc.Attributes().PutStr("profile.format", "jfr")
+	req := pprofileotlp.NewExportRequestFromProfiles(pd)
+
+	profileSink := new(consumertest.ProfilesSink)
+	profileClient := makeProfileServiceClient(t, profileSink)
+	resp, err := profileClient.Export(context.Background(), req)
+	require.NoError(t, err, "Failed to export profile: %v", err)
+	require.NotNil(t, resp, "The response is missing")
+
+	pds := profileSink.AllProfiles()
+	require.Len(t, pds, 1)
+	assert.EqualValues(t, pd, pds[0])
+}
+
+func TestExport_NonPermanentErrorConsumer(t *testing.T) {
+	pd := testdata.GenerateProfiles(1)
+	req := pprofileotlp.NewExportRequestFromProfiles(pd)
+
+	profileClient := makeProfileServiceClient(t, consumertest.NewErr(errors.New("my error")))
+	resp, err := profileClient.Export(context.Background(), req)
+	assert.EqualError(t, err, "rpc error: code = Unavailable desc = my error")
+	assert.IsType(t, status.Error(codes.Unknown, ""), err)
+	assert.Equal(t, pprofileotlp.ExportResponse{}, resp)
+}
+
+func TestExport_PermanentErrorConsumer(t *testing.T) {
+	pd := testdata.GenerateProfiles(1)
+	req := pprofileotlp.NewExportRequestFromProfiles(pd)
+
+	profileClient := makeProfileServiceClient(t, consumertest.NewErr(consumererror.NewPermanent(errors.New("my error"))))
+	resp, err := profileClient.Export(context.Background(), req)
+	assert.EqualError(t, err, "rpc error: code = Internal desc = Permanent error: my error")
+	assert.IsType(t, status.Error(codes.Unknown, ""), err)
+	assert.Equal(t, pprofileotlp.ExportResponse{}, resp)
+}
+
+func makeProfileServiceClient(t *testing.T, pc consumerprofiles.Profiles) pprofileotlp.GRPCClient {
+	addr := otlpReceiverOnGRPCServer(t, pc)
+	cc, err := grpc.NewClient(addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err, "Failed to create the ProfileServiceClient: %v", err)
+	t.Cleanup(func() {
+		require.NoError(t, cc.Close())
+	})
+
+	return pprofileotlp.NewGRPCClient(cc)
+}
+
+func otlpReceiverOnGRPCServer(t *testing.T, pc consumerprofiles.Profiles) net.Addr {
+	ln, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
+
+	t.Cleanup(func() {
+		require.NoError(t, ln.Close())
+	})
+
+	r := New(pc)
+	// Now run it as a gRPC server
+	srv := grpc.NewServer()
+	pprofileotlp.RegisterGRPCServer(srv, r)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+
+	return ln.Addr()
+}
@@ -10,6 +10,8 @@
 // [Graph.StartAll] starts all components in each pipeline.
 //
 // [Graph.ShutdownAll] stops all components in each pipeline.
+//
+// [Graph.Reload] updates the pipelines to a new config, restarting only the components which changed.
 package graph // import "go.opentelemetry.io/collector/service/internal/graph"
 
 import (
@@ -17,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -53,6 +56,10 @@ type Settings struct {
 }
 
 type Graph struct {
+	// mu guards the fields swapped by Reload against the readers running concurrently with it: GetExporters,
+	// called by the components, and the zPages handlers. Reload, StartAll and ShutdownAll are called one at a time.
+	mu sync.RWMutex
+
 	// All component instances represented as nodes, with directed edges indicating data flow.
 	componentGraph *simple.DirectedGraph
 
@@ -63,16 +70,29 @@ type Graph struct {
 	instanceIDs map[int64]*component.InstanceID
 
 	telemetry component.TelemetrySettings
+
+	// The settings the graph was built with, to compare the configuration of the components with on reload.
+	settings Settings
 }
 
 // Build builds a full pipeline graph.
 // Build also validates the configuration of the pipelines and does the actual initialization of each Component in the Graph.
 func Build(ctx context.Context, set Settings) (*Graph, error) {
+	pipelines, err := newGraph(set)
+	if err != nil {
+		return nil, err
+	}
+	return pipelines, pipelines.buildComponents(ctx, set)
+}
+
+// newGraph creates the nodes and edges of the graph of the pipelines, without building the components.
+func newGraph(set Settings) (*Graph, error) {
 	pipelines := &Graph{
 		componentGraph: simple.NewDirectedGraph(),
 		pipelines:      make(map[component.ID]*pipelineNodes, len(set.PipelineConfigs)),
 		instanceIDs:    make(map[int64]*component.InstanceID),
 		telemetry:      set.Telemetry,
+		settings:       set,
 	}
 	for pipelineID := range set.PipelineConfigs {
 		pipelines.pipelines[pipelineID] = &pipelineNodes{
@@ -84,7 +104,7 @@ func Build(ctx context.Context, set Settings) (*Graph, error) {
 		return nil, err
 	}
 	pipelines.createEdges()
-	return pipelines, pipelines.buildComponents(ctx, set)
+	return pipelines, nil
 }
 
 // Creates a node for each instance of a component and adds it to the graph.
@@ -297,78 +317,82 @@ func (g *Graph) buildComponents(ctx context.Context, set Settings) error {
 	}
 
 	for i := len(nodes) - 1; i >= 0; i-- {
-		node := nodes[i]
-
-		// skipped for capabilitiesNodes and fanoutNodes as they are not assigned componentIDs.
-		var telemetrySettings component.TelemetrySettings
-		if instanceID, ok := g.instanceIDs[node.ID()]; ok {
-			telemetrySettings = set.Telemetry
-			telemetrySettings.ReportStatus = status.NewReportStatusFunc(instanceID, set.ReportStatus)
+		if err = g.buildNode(ctx, set, nodes[i]); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		switch n := node.(type) {
-		case *receiverNode:
-			err = n.buildComponent(ctx, telemetrySettings, set.BuildInfo, set.ReceiverBuilder, g.nextConsumers(n.ID()))
-		case *processorNode:
-			// nextConsumers is guaranteed to be length 1.  Either it is the next processor or it is the fanout node for the exporters.
-			err = n.buildComponent(ctx, telemetrySettings, set.BuildInfo, set.ProcessorBuilder, g.nextConsumers(n.ID())[0])
-		case *exporterNode:
-			err = n.buildComponent(ctx, telemetrySettings, set.BuildInfo, set.ExporterBuilder)
-		case *connectorNode:
-			err = n.buildComponent(ctx, telemetrySettings, set.BuildInfo, set.ConnectorBuilder, g.nextConsumers(n.ID()))
-		case *capabilitiesNode:
-			capability := consumer.Capabilities{
-				// The fanOutNode represents the aggregate capabilities of the exporters in the pipeline.
-				MutatesData: g.pipelines[n.pipelineID].fanOutNode.getConsumer().Capabilities().MutatesData,
-			}
-			for _, proc := range g.pipelines[n.pipelineID].processors {
-				capability.MutatesData = capability.MutatesData || proc.getConsumer().Capabilities().MutatesData
+// buildNode instantiates the component of the node, hooked up to the already built components of the next nodes.
+func (g *Graph) buildNode(ctx context.Context, set Settings, node graph.Node) error {
+	// skipped for capabilitiesNodes and fanoutNodes as they are not assigned componentIDs.
+	var telemetrySettings component.TelemetrySettings
+	if instanceID, ok := g.instanceIDs[node.ID()]; ok {
+		telemetrySettings = set.Telemetry
+		telemetrySettings.ReportStatus = status.NewReportStatusFunc(instanceID, set.ReportStatus)
+	}
+
+	switch n := node.(type) {
+	case *receiverNode:
+		return n.buildComponent(ctx, telemetrySettings, set.BuildInfo, set.ReceiverBuilder, g.nextConsumers(n.ID()))
+	case *processorNode:
+		// nextConsumers is guaranteed to be length 1.  Either it is the next processor or it is the fanout node for the exporters.
+		return n.buildComponent(ctx, telemetrySettings, set.BuildInfo, set.ProcessorBuilder, g.nextConsumers(n.ID())[0])
+	case *exporterNode:
+		return n.buildComponent(ctx, telemetrySettings, set.BuildInfo, set.ExporterBuilder)
+	case *connectorNode:
+		return n.buildComponent(ctx, telemetrySettings, set.BuildInfo, set.ConnectorBuilder, g.nextConsumers(n.ID()))
+	case *capabilitiesNode:
+		n.setConsumer(g.capabilitiesConsumer(n))
+	case *fanOutNode:
+		nexts := g.nextConsumers(n.ID())
+		switch n.pipelineID.Type() {
+		case component.DataTypeTraces:
+			consumers := make([]consumer.Traces, 0, len(nexts))
+			for _, next := range nexts {
+				consumers = append(consumers, next.(consumer.Traces))
 			}
-			next := g.nextConsumers(n.ID())[0]
-			switch n.pipelineID.Type() {
-			case component.DataTypeTraces:
-				cc := capabilityconsumer.NewTraces(next.(consumer.Traces), capability)
-				n.baseConsumer = cc
-				n.ConsumeTracesFunc = cc.ConsumeTraces
-			case component.DataTypeMetrics:
-				cc := capabilityconsumer.NewMetrics(next.(consumer.Metrics), capability)
-				n.baseConsumer = cc
-				n.ConsumeMetricsFunc = cc.ConsumeMetrics
-			case component.DataTypeLogs:
-				cc := capabilityconsumer.NewLogs(next.(consumer.Logs), capability)
-				n.baseConsumer = cc
-				n.ConsumeLogsFunc = cc.ConsumeLogs
+			n.baseConsumer = fanoutconsumer.NewTraces(consumers)
+		case component.DataTypeMetrics:
+			consumers := make([]consumer.Metrics, 0, len(nexts))
+			for _, next := range nexts {
+				consumers = append(consumers, next.(consumer.Metrics))
 			}
-		case *fanOutNode:
-			nexts := g.nextConsumers(n.ID())
-			switch n.pipelineID.Type() {
-			case component.DataTypeTraces:
-				consumers := make([]consumer.Traces, 0, len(nexts))
-				for _, next := range nexts {
-					consumers = append(consumers, next.(consumer.Traces))
-				}
-				n.baseConsumer = fanoutconsumer.NewTraces(consumers)
-			case component.DataTypeMetrics:
-				consumers := make([]consumer.Metrics, 0, len(nexts))
-				for _, next := range nexts {
-					consumers = append(consumers, next.(consumer.Metrics))
-				}
-				n.baseConsumer = fanoutconsumer.NewMetrics(consumers)
-			case component.DataTypeLogs:
-				consumers := make([]consumer.Logs, 0, len(nexts))
-				for _, next := range nexts {
-					consumers = append(consumers, next.(consumer.Logs))
-				}
-				n.baseConsumer = fanoutconsumer.NewLogs(consumers)
+			n.baseConsumer = fanoutconsumer.NewMetrics(consumers)
+		case component.DataTypeLogs:
+			consumers := make([]consumer.Logs, 0, len(nexts))
+			for _, next := range nexts {
+				consumers = append(consumers, next.(consumer.Logs))
 			}
-		}
-		if err != nil {
-			return err
+			n.baseConsumer = fanoutconsumer.NewLogs(consumers)
 		}
 	}
 	return nil
 }
 
+// capabilitiesConsumer returns the consumer of the rest of the pipeline of the capabilities node, presenting the
+// aggregated capabilities of the pipeline.
+func (g *Graph) capabilitiesConsumer(n *capabilitiesNode) baseConsumer {
+	capability := consumer.Capabilities{
+		// The fanOutNode represents the aggregate capabilities of the exporters in the pipeline.
+		MutatesData: g.pipelines[n.pipelineID].fanOutNode.getConsumer().Capabilities().MutatesData,
+	}
+	for _, proc := range g.pipelines[n.pipelineID].processors {
+		capability.MutatesData = capability.MutatesData || proc.getConsumer().Capabilities().MutatesData
+	}
+	next := g.nextConsumers(n.ID())[0]
+	switch n.pipelineID.Type() {
+	case component.DataTypeTraces:
+		return capabilityconsumer.NewTraces(next.(consumer.Traces), capability)
+	case component.DataTypeMetrics:
+		return capabilityconsumer.NewMetrics(next.(consumer.Metrics), capability)
+	case component.DataTypeLogs:
+		return capabilityconsumer.NewLogs(next.(consumer.Logs), capability)
+	}
+	return next
+}
+
 // Find all nodes
 func (g *Graph) nextConsumers(nodeID int64) []baseConsumer {
 	nextNodes := g.componentGraph.From(nodeID)
@@ -408,37 +432,43 @@ func (g *Graph) StartAll(ctx context.Context, host component.Host, reporter stat
 	// are started before upstream components. This ensures that each
 	// component's consumer is ready to consume.
 	for i := len(nodes) - 1; i >= 0; i-- {
-		node := nodes[i]
-		comp, ok := node.(component.Component)
-
-		if !ok {
-			// Skip capabilities/fanout nodes
-			continue
+		if err = g.startNode(ctx, host, reporter, nodes[i]); err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+// startNode starts the component of the node, reporting its status.
+func (g *Graph) startNode(ctx context.Context, host component.Host, reporter status.Reporter, node graph.Node) error {
+	comp, ok := node.(component.Component)
+	if !ok {
+		// Skip capabilities/fanout nodes
+		return nil
+	}
 
-		instanceID := g.instanceIDs[node.ID()]
+	instanceID := g.instanceIDs[node.ID()]
+	reporter.ReportStatus(
+		instanceID,
+		component.NewStatusEvent(component.StatusStarting),
+	)
+
+	if compErr := comp.Start(ctx, host); compErr != nil {
 		reporter.ReportStatus(
 			instanceID,
-			component.NewStatusEvent(component.StatusStarting),
+			component.NewPermanentErrorEvent(compErr),
 		)
-
-		if compErr := comp.Start(ctx, host); compErr != nil {
-			reporter.ReportStatus(
-				instanceID,
-				component.NewPermanentErrorEvent(compErr),
+		// We log with zap.AddStacktrace(zap.DPanicLevel) to avoid adding the stack trace to the error log
+		g.telemetry.Logger.WithOptions(zap.AddStacktrace(zap.DPanicLevel)).
+			Error("Failed to start component",
+				zap.Error(compErr),
+				zap.String("type", instanceID.Kind.String()),
+				zap.String("id", instanceID.ID.String()),
 			)
-			// We log with zap.AddStacktrace(zap.DPanicLevel) to avoid adding the stack trace to the error log
-			g.telemetry.Logger.WithOptions(zap.AddStacktrace(zap.DPanicLevel)).
-				Error("Failed to start component",
-					zap.Error(compErr),
-					zap.String("type", instanceID.Kind.String()),
-					zap.String("id", instanceID.ID.String()),
-				)
-			return compErr
-		}
-
-		reporter.ReportOKIfStarting(instanceID)
+		return compErr
 	}
+
+	reporter.ReportOKIfStarting(instanceID)
 	return nil
 }
 
@@ -454,35 +484,38 @@ func (g *Graph) ShutdownAll(ctx context.Context, reporter status.Reporter) error
 	// before the consumer is stopped.
 	var errs error
 	for i := 0; i < len(nodes); i++ {
-		node := nodes[i]
-		comp, ok := node.(component.Component)
-
-		if !ok {
-			// Skip capabilities/fanout nodes
-			continue
-		}
+		errs = multierr.Append(errs, g.shutdownNode(ctx, reporter, nodes[i]))
+	}
+	return errs
+}
 
-		instanceID := g.instanceIDs[node.ID()]
-		reporter.ReportStatus(
-			instanceID,
-			component.NewStatusEvent(component.StatusStopping),
-		)
+// shutdownNode shuts down the component of the node, reporting its status.
+func (g *Graph) shutdownNode(ctx context.Context, reporter status.Reporter, node graph.Node) error {
+	comp, ok := node.(component.Component)
+	if !ok {
+		// Skip capabilities/fanout nodes
+		return nil
+	}
 
-		if compErr := comp.Shutdown(ctx); compErr != nil {
-			errs = multierr.Append(errs, compErr)
-			reporter.ReportStatus(
-				instanceID,
-				component.NewPermanentErrorEvent(compErr),
-			)
-			continue
-		}
+	instanceID := g.instanceIDs[node.ID()]
+	reporter.ReportStatus(
+		instanceID,
+		component.NewStatusEvent(component.StatusStopping),
+	)
 
+	if compErr := comp.Shutdown(ctx); compErr != nil {
 		reporter.ReportStatus(
 			instanceID,
-			component.NewStatusEvent(component.StatusStopped),
+			component.NewPermanentErrorEvent(compErr),
 		)
+		return compErr
 	}
-	return errs
+
+	reporter.ReportStatus(
+		instanceID,
+		component.NewStatusEvent(component.StatusStopped),
+	)
+	return nil
 }
 
 // Deprecated: [0.79.0] This function will be removed in the future.
@@ -492,6 +525,8 @@ func (g *Graph) ShutdownAll(ctx context.Context, reporter status.Reporter) error
 // https://github.com/open-telemetry/opentelemetry-collector/pull/7390#issuecomment-1483710184
 // for additional information.
 func (g *Graph) GetExporters() map[component.DataType]map[component.ID]component.Component {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 	exportersMap := make(map[component.DataType]map[component.ID]component.Component)
 	exportersMap[component.DataTypeTraces] = make(map[component.ID]component.Component)
 	exportersMap[component.DataTypeMetrics] = make(map[component.ID]component.Component)
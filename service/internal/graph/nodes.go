@@ -8,12 +8,16 @@ import (
 	"fmt"
 	"hash/fnv"
 	"strings"
+	"sync/atomic"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/internal/fanoutconsumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/service/internal/capabilityconsumer"
@@ -350,23 +354,29 @@ func (n *connectorNode) buildComponent(
 var _ consumerNode = (*capabilitiesNode)(nil)
 
 // Every pipeline has a "virtual" capabilities node immediately after the receiver(s).
-// There are two purposes for this node:
+// There are three purposes for this node:
 // 1. Present aggregated capabilities to receivers, such as whether the pipeline mutates data.
 // 2. Present a consistent "first consumer" for each pipeline.
+// 3. Allow the rest of the pipeline to be replaced on reload, without rebuilding the receivers.
 // The nodeID is derived from "pipeline ID".
 type capabilitiesNode struct {
 	nodeID
 	pipelineID component.ID
+	// next holds the consumer of the rest of the pipeline. It is shared with the capabilities node
+	// of the same pipeline in the graph of a reload, which swaps the consumer in place.
+	next *atomic.Pointer[nextConsumer]
+}
+
+// nextConsumer wraps the consumer of the rest of the pipeline, for it to be stored atomically.
+type nextConsumer struct {
 	baseConsumer
-	consumer.ConsumeTracesFunc
-	consumer.ConsumeMetricsFunc
-	consumer.ConsumeLogsFunc
 }
 
 func newCapabilitiesNode(pipelineID component.ID) *capabilitiesNode {
 	return &capabilitiesNode{
 		nodeID:     newNodeID(capabilitiesSeed, pipelineID.String()),
 		pipelineID: pipelineID,
+		next:       &atomic.Pointer[nextConsumer]{},
 	}
 }
 
@@ -374,6 +384,26 @@ func (n *capabilitiesNode) getConsumer() baseConsumer {
 	return n
 }
 
+func (n *capabilitiesNode) setConsumer(next baseConsumer) {
+	n.next.Store(&nextConsumer{baseConsumer: next})
+}
+
+func (n *capabilitiesNode) Capabilities() consumer.Capabilities {
+	return n.next.Load().Capabilities()
+}
+
+func (n *capabilitiesNode) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	return n.next.Load().baseConsumer.(consumer.Traces).ConsumeTraces(ctx, td)
+}
+
+func (n *capabilitiesNode) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	return n.next.Load().baseConsumer.(consumer.Metrics).ConsumeMetrics(ctx, md)
+}
+
+func (n *capabilitiesNode) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	return n.next.Load().baseConsumer.(consumer.Logs).ConsumeLogs(ctx, ld)
+}
+
 var _ consumerNode = (*fanOutNode)(nil)
 
 // Each pipeline has one fan-out node before exporters.
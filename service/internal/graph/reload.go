@@ -0,0 +1,288 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package graph // import "go.opentelemetry.io/collector/service/internal/graph"
+
+import (
+	"context"
+	"reflect"
+
+	"go.uber.org/multierr"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/topo"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/service/internal/status"
+)
+
+// Reload updates the running graph to the pipelines of the settings. The components whose configuration and edges
+// are unchanged, and whose next components are kept, keep running. The other components are built and started,
+// and the components they replace, or no longer used, are shut down.
+//
+// The first consumer of each pipeline is shared with the new graph, so the rest of a pipeline is swapped in place
+// once started, without rebuilding its receivers. The receivers being replaced are shut down first, so that the new
+// ones can use the same endpoints.
+//
+// A receiver may be shared by its pipelines of different data types, as the OTLP receiver is, so all the nodes
+// of a receiver are kept or rebuilt together: shutting down one of them would stop the others.
+//
+// If the new graph cannot be built, the running graph is left untouched. If a component fails to start, the reload
+// is rolled back: the components it built are shut down, the receivers it shut down are rebuilt from the settings
+// of the running graph and started again, and the other components of the running graph keep running.
+func (g *Graph) Reload(ctx context.Context, set Settings, host component.Host, reporter status.Reporter) error {
+	newG, err := newGraph(set)
+	if err != nil {
+		return err
+	}
+	nodes, err := topo.Sort(newG.componentGraph)
+	if err != nil {
+		return cycleErr(err, topo.DirectedCyclesIn(newG.componentGraph))
+	}
+	currentNodes, err := topo.Sort(g.componentGraph)
+	if err != nil {
+		return err
+	}
+
+	// The nodes built by the reload, as opposed to kept from the running graph.
+	built := make(map[int64]bool)
+	// The consumers to swap in the capabilities nodes shared with the running graph, once their pipeline started.
+	swaps := make(map[int64]baseConsumer)
+	for i := len(nodes) - 1; i >= 0; i-- {
+		node := nodes[i]
+		if n, ok := node.(*capabilitiesNode); ok {
+			next := newG.capabilitiesConsumer(n)
+			current, exists := g.componentGraph.Node(n.ID()).(*capabilitiesNode)
+			if !exists || current.Capabilities() != next.Capabilities() {
+				// The receivers are given the capabilities of the pipeline when built, so they are rebuilt.
+				n.setConsumer(next)
+				built[n.ID()] = true
+				continue
+			}
+			n.next = current.next
+			if anyBuilt(newG.componentGraph.From(n.ID()), built) {
+				swaps[n.ID()] = next
+			}
+			continue
+		}
+		if _, ok := node.(*receiverNode); ok {
+			// The receivers have no previous nodes, they are kept or built once the rest of the graph is.
+			continue
+		}
+
+		if g.canKeep(newG, node, built) {
+			keepComponent(node, g.componentGraph.Node(node.ID()))
+			if instanceID, ok := g.instanceIDs[node.ID()]; ok {
+				newG.instanceIDs[node.ID()] = instanceID
+			}
+			continue
+		}
+		if err = newG.buildNode(ctx, set, node); err != nil {
+			return err
+		}
+		built[node.ID()] = true
+	}
+	currentRcvrNodes := receiverNodesByID(currentNodes)
+	for rcvrID, rcvrNodes := range receiverNodesByID(nodes) {
+		if g.canKeepReceiver(newG, rcvrNodes, currentRcvrNodes[rcvrID], built) {
+			for _, node := range rcvrNodes {
+				keepComponent(node, g.componentGraph.Node(node.ID()))
+				newG.instanceIDs[node.ID()] = g.instanceIDs[node.ID()]
+			}
+			continue
+		}
+		for _, node := range rcvrNodes {
+			if err = newG.buildNode(ctx, set, node); err != nil {
+				return err
+			}
+			built[node.ID()] = true
+		}
+	}
+
+	// replaced reports whether the component of the node of the running graph is not kept in the new graph.
+	replaced := func(node graph.Node) bool {
+		_, isComponent := node.(component.Component)
+		return isComponent && (newG.componentGraph.Node(node.ID()) == nil || built[node.ID()])
+	}
+
+	var errs error
+	var stoppedRcvrs []graph.Node
+	for _, node := range currentNodes {
+		if _, ok := node.(*receiverNode); ok && replaced(node) {
+			errs = multierr.Append(errs, g.shutdownNode(ctx, reporter, node))
+			stoppedRcvrs = append(stoppedRcvrs, node)
+		}
+	}
+
+	// Start in reverse topological order, as in StartAll, so that the rest of a pipeline
+	// is started when swapped in its capabilities node.
+	swapped := make(map[*capabilitiesNode]*nextConsumer)
+	for i := len(nodes) - 1; i >= 0; i-- {
+		node := nodes[i]
+		if next, ok := swaps[node.ID()]; ok {
+			n := node.(*capabilitiesNode)
+			swapped[n] = n.next.Load()
+			n.setConsumer(next)
+			continue
+		}
+		if !built[node.ID()] {
+			continue
+		}
+		if err = newG.startNode(ctx, host, reporter, node); err != nil {
+			return multierr.Combine(errs, err, g.rollback(ctx, host, reporter, newG, nodes, i, built, swapped, stoppedRcvrs))
+		}
+	}
+
+	// Stop in topological order, as in ShutdownAll, so that the replaced components drain to their consumers.
+	for _, node := range currentNodes {
+		if _, ok := node.(*receiverNode); !ok && replaced(node) {
+			errs = multierr.Append(errs, g.shutdownNode(ctx, reporter, node))
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.componentGraph = newG.componentGraph
+	g.pipelines = newG.pipelines
+	g.instanceIDs = newG.instanceIDs
+	g.telemetry = newG.telemetry
+	g.settings = newG.settings
+	return errs
+}
+
+// rollback restores the running graph after the node at index failed of the nodes of the new graph to start.
+// The consumers swapped in the capabilities nodes are restored, the components built by the reload are shut down,
+// and the receivers shut down to free their endpoints are rebuilt and started again.
+func (g *Graph) rollback(ctx context.Context, host component.Host, reporter status.Reporter, newG *Graph, nodes []graph.Node, failed int,
+	built map[int64]bool, swapped map[*capabilitiesNode]*nextConsumer, stoppedRcvrs []graph.Node) error {
+	for n, next := range swapped {
+		n.next.Store(next)
+	}
+
+	var errs error
+	// Stop in topological order, as in ShutdownAll. The components not started, including the one which failed,
+	// have no status to report.
+	for i, node := range nodes {
+		if !built[node.ID()] {
+			continue
+		}
+		if i > failed {
+			errs = multierr.Append(errs, newG.shutdownNode(ctx, reporter, node))
+			continue
+		}
+		if comp, ok := node.(component.Component); ok {
+			errs = multierr.Append(errs, comp.Shutdown(ctx))
+		}
+	}
+
+	// The receivers are built from the settings of the running graph. Their stopped instances can't be
+	// started again, so they are reported as new instances.
+	for _, node := range stoppedRcvrs {
+		instanceID := *g.instanceIDs[node.ID()]
+		g.instanceIDs[node.ID()] = &instanceID
+	}
+	for _, node := range stoppedRcvrs {
+		if err := g.buildNode(ctx, g.settings, node); err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		errs = multierr.Append(errs, g.startNode(ctx, host, reporter, node))
+	}
+	return errs
+}
+
+// canKeep reports whether the component of the node of the running graph can be kept in the new graph: it is
+// started, its configuration and its edges are unchanged, and none of its next nodes is built by the reload.
+func (g *Graph) canKeep(newG *Graph, node graph.Node, built map[int64]bool) bool {
+	if g.componentGraph.Node(node.ID()) == nil || !sameConfig(node, g.settings, newG.settings) {
+		return false
+	}
+	if !sameNodes(g.componentGraph.To(node.ID()), newG.componentGraph.To(node.ID())) ||
+		!sameNodes(g.componentGraph.From(node.ID()), newG.componentGraph.From(node.ID())) {
+		return false
+	}
+	return !anyBuilt(newG.componentGraph.From(node.ID()), built)
+}
+
+// canKeepReceiver reports whether the receiver of the nodes can be kept in the new graph: it's used by the pipelines
+// of the same data types as in the running graph, and all its nodes can be kept.
+func (g *Graph) canKeepReceiver(newG *Graph, rcvrNodes, currentRcvrNodes []graph.Node, built map[int64]bool) bool {
+	if len(rcvrNodes) != len(currentRcvrNodes) {
+		return false
+	}
+	for _, node := range rcvrNodes {
+		if !g.canKeep(newG, node, built) {
+			return false
+		}
+	}
+	return true
+}
+
+// receiverNodesByID groups the receiver nodes by the ID of their receiver.
+func receiverNodesByID(nodes []graph.Node) map[component.ID][]graph.Node {
+	byID := make(map[component.ID][]graph.Node)
+	for _, node := range nodes {
+		if n, ok := node.(*receiverNode); ok {
+			byID[n.componentID] = append(byID[n.componentID], n)
+		}
+	}
+	return byID
+}
+
+// keepComponent sets the component of the node of the running graph to the node of the new graph.
+func keepComponent(node, current graph.Node) {
+	switch n := node.(type) {
+	case *receiverNode:
+		n.Component = current.(*receiverNode).Component
+	case *processorNode:
+		n.Component = current.(*processorNode).Component
+	case *exporterNode:
+		n.Component = current.(*exporterNode).Component
+	case *connectorNode:
+		n.Component = current.(*connectorNode).Component
+		n.baseConsumer = current.(*connectorNode).baseConsumer
+	case *fanOutNode:
+		n.baseConsumer = current.(*fanOutNode).baseConsumer
+	}
+}
+
+// sameConfig reports whether the component of the node has the same configuration in both settings.
+func sameConfig(node graph.Node, currentSet, newSet Settings) bool {
+	switch n := node.(type) {
+	case *receiverNode:
+		return reflect.DeepEqual(currentSet.ReceiverBuilder.Config(n.componentID), newSet.ReceiverBuilder.Config(n.componentID))
+	case *processorNode:
+		return reflect.DeepEqual(currentSet.ProcessorBuilder.Config(n.componentID), newSet.ProcessorBuilder.Config(n.componentID))
+	case *exporterNode:
+		return reflect.DeepEqual(currentSet.ExporterBuilder.Config(n.componentID), newSet.ExporterBuilder.Config(n.componentID))
+	case *connectorNode:
+		return reflect.DeepEqual(currentSet.ConnectorBuilder.Config(n.componentID), newSet.ConnectorBuilder.Config(n.componentID))
+	}
+	return true
+}
+
+// sameNodes reports whether both iterators have the same nodes.
+func sameNodes(a, b graph.Nodes) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	ids := make(map[int64]struct{}, a.Len())
+	for a.Next() {
+		ids[a.Node().ID()] = struct{}{}
+	}
+	for b.Next() {
+		if _, ok := ids[b.Node().ID()]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// anyBuilt reports whether any of the nodes is built by the reload.
+func anyBuilt(nodes graph.Nodes, built map[int64]bool) bool {
+	for nodes.Next() {
+		if built[nodes.Node().ID()] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,368 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/testdata"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/service/internal/status/statustest"
+	"go.opentelemetry.io/collector/service/internal/testcomponents"
+	"go.opentelemetry.io/collector/service/pipelines"
+)
+
+// reloadConfig is a configuration of the test components, to tell whether it changed on reload.
+type reloadConfig struct {
+	Endpoint string
+}
+
+type reloadSettings struct {
+	receiverEndpoint string
+	exporterEndpoint string
+	processors       []component.ID
+	secondPipeline   bool
+	metricsPipeline  bool
+}
+
+func newReloadSettings(rs reloadSettings) Settings {
+	tracesID := component.MustNewID("traces")
+	pipelineConfigs := pipelines.Config{
+		tracesID: {
+			Receivers:  []component.ID{component.MustNewID("examplereceiver")},
+			Processors: rs.processors,
+			Exporters:  []component.ID{component.MustNewID("exampleexporter")},
+		},
+	}
+	if rs.secondPipeline {
+		pipelineConfigs[component.MustNewIDWithName("traces", "2")] = &pipelines.PipelineConfig{
+			Receivers: []component.ID{component.MustNewID("examplereceiver")},
+			Exporters: []component.ID{component.MustNewIDWithName("exampleexporter", "2")},
+		}
+	}
+	if rs.metricsPipeline {
+		pipelineConfigs[component.MustNewID("metrics")] = &pipelines.PipelineConfig{
+			Receivers: []component.ID{component.MustNewID("examplereceiver")},
+			Exporters: []component.ID{component.MustNewID("exampleexporter")},
+		}
+	}
+	return Settings{
+		Telemetry: componenttest.NewNopTelemetrySettings(),
+		BuildInfo: component.NewDefaultBuildInfo(),
+		ReceiverBuilder: receiver.NewBuilder(
+			map[component.ID]component.Config{
+				component.MustNewID("examplereceiver"): &reloadConfig{Endpoint: rs.receiverEndpoint},
+			},
+			map[component.Type]receiver.Factory{
+				testcomponents.ExampleReceiverFactory.Type(): testcomponents.ExampleReceiverFactory,
+			},
+		),
+		ProcessorBuilder: processor.NewBuilder(
+			map[component.ID]component.Config{
+				component.MustNewID("exampleprocessor"):                   testcomponents.ExampleProcessorFactory.CreateDefaultConfig(),
+				component.MustNewIDWithName("exampleprocessor", "mutate"): testcomponents.ExampleProcessorFactory.CreateDefaultConfig(),
+			},
+			map[component.Type]processor.Factory{
+				testcomponents.ExampleProcessorFactory.Type(): testcomponents.ExampleProcessorFactory,
+			},
+		),
+		ExporterBuilder: exporter.NewBuilder(
+			map[component.ID]component.Config{
+				component.MustNewID("exampleexporter"):              &reloadConfig{Endpoint: rs.exporterEndpoint},
+				component.MustNewIDWithName("exampleexporter", "2"): &reloadConfig{},
+			},
+			map[component.Type]exporter.Factory{
+				testcomponents.ExampleExporterFactory.Type(): testcomponents.ExampleExporterFactory,
+			},
+		),
+		ConnectorBuilder: connector.NewBuilder(map[component.ID]component.Config{}, map[component.Type]connector.Factory{}),
+		PipelineConfigs:  pipelineConfigs,
+	}
+}
+
+// tracesPipeline returns the receiver, the processors and the exporter of the traces pipeline.
+func tracesPipeline(t *testing.T, g *Graph) (*testcomponents.ExampleReceiver, []*testcomponents.ExampleProcessor, *testcomponents.ExampleExporter) {
+	pipeline := g.pipelines[component.MustNewID("traces")]
+	require.Len(t, pipeline.receivers, 1)
+	require.Len(t, pipeline.exporters, 1)
+	var rcvr *testcomponents.ExampleReceiver
+	for _, n := range pipeline.receivers {
+		rcvr = n.(*receiverNode).Component.(*testcomponents.ExampleReceiver)
+	}
+	var procs []*testcomponents.ExampleProcessor
+	for _, n := range pipeline.processors {
+		procs = append(procs, n.Component.(*testcomponents.ExampleProcessor))
+	}
+	var exp *testcomponents.ExampleExporter
+	for _, n := range pipeline.exporters {
+		exp = n.(*exporterNode).Component.(*testcomponents.ExampleExporter)
+	}
+	return rcvr, procs, exp
+}
+
+func TestGraphReload(t *testing.T) {
+	processorID := component.MustNewID("exampleprocessor")
+	mutateProcessorID := component.MustNewIDWithName("exampleprocessor", "mutate")
+
+	tests := []struct {
+		name           string
+		reloaded       reloadSettings
+		keepsReceiver  bool
+		keepsProcessor bool
+		keepsExporter  bool
+	}{
+		{
+			name:           "unchanged",
+			reloaded:       reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a", processors: []component.ID{processorID}},
+			keepsReceiver:  true,
+			keepsProcessor: true,
+			keepsExporter:  true,
+		},
+		{
+			name:           "receiver changed",
+			reloaded:       reloadSettings{receiverEndpoint: "b", exporterEndpoint: "a", processors: []component.ID{processorID}},
+			keepsReceiver:  false,
+			keepsProcessor: true,
+			keepsExporter:  true,
+		},
+		{
+			name:           "exporter changed",
+			reloaded:       reloadSettings{receiverEndpoint: "a", exporterEndpoint: "b", processors: []component.ID{processorID}},
+			keepsReceiver:  true,
+			keepsProcessor: false,
+			keepsExporter:  false,
+		},
+		{
+			name:           "pipeline added",
+			reloaded:       reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a", processors: []component.ID{processorID}, secondPipeline: true},
+			keepsReceiver:  false,
+			keepsProcessor: true,
+			keepsExporter:  true,
+		},
+		{
+			name:           "mutating processor added",
+			reloaded:       reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a", processors: []component.ID{processorID, mutateProcessorID}},
+			keepsReceiver:  false,
+			keepsProcessor: false,
+			keepsExporter:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pg, err := Build(context.Background(), newReloadSettings(reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a", processors: []component.ID{processorID}}))
+			require.NoError(t, err)
+			require.NoError(t, pg.StartAll(context.Background(), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+			rcvr, procs, exp := tracesPipeline(t, pg)
+
+			require.NoError(t, pg.Reload(context.Background(), newReloadSettings(tt.reloaded), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+			newRcvr, newProcs, newExp := tracesPipeline(t, pg)
+
+			assert.Equal(t, tt.keepsReceiver, rcvr == newRcvr)
+			assert.Equal(t, !tt.keepsReceiver, rcvr.Stopped())
+			assert.Equal(t, tt.keepsProcessor, procs[0] == newProcs[0])
+			assert.Equal(t, !tt.keepsProcessor, procs[0].Stopped())
+			assert.Equal(t, tt.keepsExporter, exp == newExp)
+			assert.Equal(t, !tt.keepsExporter, exp.Stopped())
+			assert.True(t, newRcvr.Started())
+			for _, proc := range newProcs {
+				assert.True(t, proc.Started())
+			}
+			assert.True(t, newExp.Started())
+
+			// The data received flows through the reloaded pipeline.
+			require.NoError(t, newRcvr.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+			assert.Len(t, newExp.Traces, 1)
+
+			require.NoError(t, pg.ShutdownAll(context.Background(), statustest.NewNopStatusReporter()))
+			assert.True(t, newRcvr.Stopped())
+			assert.True(t, newExp.Stopped())
+		})
+	}
+}
+
+func TestGraphReloadSharedReceiver(t *testing.T) {
+	processorID := component.MustNewID("exampleprocessor")
+	mutateProcessorID := component.MustNewIDWithName("exampleprocessor", "mutate")
+	metricsReceiver := func(g *Graph) *testcomponents.ExampleReceiver {
+		pipeline := g.pipelines[component.MustNewID("metrics")]
+		require.Len(t, pipeline.receivers, 1)
+		for _, n := range pipeline.receivers {
+			return n.(*receiverNode).Component.(*testcomponents.ExampleReceiver)
+		}
+		return nil
+	}
+	pg, err := Build(context.Background(), newReloadSettings(reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a",
+		processors: []component.ID{processorID}, metricsPipeline: true}))
+	require.NoError(t, err)
+	require.NoError(t, pg.StartAll(context.Background(), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+	rcvr, _, _ := tracesPipeline(t, pg)
+	// The receiver is shared by the traces and the metrics pipelines.
+	require.Same(t, rcvr, metricsReceiver(pg))
+
+	// The receiver is rebuilt for the traces pipeline, as it mutates data, so it's rebuilt for the metrics one too.
+	require.NoError(t, pg.Reload(context.Background(), newReloadSettings(reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a",
+		processors: []component.ID{processorID, mutateProcessorID}, metricsPipeline: true}), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+	newRcvr, _, newExp := tracesPipeline(t, pg)
+	assert.NotSame(t, rcvr, newRcvr)
+	assert.Same(t, newRcvr, metricsReceiver(pg))
+	assert.True(t, rcvr.Stopped())
+	assert.True(t, newRcvr.Started())
+	assert.False(t, newRcvr.Stopped())
+
+	require.NoError(t, newRcvr.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	assert.Len(t, newExp.Traces, 1)
+	require.NoError(t, newRcvr.ConsumeMetrics(context.Background(), testdata.GenerateMetrics(1)))
+	for _, n := range pg.pipelines[component.MustNewID("metrics")].exporters {
+		assert.Len(t, n.(*exporterNode).Component.(*testcomponents.ExampleExporter).Metrics, 1)
+	}
+
+	require.NoError(t, pg.ShutdownAll(context.Background(), statustest.NewNopStatusReporter()))
+}
+
+func TestGraphReloadConcurrentReaders(t *testing.T) {
+	processorID := component.MustNewID("exampleprocessor")
+	pg, err := Build(context.Background(), newReloadSettings(reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a", processors: []component.ID{processorID}}))
+	require.NoError(t, err)
+	require.NoError(t, pg.StartAll(context.Background(), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+
+	// The exporters and the pipelines are read while the graph is reloaded.
+	done := make(chan struct{})
+	reading := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		close(reading)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			assert.NotEmpty(t, pg.GetExporters()[component.DataTypeTraces])
+			pg.HandleZPages(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/debug/pipelinez", nil))
+		}
+	}()
+	<-reading
+	for i := 0; i < 100; i++ {
+		endpoint := fmt.Sprintf("%d", i)
+		require.NoError(t, pg.Reload(context.Background(), newReloadSettings(reloadSettings{receiverEndpoint: endpoint, exporterEndpoint: endpoint,
+			processors: []component.ID{processorID}, secondPipeline: i%2 == 0}), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+	}
+	close(done)
+	wg.Wait()
+
+	require.NoError(t, pg.ShutdownAll(context.Background(), statustest.NewNopStatusReporter()))
+}
+
+func TestGraphReloadPipelineRemoved(t *testing.T) {
+	processorID := component.MustNewID("exampleprocessor")
+	pg, err := Build(context.Background(), newReloadSettings(reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a", processors: []component.ID{processorID}, secondPipeline: true}))
+	require.NoError(t, err)
+	require.NoError(t, pg.StartAll(context.Background(), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+	var removedExp *testcomponents.ExampleExporter
+	for _, n := range pg.pipelines[component.MustNewIDWithName("traces", "2")].exporters {
+		removedExp = n.(*exporterNode).Component.(*testcomponents.ExampleExporter)
+	}
+	require.NotNil(t, removedExp)
+
+	require.NoError(t, pg.Reload(context.Background(), newReloadSettings(reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a", processors: []component.ID{processorID}}), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+	assert.Len(t, pg.pipelines, 1)
+	assert.True(t, removedExp.Stopped())
+	assert.Empty(t, pg.GetExporters()[component.DataTypeTraces][component.MustNewIDWithName("exampleexporter", "2")])
+
+	require.NoError(t, pg.ShutdownAll(context.Background(), statustest.NewNopStatusReporter()))
+}
+
+func TestGraphReloadBuildError(t *testing.T) {
+	processorID := component.MustNewID("exampleprocessor")
+	pg, err := Build(context.Background(), newReloadSettings(reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a", processors: []component.ID{processorID}}))
+	require.NoError(t, err)
+	require.NoError(t, pg.StartAll(context.Background(), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+	rcvr, _, exp := tracesPipeline(t, pg)
+
+	// The processor is not configured, so the new graph cannot be built.
+	require.Error(t, pg.Reload(context.Background(), newReloadSettings(reloadSettings{receiverEndpoint: "b", exporterEndpoint: "b", processors: []component.ID{component.MustNewIDWithName("exampleprocessor", "missing")}}), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+
+	// The running graph is left untouched.
+	newRcvr, _, newExp := tracesPipeline(t, pg)
+	assert.Same(t, rcvr, newRcvr)
+	assert.Same(t, exp, newExp)
+	assert.False(t, rcvr.Stopped())
+	require.NoError(t, rcvr.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	assert.Len(t, exp.Traces, 1)
+
+	require.NoError(t, pg.ShutdownAll(context.Background(), statustest.NewNopStatusReporter()))
+}
+
+// startErrComponent fails to start, but shuts down.
+type startErrComponent struct {
+	errComponent
+}
+
+func (startErrComponent) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestGraphReloadStartError(t *testing.T) {
+	processorID := component.MustNewID("exampleprocessor")
+	set := newReloadSettings(reloadSettings{receiverEndpoint: "a", exporterEndpoint: "a", processors: []component.ID{processorID}})
+	pg, err := Build(context.Background(), set)
+	require.NoError(t, err)
+	require.NoError(t, pg.StartAll(context.Background(), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+
+	rcvr, procs, exp := tracesPipeline(t, pg)
+
+	// The exporter fails to start, so the reload is rolled back.
+	failingSet := newReloadSettings(reloadSettings{receiverEndpoint: "b", exporterEndpoint: "b", processors: []component.ID{processorID}})
+	failingSet.ExporterBuilder = exporter.NewBuilder(
+		map[component.ID]component.Config{component.MustNewID("exampleexporter"): &reloadConfig{Endpoint: "b"}},
+		map[component.Type]exporter.Factory{
+			testcomponents.ExampleExporterFactory.Type(): exporter.NewFactory(testcomponents.ExampleExporterFactory.Type(),
+				func() component.Config { return &reloadConfig{} },
+				exporter.WithTraces(func(context.Context, exporter.Settings, component.Config) (exporter.Traces, error) {
+					return &startErrComponent{}, nil
+				}, component.StabilityLevelDevelopment)),
+		},
+	)
+	require.Error(t, pg.Reload(context.Background(), failingSet, componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+
+	// The processor and the exporter keep running, and the receiver shut down to free its endpoint is started again.
+	newRcvr, newProcs, newExp := tracesPipeline(t, pg)
+	assert.NotSame(t, rcvr, newRcvr)
+	assert.True(t, rcvr.Stopped())
+	assert.True(t, newRcvr.Started())
+	assert.False(t, newRcvr.Stopped())
+	assert.Same(t, procs[0], newProcs[0])
+	assert.False(t, procs[0].Stopped())
+	assert.Same(t, exp, newExp)
+	assert.False(t, exp.Stopped())
+	require.NoError(t, newRcvr.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	assert.Len(t, exp.Traces, 1)
+
+	// The next reload starts from the running graph.
+	require.NoError(t, pg.Reload(context.Background(), newReloadSettings(reloadSettings{receiverEndpoint: "b", exporterEndpoint: "a", processors: []component.ID{processorID}}), componenttest.NewNopHost(), statustest.NewNopStatusReporter()))
+	rcvr, procs, newExp = tracesPipeline(t, pg)
+	assert.True(t, rcvr.Started())
+	assert.Same(t, newProcs[0], procs[0])
+	assert.Same(t, exp, newExp)
+	require.NoError(t, rcvr.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	assert.Len(t, exp.Traces, 2)
+
+	require.NoError(t, pg.ShutdownAll(context.Background(), statustest.NewNopStatusReporter()))
+}
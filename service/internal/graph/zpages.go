@@ -4,9 +4,14 @@
 package graph // import "go.opentelemetry.io/collector/service/internal/graph"
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"sort"
+	"time"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
 	"go.opentelemetry.io/collector/service/internal/zpages"
 )
 
@@ -16,6 +21,11 @@ const (
 	zPipelineName  = "pipelinenamez"
 	zComponentName = "componentnamez"
 	zComponentKind = "componentkindz"
+
+	// Form values of the queue actions
+	zExporterName = "zexportername"
+	zDataType     = "zdatatype"
+	zQueueAction  = "zqueueaction"
 )
 
 func (g *Graph) HandleZPages(w http.ResponseWriter, r *http.Request) {
@@ -27,6 +37,7 @@ func (g *Graph) HandleZPages(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	zpages.WriteHTMLPageHeader(w, zpages.HeaderData{Title: "builtPipelines"})
 
+	g.mu.RLock()
 	sumData := zpages.SummaryPipelinesTableData{}
 	sumData.Rows = make([]zpages.SummaryPipelinesTableRowData, 0, len(g.pipelines))
 	for c, p := range g.pipelines {
@@ -62,6 +73,7 @@ func (g *Graph) HandleZPages(w http.ResponseWriter, r *http.Request) {
 			Exporters:   exprIDs,
 		})
 	}
+	g.mu.RUnlock()
 	sort.Slice(sumData.Rows, func(i, j int) bool {
 		return sumData.Rows[i].FullName < sumData.Rows[j].FullName
 	})
@@ -79,3 +91,117 @@ func (g *Graph) HandleZPages(w http.ResponseWriter, r *http.Request) {
 	}
 	zpages.WriteHTMLPageFooter(w)
 }
+
+// HandleQueueZPages lists the sending queues of the exporters. If adminActions is set, it also performs the pause,
+// resume and purge actions posted from the page, otherwise the page is read-only.
+func (g *Graph) HandleQueueZPages(w http.ResponseWriter, r *http.Request, adminActions bool) {
+	handleQueueZPages(w, r, g.GetExporters(), adminActions)
+}
+
+func handleQueueZPages(w http.ResponseWriter, r *http.Request, exporters map[component.DataType]map[component.ID]component.Component,
+	adminActions bool) {
+	data := zpages.QueuesTableData{AdminActions: adminActions}
+	if r.Method == http.MethodPost {
+		if !adminActions {
+			http.Error(w, "Queue actions are disabled, they are enabled by the queue_admin_actions setting of the zpages extension.",
+				http.StatusForbidden)
+			return
+		}
+		if !isSameOrigin(r) {
+			http.Error(w, "Queue actions are only allowed from the queuez page.", http.StatusForbidden)
+			return
+		}
+		data.Message = doQueueAction(r, exporters)
+	}
+
+	for dt, exps := range exporters {
+		for id, exp := range exps {
+			inspector, ok := exp.(exporterqueue.Inspector)
+			if !ok {
+				continue
+			}
+			row := zpages.QueuesTableRowData{FullName: id.String(), DataType: dt.String()}
+			if st, ok := inspector.QueueStatus(); ok {
+				row.Enabled = true
+				row.Kind = st.Kind
+				row.Size = st.Size
+				row.Capacity = st.Capacity
+				row.Unit = st.Unit
+				row.OldestItemAge = st.OldestItemAge.Truncate(time.Millisecond).String()
+				row.Consumers = st.Consumers
+				row.Paused = st.Paused
+				row.RetryingRequests = st.RetryingRequests
+				row.CircuitBreakerState = st.CircuitBreakerState
+			}
+			data.Rows = append(data.Rows, row)
+		}
+	}
+	sort.Slice(data.Rows, func(i, j int) bool {
+		if data.Rows[i].DataType != data.Rows[j].DataType {
+			return data.Rows[i].DataType < data.Rows[j].DataType
+		}
+		return data.Rows[i].FullName < data.Rows[j].FullName
+	})
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	zpages.WriteHTMLPageHeader(w, zpages.HeaderData{Title: "Exporter Queues"})
+	zpages.WriteHTMLQueuesTable(w, data)
+	zpages.WriteHTMLPageFooter(w)
+}
+
+// isSameOrigin reports whether the request is not sent from another origin, so that the pages of other sites loaded
+// by a browser cannot perform the queue actions. The clients other than browsers usually send neither header.
+func isSameOrigin(r *http.Request) bool {
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" && site != "same-origin" && site != "none" {
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// doQueueAction performs the action posted for the queue of an exporter, and returns the message reporting the result.
+func doQueueAction(r *http.Request, exporters map[component.DataType]map[component.ID]component.Component) string {
+	name := r.PostFormValue(zExporterName)
+	dataType := r.PostFormValue(zDataType)
+	action := r.PostFormValue(zQueueAction)
+
+	var inspector exporterqueue.Inspector
+	for dt, exps := range exporters {
+		if dt.String() != dataType {
+			continue
+		}
+		for id, exp := range exps {
+			if id.String() == name {
+				inspector, _ = exp.(exporterqueue.Inspector)
+			}
+		}
+	}
+	if inspector == nil {
+		return fmt.Sprintf("Exporter %q of data type %q not found.", name, dataType)
+	}
+
+	var (
+		err    error
+		result string
+	)
+	switch action {
+	case "pause":
+		err, result = inspector.PauseQueue(), "paused"
+	case "resume":
+		err, result = inspector.ResumeQueue(), "resumed"
+	case "purge":
+		var n int
+		n, err = inspector.PurgeQueue(r.Context())
+		result = fmt.Sprintf("purged of %d requests", n)
+	default:
+		return fmt.Sprintf("Unknown queue action %q.", action)
+	}
+	if err != nil {
+		return fmt.Sprintf("Failed to %s the queue of %s (%s): %v.", action, name, dataType, err)
+	}
+	return fmt.Sprintf("Queue of %s (%s) %s.", name, dataType, result)
+}
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
+)
+
+type testInspector struct {
+	component.StartFunc
+	component.ShutdownFunc
+	status  exporterqueue.Status
+	enabled bool
+	purged  int
+}
+
+func (ti *testInspector) QueueStatus() (exporterqueue.Status, bool) {
+	return ti.status, ti.enabled
+}
+
+func (ti *testInspector) PauseQueue() error {
+	ti.status.Paused = true
+	return nil
+}
+
+func (ti *testInspector) ResumeQueue() error {
+	ti.status.Paused = false
+	return nil
+}
+
+func (ti *testInspector) PurgeQueue(context.Context) (int, error) {
+	if !ti.enabled {
+		return 0, errors.New("sending queue is not enabled")
+	}
+	ti.purged, ti.status.Size = ti.status.Size, 0
+	return ti.purged, nil
+}
+
+func TestHandleQueueZPages(t *testing.T) {
+	otlp := &testInspector{enabled: true, status: exporterqueue.Status{
+		Kind:                "persistent",
+		Size:                3,
+		Capacity:            1000,
+		Unit:                "requests",
+		OldestItemAge:       1500 * time.Millisecond,
+		Consumers:           10,
+		RetryingRequests:    2,
+		CircuitBreakerState: "open",
+	}}
+	debug := &testInspector{}
+	exporters := map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeTraces: {
+			component.MustNewID("otlp"):  otlp,
+			component.MustNewID("debug"): debug,
+			// Exporters not created with the exporterhelper are not listed.
+			component.MustNewID("nop"): struct {
+				component.StartFunc
+				component.ShutdownFunc
+			}{},
+		},
+	}
+
+	get := func() string {
+		rec := httptest.NewRecorder()
+		handleQueueZPages(rec, httptest.NewRequest(http.MethodGet, "/debug/queuez", nil), exporters, true)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+	post := func(name, action string) string {
+		form := url.Values{zExporterName: {name}, zDataType: {"traces"}, zQueueAction: {action}}
+		req := httptest.NewRequest(http.MethodPost, "/debug/queuez", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handleQueueZPages(rec, req, exporters, true)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	body := get()
+	assert.Contains(t, body, "otlp")
+	assert.Contains(t, body, "persistent")
+	assert.Contains(t, body, "3 requests")
+	assert.Contains(t, body, "1.5s")
+	assert.Contains(t, body, "open")
+	assert.Contains(t, body, `value="pause"`)
+	assert.NotContains(t, body, "nop")
+	// The exporter without a queue is listed as disabled.
+	assert.Contains(t, body, "debug")
+	assert.Contains(t, body, "disabled")
+
+	body = post("otlp", "pause")
+	assert.Contains(t, body, "Queue of otlp (traces) paused.")
+	assert.True(t, otlp.status.Paused)
+	assert.Contains(t, body, `value="resume"`)
+
+	body = post("otlp", "resume")
+	assert.Contains(t, body, "Queue of otlp (traces) resumed.")
+	assert.False(t, otlp.status.Paused)
+
+	body = post("otlp", "purge")
+	assert.Contains(t, body, "Queue of otlp (traces) purged of 3 requests.")
+	assert.Equal(t, 3, otlp.purged)
+
+	body = post("debug", "purge")
+	assert.Contains(t, body, "Failed to purge the queue of debug (traces): sending queue is not enabled.")
+
+	body = post("otlp", "drop")
+	assert.Contains(t, body, "Unknown queue action &#34;drop&#34;.")
+
+	body = post("missing", "pause")
+	assert.Contains(t, body, "Exporter &#34;missing&#34; of data type &#34;traces&#34; not found.")
+}
+
+func TestHandleQueueZPagesReadOnly(t *testing.T) {
+	otlp := &testInspector{enabled: true, status: exporterqueue.Status{Kind: "memory", Size: 3, Capacity: 1000, Unit: "requests"}}
+	exporters := map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeTraces: {component.MustNewID("otlp"): otlp},
+	}
+
+	rec := httptest.NewRecorder()
+	handleQueueZPages(rec, httptest.NewRequest(http.MethodGet, "/debug/queuez", nil), exporters, false)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "3 requests")
+	assert.NotContains(t, rec.Body.String(), "<form")
+
+	form := url.Values{zExporterName: {"otlp"}, zDataType: {"traces"}, zQueueAction: {"purge"}}
+	req := httptest.NewRequest(http.MethodPost, "/debug/queuez", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	handleQueueZPages(rec, req, exporters, false)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, 0, otlp.purged)
+	assert.Equal(t, 3, otlp.status.Size)
+}
+
+func TestHandleQueueZPagesCrossOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		allowed bool
+	}{
+		{
+			name:    "no headers",
+			allowed: true,
+		},
+		{
+			name:    "same origin",
+			headers: map[string]string{"Origin": "http://localhost:55679", "Sec-Fetch-Site": "same-origin"},
+			allowed: true,
+		},
+		{
+			name:    "other origin",
+			headers: map[string]string{"Origin": "http://example.com"},
+		},
+		{
+			name:    "opaque origin",
+			headers: map[string]string{"Origin": "null"},
+		},
+		{
+			name:    "cross site",
+			headers: map[string]string{"Sec-Fetch-Site": "cross-site"},
+		},
+		{
+			name:    "same site",
+			headers: map[string]string{"Origin": "http://localhost:55679", "Sec-Fetch-Site": "same-site"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			otlp := &testInspector{enabled: true, status: exporterqueue.Status{Kind: "memory", Size: 3}}
+			exporters := map[component.DataType]map[component.ID]component.Component{
+				component.DataTypeTraces: {component.MustNewID("otlp"): otlp},
+			}
+			form := url.Values{zExporterName: {"otlp"}, zDataType: {"traces"}, zQueueAction: {"purge"}}
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:55679/debug/queuez", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handleQueueZPages(rec, req, exporters, true)
+			if tt.allowed {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, 3, otlp.purged)
+				return
+			}
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+			assert.Equal(t, 0, otlp.purged)
+		})
+	}
+}
@@ -67,7 +67,7 @@ func createReceiver(cfg component.Config) *ExampleReceiver {
 	// Check to see if there is already a receiver for this config.
 	er, ok := exampleReceivers[cfg]
 	if !ok {
-		er = &ExampleReceiver{}
+		er = &ExampleReceiver{cfg: cfg}
 		// Remember the receiver in the map
 		exampleReceivers[cfg] = er
 	}
@@ -81,6 +81,15 @@ type ExampleReceiver struct {
 	consumer.ConsumeTracesFunc
 	consumer.ConsumeMetricsFunc
 	consumer.ConsumeLogsFunc
+	cfg component.Config
+}
+
+// Shutdown stops the receiver and forgets it, so that the next one created for the config is a new receiver.
+func (er *ExampleReceiver) Shutdown(ctx context.Context) error {
+	if exampleReceivers[er.cfg] == er {
+		delete(exampleReceivers, er.cfg)
+	}
+	return er.componentState.Shutdown(ctx)
 }
 
 // This is the map of already created example receivers for particular configurations.
@@ -37,6 +37,10 @@ var (
 	pipelinesTableBytes    []byte
 	pipelinesTableTemplate = parseTemplate("pipelines_table", pipelinesTableBytes)
 
+	//go:embed templates/queues_table.html
+	queuesTableBytes    []byte
+	queuesTableTemplate = parseTemplate("queues_table", queuesTableBytes)
+
 	//go:embed templates/properties_table.html
 	propertiesTableBytes    []byte
 	propertiesTableTemplate = parseTemplate("properties_table", propertiesTableBytes)
@@ -104,6 +108,39 @@ func WriteHTMLPipelinesSummaryTable(w io.Writer, spd SummaryPipelinesTableData)
 	}
 }
 
+// QueuesTableData contains data for the exporter queues table template.
+type QueuesTableData struct {
+	// Message is the result of the last action, shown above the table.
+	Message string
+	// AdminActions is set if the queues can be paused, resumed and purged from the page.
+	AdminActions bool
+	Rows         []QueuesTableRowData
+}
+
+// QueuesTableRowData contains data for one row in the exporter queues table template.
+type QueuesTableRowData struct {
+	FullName            string
+	DataType            string
+	Enabled             bool
+	Kind                string
+	Size                int
+	Capacity            int
+	Unit                string
+	OldestItemAge       string
+	Consumers           int
+	Paused              bool
+	RetryingRequests    int64
+	CircuitBreakerState string
+}
+
+// WriteHTMLQueuesTable writes the table of the exporter queues along with the actions to administer them, if enabled.
+// It does not write the header or footer.
+func WriteHTMLQueuesTable(w io.Writer, qtd QueuesTableData) {
+	if err := queuesTableTemplate.Execute(w, qtd); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
 // ComponentHeaderData contains data for component header template.
 type ComponentHeaderData struct {
 	Name              string
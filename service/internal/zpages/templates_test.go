@@ -66,6 +66,15 @@ func TestNoCrash(t *testing.T) {
 			}},
 		})
 	})
+	assert.NotPanics(t, func() {
+		WriteHTMLQueuesTable(buf, QueuesTableData{
+			Message: "Paused",
+			Rows: []QueuesTableRowData{
+				{FullName: "otlp", DataType: "traces", Enabled: true, Kind: "memory", Size: 1, Capacity: 10, Unit: "requests", Paused: true},
+				{FullName: "debug", DataType: "logs"},
+			},
+		})
+	})
 	assert.NotPanics(t, func() {
 		WriteHTMLPropertiesTable(buf, PropertiesTableData{Name: "Bar", Properties: [][2]string{{"key", "value"}}})
 	})
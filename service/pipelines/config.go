@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentprofiles"
 )
 
 var (
@@ -28,6 +29,10 @@ func (cfg Config) Validate() error {
 	// Check that all pipelines have at least one receiver and one exporter, and they reference
 	// only configured components.
 	for pipelineID, pipeline := range cfg {
+		// The components may support profiles, but the pipelines don't yet.
+		if pipelineID.Type() == componentprofiles.DataTypeProfiles {
+			return fmt.Errorf("pipeline %q: profiles pipelines are not supported yet", pipelineID)
+		}
 		if pipelineID.Type() != component.DataTypeTraces && pipelineID.Type() != component.DataTypeMetrics && pipelineID.Type() != component.DataTypeLogs {
 			return fmt.Errorf("pipeline %q: unknown datatype %q", pipelineID, pipelineID.Type())
 		}
@@ -72,6 +72,18 @@ func TestConfigValidate(t *testing.T) {
 			},
 			expected: errors.New(`pipeline "wrongtype": unknown datatype "wrongtype"`),
 		},
+		{
+			name: "unsupported-profiles-pipeline",
+			cfgFn: func() Config {
+				cfg := generateConfig()
+				cfg[component.MustNewID("profiles")] = &PipelineConfig{
+					Receivers: []component.ID{component.MustNewID("nop")},
+					Exporters: []component.ID{component.MustNewID("nop")},
+				}
+				return cfg
+			},
+			expected: errors.New(`pipeline "profiles": profiles pipelines are not supported yet`),
+		},
 	}
 
 	for _, test := range testCases {
@@ -12,7 +12,9 @@ import (
 	"runtime"
 
 	"go.opentelemetry.io/otel/metric"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	nooptrace "go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
@@ -29,6 +31,7 @@ import (
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/service/extensions"
 	"go.opentelemetry.io/collector/service/internal/graph"
+	"go.opentelemetry.io/collector/service/internal/metadata"
 	"go.opentelemetry.io/collector/service/internal/proctelemetry"
 	"go.opentelemetry.io/collector/service/internal/resource"
 	"go.opentelemetry.io/collector/service/internal/status"
@@ -71,10 +74,20 @@ type Service struct {
 	telemetrySettings component.TelemetrySettings
 	host              *serviceHost
 	collectorConf     *confmap.Conf
+	telemetryBuilder  *metadata.TelemetryBuilder
 
 	reporter status.Reporter
+	// configReported is set once the configuration started reporting its status, by the first failed reload.
+	configReported bool
+	// reloadFailed is set when a failed reload was reported, until a reload succeeds.
+	reloadFailed bool
 }
 
+// configInstanceID is the instance the status of the configuration reloads is reported for, as the reloads which
+// fail before the components are built cannot be attributed to a component. It is reported as an extension, the
+// kind of the instances which belong to no pipeline, so that the status watchers aggregate it with the extensions.
+var configInstanceID = &component.InstanceID{ID: component.MustNewID("config"), Kind: component.KindExtension}
+
 // New creates a new Service, its telemetry, and Components.
 func New(ctx context.Context, set Settings, cfg Config) (*Service, error) {
 	disableHighCard := obsreportconfig.DisableHighCardinalityMetricsfeatureGate.IsEnabled()
@@ -136,6 +149,9 @@ func New(ctx context.Context, set Settings, cfg Config) (*Service, error) {
 		// Construct telemetry attributes from build info and config's resource attributes.
 		Resource: pcommonRes,
 	}
+	if srv.telemetryBuilder, err = metadata.NewTelemetryBuilder(srv.telemetrySettings); err != nil {
+		return nil, fmt.Errorf("failed to create telemetry builder: %w", err)
+	}
 	srv.reporter = status.NewReporter(srv.host.notifyComponentStatusChange, func(err error) {
 		if errors.Is(err, status.ErrStatusNotReady) {
 			logger.Warn("Invalid transition", zap.Error(err))
@@ -223,6 +239,85 @@ func (srv *Service) Start(ctx context.Context) error {
 	return nil
 }
 
+// Reload updates the pipelines of the service to the settings and configuration, restarting only the components
+// which changed, and notifies the extensions about the new Collector configuration. The telemetry and the
+// extensions of the service are not reloaded: if their configuration changed, the service must be shut down
+// and a new one started instead.
+func (srv *Service) Reload(ctx context.Context, set Settings, cfg Config) error {
+	srv.telemetrySettings.Logger.Info("Reloading pipelines...")
+
+	// The factories of the components are the same, only the pipelines are rebuilt with the new builders.
+	if err := srv.host.pipelines.Reload(ctx, graph.Settings{
+		Telemetry:        srv.telemetrySettings,
+		BuildInfo:        srv.buildInfo,
+		ReceiverBuilder:  set.Receivers,
+		ProcessorBuilder: set.Processors,
+		ExporterBuilder:  set.Exporters,
+		ConnectorBuilder: set.Connectors,
+		PipelineConfigs:  cfg.Pipelines,
+		ReportStatus:     srv.reporter.ReportStatus,
+	}, srv.host, srv.reporter); err != nil {
+		return fmt.Errorf("cannot reload pipelines: %w", err)
+	}
+
+	srv.collectorConf = set.CollectorConf
+	if srv.collectorConf != nil {
+		if err := srv.host.serviceExtensions.NotifyConfig(ctx, srv.collectorConf); err != nil {
+			return err
+		}
+	}
+
+	if srv.reloadFailed {
+		srv.reloadFailed = false
+		srv.reporter.ReportStatus(configInstanceID, component.NewStatusEvent(component.StatusOK))
+	}
+	srv.telemetrySettings.Logger.Info("Pipelines reloaded.")
+	return nil
+}
+
+// ReportReloadFailure reports that reloading the configuration failed, and that the service keeps running with the
+// last known-good configuration. The failure is reported as a recoverable error status of the configuration, which
+// is reported OK again by the next successful reload.
+func (srv *Service) ReportReloadFailure(ctx context.Context, err error) {
+	srv.telemetrySettings.Logger.Error("Failed to reload the configuration, keep running the last known-good configuration", zap.Error(err))
+	srv.telemetryBuilder.ConfigReloadFailures.Add(ctx, 1)
+	if !srv.configReported {
+		srv.configReported = true
+		srv.reporter.ReportStatus(configInstanceID, component.NewStatusEvent(component.StatusStarting))
+	}
+	srv.reloadFailed = true
+	srv.reporter.ReportStatus(configInstanceID, component.NewRecoverableErrorEvent(err))
+}
+
+// Validate verifies that the pipelines of the configuration can be built, creating their components without
+// starting them. The created components are shut down.
+func Validate(ctx context.Context, set Settings, cfg Config) error {
+	tel := component.TelemetrySettings{
+		Logger:         zap.NewNop(),
+		TracerProvider: nooptrace.NewTracerProvider(),
+		MeterProvider:  noopmetric.NewMeterProvider(),
+		MetricsLevel:   configtelemetry.LevelNone,
+		Resource:       pcommon.NewResource(),
+		ReportStatus:   func(*component.StatusEvent) {},
+	}
+	reportStatus := func(*component.InstanceID, *component.StatusEvent) {}
+	pipelines, err := graph.Build(ctx, graph.Settings{
+		Telemetry:        tel,
+		BuildInfo:        set.BuildInfo,
+		ReceiverBuilder:  set.Receivers,
+		ProcessorBuilder: set.Processors,
+		ExporterBuilder:  set.Exporters,
+		ConnectorBuilder: set.Connectors,
+		PipelineConfigs:  cfg.Pipelines,
+		ReportStatus:     reportStatus,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build pipelines: %w", err)
+	}
+	// The components are shut down, so that the shared ones are released.
+	return pipelines.ShutdownAll(ctx, status.NewReporter(reportStatus, func(error) {}))
+}
+
 func (srv *Service) shutdownTelemetry(ctx context.Context) error {
 	// The metric.MeterProvider and trace.TracerProvider interfaces do not have a Shutdown method.
 	// To shutdown the providers we try to cast to this interface, which matches the type signature used in the SDK.
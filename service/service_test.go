@@ -237,6 +237,71 @@ func TestServiceGetExporters(t *testing.T) {
 	assert.Contains(t, expMap[component.DataTypeLogs], component.NewID(nopType))
 }
 
+func TestServiceReload(t *testing.T) {
+	srv, err := New(context.Background(), newNopSettings(), newNopConfig())
+	require.NoError(t, err)
+
+	assert.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	cfg := newNopConfigPipelineConfigs(pipelines.Config{
+		component.MustNewID("traces"): {
+			Receivers: []component.ID{component.NewID(nopType)},
+			Exporters: []component.ID{component.NewID(nopType)},
+		},
+	})
+	require.NoError(t, srv.Reload(context.Background(), newNopSettings(), cfg))
+
+	expMap := srv.host.GetExporters()
+	assert.Len(t, expMap[component.DataTypeTraces], 1)
+	assert.Empty(t, expMap[component.DataTypeMetrics])
+	assert.Empty(t, expMap[component.DataTypeLogs])
+}
+
+func TestServiceReloadInvalidConfig(t *testing.T) {
+	srv, err := New(context.Background(), newNopSettings(), newNopConfig())
+	require.NoError(t, err)
+
+	assert.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	invalidCfg := newNopConfig()
+	invalidCfg.Pipelines[component.MustNewID("traces")].Processors[0] = component.MustNewID("invalid")
+	require.Error(t, srv.Reload(context.Background(), newNopSettings(), invalidCfg))
+
+	// The running pipelines are left untouched.
+	assert.Len(t, srv.host.GetExporters(), 3)
+}
+
+func TestServiceReportReloadFailure(t *testing.T) {
+	// The process metrics are not registered without a metrics address.
+	cfg := newNopConfig()
+	cfg.Telemetry.Metrics.Address = ""
+	srv, err := New(context.Background(), newNopSettings(), cfg)
+	require.NoError(t, err)
+
+	assert.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	// The failures are recorded regardless of the process metrics.
+	require.NotNil(t, srv.telemetryBuilder)
+	srv.ReportReloadFailure(context.Background(), errors.New("failed to start"))
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(context.Background(), newNopSettings(), newNopConfig()))
+
+	invalidCfg := newNopConfig()
+	invalidCfg.Pipelines[component.MustNewID("traces")].Processors[0] = component.MustNewID("invalid")
+	require.Error(t, Validate(context.Background(), newNopSettings(), invalidCfg))
+}
+
 // TestServiceTelemetryCleanupOnError tests that if newService errors due to an invalid config telemetry is cleaned up
 // and another service with a valid config can be started right after.
 func TestServiceTelemetryCleanupOnError(t *testing.T) {
@@ -517,6 +582,7 @@ func assertZPages(t *testing.T, zpagesAddr string) {
 		"/debug/pipelinez",
 		"/debug/servicez",
 		"/debug/extensionz",
+		"/debug/queuez",
 	}
 
 	testZPagePathFn := func(t *testing.T, path string) {
@@ -20,6 +20,7 @@ const (
 	zPipelinePath  = "pipelinez"
 	zExtensionPath = "extensionz"
 	zFeaturePath   = "featurez"
+	zQueuePath     = "queuez"
 )
 
 var (
@@ -37,11 +38,25 @@ func init() {
 	}
 }
 
+// RegisterZPages registers the zPages of the host. The queuez page is read-only.
 func (host *serviceHost) RegisterZPages(mux *http.ServeMux, pathPrefix string) {
+	host.registerZPages(mux, pathPrefix, false)
+}
+
+// RegisterZPagesWithQueueAdminActions registers the zPages of the host, allowing the sending queues of the exporters
+// to be paused, resumed and purged from the queuez page.
+func (host *serviceHost) RegisterZPagesWithQueueAdminActions(mux *http.ServeMux, pathPrefix string) {
+	host.registerZPages(mux, pathPrefix, true)
+}
+
+func (host *serviceHost) registerZPages(mux *http.ServeMux, pathPrefix string, queueAdminActions bool) {
 	mux.HandleFunc(path.Join(pathPrefix, zServicePath), host.zPagesRequest)
 	mux.HandleFunc(path.Join(pathPrefix, zPipelinePath), host.pipelines.HandleZPages)
 	mux.HandleFunc(path.Join(pathPrefix, zExtensionPath), host.serviceExtensions.HandleZPages)
 	mux.HandleFunc(path.Join(pathPrefix, zFeaturePath), handleFeaturezRequest)
+	mux.HandleFunc(path.Join(pathPrefix, zQueuePath), func(w http.ResponseWriter, r *http.Request) {
+		host.pipelines.HandleQueueZPages(w, r, queueAdminActions)
+	})
 }
 
 func (host *serviceHost) zPagesRequest(w http.ResponseWriter, _ *http.Request) {
@@ -59,6 +74,11 @@ func (host *serviceHost) zPagesRequest(w http.ResponseWriter, _ *http.Request) {
 		ComponentEndpoint: zExtensionPath,
 		Link:              true,
 	})
+	zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+		Name:              "Exporter Queues",
+		ComponentEndpoint: zQueuePath,
+		Link:              true,
+	})
 	zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
 		Name:              "Features",
 		ComponentEndpoint: zFeaturePath,